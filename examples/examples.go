@@ -0,0 +1,197 @@
+// Package examples provides compiled example handlers built on the go-rps packages: a paginated list
+// endpoint, error handling with FromError and presets, content negotiation and NDJSON streaming.
+// The handlers are exercised by the package's Example functions and tests, so that they keep compiling
+// and behaving as documented as the API evolves.
+package examples
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// Product is the resource served by the example handlers.
+type Product struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// ErrNotFound is returned by Store when a product does not exist.
+var ErrNotFound = errors.New("product not found")
+
+// Store is an in-memory product store.
+type Store struct {
+	Products []Product
+}
+
+// Get returns the product with the given ID, or ErrNotFound.
+func (store *Store) Get(_ context.Context, id string) (Product, error) {
+
+	for _, product := range store.Products {
+		if product.ID == id {
+			return product, nil
+		}
+	}
+
+	return Product{}, ErrNotFound
+}
+
+func init() {
+	httpresponse.RegisterErrorTranslation(func(err error) bool { return errors.Is(err, ErrNotFound) }, "404", "The product does not exist.")
+}
+
+// build builds an envelope within the request's context, logging how long each option took when debug
+// mode is enabled.
+func build[D any](ctx context.Context, builder *httpresponse.HTTPResponseBuilder[int, D, map[string]any, int]) (*httpresponse.HTTPResponseOptions[int, D, map[string]any, int], error) {
+
+	lister := rpsutil.Lister[httpresponse.HTTPResponseOptions[int, D, map[string]any, int]](builder)
+
+	if httpresponse.Debug() {
+		lister = rpsutil.Map(lister, func(next func(*httpresponse.HTTPResponseOptions[int, D, map[string]any, int]) error) func(*httpresponse.HTTPResponseOptions[int, D, map[string]any, int]) error {
+			return func(args *httpresponse.HTTPResponseOptions[int, D, map[string]any, int]) error {
+				start := time.Now()
+				defer func() { log.Printf("examples: option applied in %v", time.Since(start)) }()
+				return next(args)
+			}
+		})
+	}
+
+	return rpsutil.BuildContext(ctx, lister)
+}
+
+// write builds the envelope of builder and serves it, falling back to a generic error envelope if the
+// build fails.
+func write[D any](w http.ResponseWriter, r *http.Request, builder *httpresponse.HTTPResponseBuilder[int, D, map[string]any, int]) {
+
+	response, err := build(r.Context(), builder)
+	if err != nil {
+		response, _ = build(r.Context(), httpresponse.FromError[int, D, map[string]any, int](err))
+	}
+
+	if err := httpresponse.ServeJSON(w, r, response); err != nil {
+		log.Printf("examples: write response: %v", err)
+	}
+}
+
+// ListHandler serves the products of store, filtered by "status", sorted by "name" or "id" and paginated
+// with "offset" and "per_page" (at most 2 per page). The applied query is echoed under "query".
+func ListHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		query, params := httpresponse.ApplyListParams(r, []string{"status"}, []string{"name", "id"}, 2)
+
+		products := make([]Product, 0, len(store.Products))
+		for _, product := range store.Products {
+			if status, ok := query.Filters["status"]; !ok || product.Status == status {
+				products = append(products, product)
+			}
+		}
+
+		for i := len(query.Sort) - 1; i >= 0; i-- {
+			field := query.Sort[i]
+			sort.SliceStable(products, func(a, b int) bool {
+				x, y := products[a].ID, products[b].ID
+				if field.Field == "name" {
+					x, y = products[a].Name, products[b].Name
+				}
+				if field.Desc {
+					return x > y
+				}
+				return x < y
+			})
+		}
+
+		write(w, r, httpresponse.HTTPResponse[int, []Product, map[string]any, int]().
+			SetCode(http.StatusOK).
+			SetData(products).
+			SetTotal(len(products)).
+			SetAppliedQuery(query).
+			PaginateParams(params, r.URL.String()))
+	}
+}
+
+// GetHandler serves the product whose ID is the "id" query parameter. Missing IDs are rejected with the
+// UnprocessableEntity preset, and store errors are translated with FromError, so that unknown products
+// yield a 404 envelope without leaking internal messages.
+func GetHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			write(w, r, httpresponse.UnprocessableEntity[int, *Product, map[string]any, int]([]httpresponse.FieldError{
+				{Field: "id", Code: "required", Message: "The product ID is required."},
+			}))
+			return
+		}
+
+		product, err := store.Get(r.Context(), id)
+		if err != nil {
+			write(w, r, httpresponse.FromError[int, *Product, map[string]any, int](err))
+			return
+		}
+
+		write(w, r, httpresponse.HTTPResponse[int, *Product, map[string]any, int]().
+			SetCode(http.StatusOK).
+			SetData(&product).
+			CachePrivate(time.Minute))
+	}
+}
+
+// ExportHandler streams every product of store as newline-delimited JSON, one raw product per line.
+func ExportHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		stream(w, r, store)
+	}
+}
+
+// NegotiateHandler serves the products of store as NDJSON to clients accepting "application/x-ndjson",
+// and as a JSON envelope otherwise. The response varies by the Accept header.
+func NegotiateHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Add("Vary", "Accept")
+
+		if strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+			ExportHandler(store)(w, r)
+			return
+		}
+
+		write(w, r, httpresponse.HTTPResponse[int, []Product, map[string]any, int]().
+			SetCode(http.StatusOK).
+			SetData(store.Products).
+			SetTotal(len(store.Products)))
+	}
+}
+
+// stream writes the products of store to w as NDJSON.
+func stream(w http.ResponseWriter, r *http.Request, store *Store) {
+
+	items := make(chan Product)
+	go func() {
+		defer close(items)
+		for _, product := range store.Products {
+			select {
+			case items <- product:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	if err := httpresponse.HTTPResponse[int, Product, map[string]any, int]().StreamNDJSON(w, items, false); err != nil {
+		log.Printf("examples: stream products: %v", err)
+		// Drain the producer, so that it does not leak
+		for range items {
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package examples_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/examples"
+	"github.com/zeroxsolutions/go-rps/rpstest"
+)
+
+// newStore returns the store shared by the examples.
+func newStore() *examples.Store {
+	return &examples.Store{Products: []examples.Product{
+		{ID: "1", Name: "widget", Status: "active"},
+		{ID: "2", Name: "gadget", Status: "active"},
+		{ID: "3", Name: "doohickey", Status: "retired"},
+		{ID: "4", Name: "gizmo", Status: "active"},
+	}}
+}
+
+// serve serves the request to handler and returns the recorded response.
+func serve(handler http.HandlerFunc, target string, header http.Header) *httptest.ResponseRecorder {
+
+	r := httptest.NewRequest("GET", target, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, r)
+
+	return recorder
+}
+
+func ExampleListHandler() {
+	recorder := serve(examples.ListHandler(newStore()), "/products?status=active&sort=name&per_page=5", nil)
+
+	fmt.Println(recorder.Code)
+	fmt.Println(recorder.Body.String())
+	// Output:
+	// 200
+	// {"_links":{"next":"/products?offset=2\u0026per_page=5\u0026sort=name\u0026status=active"},"code":200,"data":[{"id":"2","name":"gadget","status":"active"},{"id":"4","name":"gizmo","status":"active"}],"message":"","query":{"filters":{"status":"active"},"sort":[{"field":"name"}],"clamped":["per_page"]},"success":true,"total":3}
+}
+
+func ExampleGetHandler() {
+	recorder := serve(examples.GetHandler(newStore()), "/products?id=42", nil)
+
+	fmt.Println(recorder.Code)
+	fmt.Println(recorder.Body.String())
+	// Output:
+	// 404
+	// {"code":404,"message":"The product does not exist.","success":false}
+}
+
+func ExampleExportHandler() {
+	recorder := serve(examples.ExportHandler(newStore()), "/products/export", nil)
+
+	fmt.Print(recorder.Body.String())
+	// Output:
+	// {"id":"1","name":"widget","status":"active"}
+	// {"id":"2","name":"gadget","status":"active"}
+	// {"id":"3","name":"doohickey","status":"retired"}
+	// {"id":"4","name":"gizmo","status":"active"}
+}
+
+// TestListHandler tests the pagination of the list endpoint.
+func TestListHandler(t *testing.T) {
+	recorder := serve(examples.ListHandler(newStore()), "/products?offset=2", nil)
+
+	rpstest.Expect().Success().Code(200).Total(4).DataLen(2).Field("data.0.id", "3").ExtraHas("_links").Match(t, recorder)
+
+	// Invalid offsets are clamped by ApplyListParams rather than failing the request
+	for _, offset := range []string{"-1", "abc", "9223372036854775807"} {
+		recorder := serve(examples.ListHandler(newStore()), "/products?offset="+offset, nil)
+		rpstest.Expect().Success().Code(200).Total(4).Match(t, recorder)
+	}
+}
+
+// TestGetHandler tests the success, validation failure and not found paths of the get endpoint.
+func TestGetHandler(t *testing.T) {
+	handler := examples.GetHandler(newStore())
+
+	recorder := serve(handler, "/products?id=1", nil)
+	rpstest.Expect().Success().Code(200).Field("data.name", "widget").Match(t, recorder)
+	if cacheControl := recorder.Header().Get("Cache-Control"); cacheControl != "private, max-age=60" {
+		t.Errorf("Expected a private cache policy, got %q", cacheControl)
+	}
+
+	rpstest.Expect().Failure().Code(422).Field("errors.0.field", "id").Match(t, serve(handler, "/products", nil))
+	rpstest.Expect().Failure().Code(404).Match(t, serve(handler, "/products?id=42", nil))
+}
+
+// TestNegotiateHandler tests that the representation follows the Accept header.
+func TestNegotiateHandler(t *testing.T) {
+	handler := examples.NegotiateHandler(newStore())
+
+	recorder := serve(handler, "/products", nil)
+	rpstest.Expect().Success().DataLen(4).Match(t, recorder)
+	if vary := recorder.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("Expected Vary Accept, got %q", vary)
+	}
+
+	recorder = serve(handler, "/products", http.Header{"Accept": {"application/x-ndjson"}})
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON, got %q", contentType)
+	}
+	if lines := strings.Count(recorder.Body.String(), "\n"); lines != 4 {
+		t.Errorf("Expected 4 lines, got %d", lines)
+	}
+}
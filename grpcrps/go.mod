@@ -0,0 +1,14 @@
+module github.com/zeroxsolutions/go-rps/grpcrps
+
+go 1.18
+
+replace github.com/zeroxsolutions/go-rps => ../
+
+require (
+	github.com/zeroxsolutions/go-rps v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230711160842-782d3b101e98
+	google.golang.org/grpc v1.58.3
+	google.golang.org/protobuf v1.31.0
+)
+
+require github.com/golang/protobuf v1.5.3 // indirect
@@ -0,0 +1,119 @@
+// Package grpcrps converts gRPC replies and errors into httpresponse envelopes, such as in gRPC gateways.
+// It lives in its own module so that the gRPC and Protocol Buffers dependencies stay optional for users of
+// the core packages.
+package grpcrps
+
+import (
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// KeyGRPCCode is the Extra key under which FromGRPC stores the name of the gRPC status code of an error.
+const KeyGRPCCode = "grpc_code"
+
+// init registers protojson as the encoding of proto.Message Data, so that replies follow the Protocol Buffers
+// JSON mapping used by gRPC gateways: 64-bit integers as strings, enums by name, oneofs by their set field and
+// well-known types such as Timestamp, Duration and Any in their dedicated forms.
+func init() {
+	httpresponse.RegisterDataMarshaler(func(m proto.Message) ([]byte, error) {
+		return protojson.Marshal(m)
+	})
+}
+
+// FromGRPC initializes a builder for the HTTP envelope of a gRPC call. On success, resp is the Data of the
+// envelope, encoded with protojson. On error, the response fails with the HTTP status mapped from the gRPC
+// status code of err as its code (see HTTPStatusFromCode), the status message as its message, the name of the gRPC code under
+// the "grpc_code" Extra key and, if the status details include a google.rpc.ErrorInfo, its reason as the
+// sub-code, the gRPC counterpart of the fine-grained code. The messages of Unknown, Internal and DataLoss statuses may carry internal
+// details, so they are replaced with httpresponse.GenericErrorMessage; errors that are not gRPC statuses
+// are handled by httpresponse.FromError.
+//
+// Parameters:
+//   - resp: The reply of the call; ignored if err is not nil.
+//   - err: The error of the call.
+//
+// Returns:
+//   - *httpresponse.HTTPResponseBuilder: A builder describing the response, to which further setters can be chained.
+func FromGRPC(resp proto.Message, err error) *httpresponse.HTTPResponseBuilder[int, proto.Message, map[string]any, int] {
+
+	if err == nil {
+		return httpresponse.HTTPResponse[int, proto.Message, map[string]any, int]().
+			SetCode(http.StatusOK).
+			SetData(resp)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return httpresponse.FromError[int, proto.Message, map[string]any, int](err)
+	}
+
+	message := st.Message()
+	switch st.Code() {
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		message = httpresponse.GenericErrorMessage
+	}
+
+	return httpresponse.HTTPResponse[int, proto.Message, map[string]any, int]().
+		SetSuccess(false).
+		SetCode(HTTPStatusFromCode(st.Code())).
+		SetSubCode(errorReason(st)).
+		SetMessage(message).
+		SetExtra(map[string]any{KeyGRPCCode: st.Code().String()})
+}
+
+// errorReason returns the reason of the first google.rpc.ErrorInfo among the details of st, or "" if none.
+func errorReason(st *status.Status) string {
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
+
+// HTTPStatusFromCode maps a gRPC status code to the HTTP status code conventionally used for it, as gRPC
+// gateways do. Unknown codes map to 500.
+//
+// Parameters:
+//   - code: The gRPC status code.
+//
+// Returns:
+//   - int: The HTTP status code.
+func HTTPStatusFromCode(code codes.Code) int {
+
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request, as used by nginx and gRPC gateways
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,136 @@
+package grpcrps_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/zeroxsolutions/go-rps/grpcrps"
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// grpcResponse is the response type built by FromGRPC.
+type grpcResponse = httpresponse.HTTPResponseOptions[int, proto.Message, map[string]any, int]
+
+// TestFromGRPC_Success tests that a reply becomes the data of a successful envelope.
+func TestFromGRPC_Success(t *testing.T) {
+	reply := wrapperspb.String("hello")
+
+	response, err := rpsutil.Build[grpcResponse](grpcrps.FromGRPC(reply, nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Code != http.StatusOK || response.Data != reply {
+		t.Errorf("Expected a 200 success carrying the reply, got %+v", response)
+	}
+}
+
+// TestFromGRPC_ProtoJSON tests that the reply is encoded with the Protocol Buffers JSON mapping, which writes
+// 64-bit integers as strings, rather than with encoding/json.
+func TestFromGRPC_ProtoJSON(t *testing.T) {
+	response, err := rpsutil.Build[grpcResponse](grpcrps.FromGRPC(wrapperspb.Int64(5), nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(string(body), `"data":"5"`) {
+		t.Errorf("Expected the data to be \"5\", got %s", body)
+	}
+}
+
+// TestFromGRPC_Error tests that a gRPC status maps to the HTTP code, message and gRPC code name.
+func TestFromGRPC_Error(t *testing.T) {
+	response, err := rpsutil.Build[grpcResponse](grpcrps.FromGRPC(nil, status.Error(codes.NotFound, "user 42 not found")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success || response.Code != http.StatusNotFound || response.Message != "user 42 not found" {
+		t.Errorf("Expected a failed 404 with the status message, got %+v", response)
+	}
+	if response.Extra[grpcrps.KeyGRPCCode] != "NotFound" {
+		t.Errorf("Expected the gRPC code NotFound, got %v", response.Extra[grpcrps.KeyGRPCCode])
+	}
+	if response.Data != nil {
+		t.Errorf("Expected no data, got %v", response.Data)
+	}
+}
+
+// TestFromGRPC_InternalError tests that internal statuses and plain errors do not leak their messages.
+func TestFromGRPC_InternalError(t *testing.T) {
+	for _, callErr := range []error{
+		status.Error(codes.Internal, "db password rejected"),
+		errors.New("dial tcp 10.0.0.1:5432: refused"),
+	} {
+		response, err := rpsutil.Build[grpcResponse](grpcrps.FromGRPC(nil, callErr))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Success || response.Message != httpresponse.GenericErrorMessage || response.StatusCode() != http.StatusInternalServerError {
+			t.Errorf("Expected a generic 500 for %v, got %+v", callErr, response)
+		}
+	}
+}
+
+// TestHTTPStatusFromCode tests the mapping of common gRPC codes.
+func TestHTTPStatusFromCode(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:                http.StatusOK,
+		codes.InvalidArgument:   http.StatusBadRequest,
+		codes.Unauthenticated:   http.StatusUnauthorized,
+		codes.PermissionDenied:  http.StatusForbidden,
+		codes.ResourceExhausted: http.StatusTooManyRequests,
+		codes.Unavailable:       http.StatusServiceUnavailable,
+		codes.Code(99):          http.StatusInternalServerError,
+	}
+
+	for code, want := range tests {
+		if got := grpcrps.HTTPStatusFromCode(code); got != want {
+			t.Errorf("Expected %d for %v, got %d", want, code, got)
+		}
+	}
+}
+
+// TestFromGRPC_ErrorInfo tests that the reason of an ErrorInfo detail becomes the sub-code.
+func TestFromGRPC_ErrorInfo(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "email already registered").
+		WithDetails(&errdetails.ErrorInfo{Reason: "EMAIL_TAKEN", Domain: "users.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := rpsutil.Build[grpcResponse](grpcrps.FromGRPC(nil, st.Err()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Code != http.StatusBadRequest || response.SubCode != "EMAIL_TAKEN" {
+		t.Errorf("Expected a 400 with sub-code EMAIL_TAKEN, got %+v", response)
+	}
+	if response.Extra[grpcrps.KeyGRPCCode] != "InvalidArgument" {
+		t.Errorf("Expected the gRPC code InvalidArgument, got %v", response.Extra[grpcrps.KeyGRPCCode])
+	}
+
+	// Statuses without an ErrorInfo have no sub-code
+	response, err = rpsutil.Build[grpcResponse](grpcrps.FromGRPC(nil, status.Error(codes.NotFound, "gone")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.SubCode != "" {
+		t.Errorf("Expected no sub-code, got %q", response.SubCode)
+	}
+}
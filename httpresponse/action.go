@@ -0,0 +1,90 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeyAction is the envelope key under which the recovery action of an error response is encoded.
+const KeyAction = "action"
+
+// Built-in recovery actions.
+const (
+	ActionRetry          = "retry"
+	ActionReauthenticate = "reauthenticate"
+	ActionContactSupport = "contact_support"
+)
+
+// ErrUnknownAction is returned when building a response whose action has not been registered.
+var ErrUnknownAction = errors.New("httpresponse: unknown action")
+
+// Action is a recovery hint carried by an error response.
+type Action struct {
+	Name   string         `json:"name"`             // The registered name of the action, such as "retry".
+	Params map[string]any `json:"params,omitempty"` // Parameters of the action, such as "retry_after".
+}
+
+var (
+	actionsMu sync.RWMutex
+	actions   = map[string]bool{
+		ActionRetry:          true,
+		ActionReauthenticate: true,
+		ActionContactSupport: true,
+	}
+)
+
+// RegisterAction adds name to the actions accepted by SetAction, typically from an init function.
+// The built-in actions "retry", "reauthenticate" and "contact_support" are always registered.
+//
+// Parameters:
+//   - name: The action name clients are expected to understand.
+func RegisterAction(name string) {
+
+	actionsMu.Lock()
+	defer actionsMu.Unlock()
+
+	actions[name] = true
+}
+
+// isRegisteredAction reports whether name has been registered.
+func isRegisteredAction(name string) bool {
+
+	actionsMu.RLock()
+	defer actionsMu.RUnlock()
+
+	return actions[name]
+}
+
+// SetAction attaches a recovery hint to an error response, encoded as an "action" object with the action's
+// name and params. The action is validated after all other options; building fails with ErrUnknownAction if
+// it has not been registered. Successful responses do not carry actions, so the hint is omitted if the
+// response ends up successful.
+//
+// Parameters:
+//   - action: The registered name of the action, such as ActionRetry.
+//   - params: Parameters of the action; may be nil.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetAction(action string, params map[string]any) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if !isRegisteredAction(action) {
+			return fmt.Errorf("%w %q", ErrUnknownAction, action)
+		}
+
+		if args.Success {
+			return nil
+		}
+
+		// Copy, so that a map shared between responses is not modified
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyAction] = Action{Name: action, Params: params}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,100 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+type actionResponse = httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+
+// TestTooManyRequests tests that the preset sets code 429, Retry-After and the retry action.
+func TestTooManyRequests(t *testing.T) {
+	response, err := rpsutil.Build[actionResponse](httpresponse.TooManyRequests[int, string, map[string]interface{}, int](1500 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", got)
+	}
+	if !contains(recorder.Body.String(), `"action":{"name":"retry","params":{"retry_after":2}}`) {
+		t.Errorf("Expected retry action, got %s", recorder.Body.String())
+	}
+}
+
+// TestUnauthorized tests that the preset sets code 401 and the reauthenticate action.
+func TestUnauthorized(t *testing.T) {
+	response, err := rpsutil.Build[actionResponse](httpresponse.Unauthorized[int, string, map[string]interface{}, int]())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success || response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a failed 401, got %+v", response)
+	}
+	if want := (httpresponse.Action{Name: httpresponse.ActionReauthenticate}); !reflect.DeepEqual(response.Extra[httpresponse.KeyAction], want) {
+		t.Errorf("Expected %+v, got %+v", want, response.Extra[httpresponse.KeyAction])
+	}
+}
+
+// TestSetAction tests a manually set action, including one registered by the application.
+func TestSetAction(t *testing.T) {
+	httpresponse.RegisterAction("update_app")
+
+	extra := map[string]interface{}{"request_id": "r-1"}
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetAction("update_app", map[string]any{"min_version": "2.0"}).
+		SetSuccess(false).
+		SetExtra(extra)
+
+	response, err := rpsutil.Build[actionResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := httpresponse.Action{Name: "update_app", Params: map[string]any{"min_version": "2.0"}}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyAction], want) {
+		t.Errorf("Expected %+v, got %+v", want, response.Extra[httpresponse.KeyAction])
+	}
+	if response.Extra["request_id"] != "r-1" {
+		t.Errorf("Expected existing Extra to be kept, got %v", response.Extra)
+	}
+	if _, ok := extra[httpresponse.KeyAction]; ok {
+		t.Error("Expected the caller's Extra map not to be modified")
+	}
+
+	// Successful responses do not carry actions
+	success, err := rpsutil.Build[actionResponse](httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetAction(httpresponse.ActionRetry, nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := success.Extra[httpresponse.KeyAction]; ok {
+		t.Errorf("Expected no action on a successful response, got %v", success.Extra)
+	}
+}
+
+// TestSetAction_Unknown tests that an unregistered action fails the build.
+func TestSetAction_Unknown(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetSuccess(false).
+		SetAction("self_destruct", nil)
+
+	if _, err := rpsutil.Build[actionResponse](builder); !errors.Is(err, httpresponse.ErrUnknownAction) {
+		t.Errorf("Expected ErrUnknownAction, got %v", err)
+	}
+}
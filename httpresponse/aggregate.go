@@ -0,0 +1,259 @@
+package httpresponse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// AggregatePolicy decides whether an aggregated response is successful given its upstream outcomes.
+type AggregatePolicy int
+
+const (
+	// AggregateAll succeeds only if every upstream succeeded.
+	AggregateAll AggregatePolicy = iota
+
+	// AggregateAny succeeds if at least one upstream succeeded.
+	AggregateAny
+
+	// AggregateQuorum succeeds if more than half of the upstreams succeeded.
+	AggregateQuorum
+)
+
+// String returns the name of the policy.
+func (policy AggregatePolicy) String() string {
+	switch policy {
+	case AggregateAll:
+		return "all"
+	case AggregateAny:
+		return "any"
+	case AggregateQuorum:
+		return "quorum"
+	default:
+		return fmt.Sprintf("AggregatePolicy(%d)", int(policy))
+	}
+}
+
+// Aggregate combines upstream responses, keyed by upstream name, into one envelope.
+//
+// The resulting Data maps each successful upstream's name to its data, and Success is decided by policy.
+// Code is taken from the upstream with the most severe status (see StatusCode), so a single failing
+// upstream surfaces as a failure code. Each failed upstream is listed under the KeyErrors Extra key as an
+// ErrorDetail whose Field is its name and whose Code, SubCode and Message are its own, sorted by name, and
+// each upstream's own Extra is nested under the "upstreams" Extra key by its name so that keys of different
+// upstreams cannot collide. A nil response counts as a failure, listed with the "no_response" code.
+//
+// Parameters:
+//   - responses: The upstream responses keyed by upstream name.
+//   - policy: The policy deciding the overall Success.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the combined envelope.
+func Aggregate[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](responses map[string]*HTTPResponseOptions[C, D, E, T], policy AggregatePolicy) *HTTPResponseBuilder[C, map[string]D, E, T] {
+
+	return aggregate(responses, nil, policy, 0)
+}
+
+// Section fetches one part of a fanned-out response, such as the response of one upstream service.
+// It should return promptly once ctx is done.
+type Section[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] func(ctx context.Context) *HTTPResponseOptions[C, D, E, T]
+
+// FanOutOption configures FanOut.
+type FanOutOption func(*fanOutOptions)
+
+// fanOutOptions holds the settings applied by FanOutOption values.
+type fanOutOptions struct {
+	ctx           context.Context
+	partialStatus int
+}
+
+// WithDeadlinePartial bounds a fan-out by ctx. When ctx is done before every section has completed,
+// FanOut stops waiting and aggregates the sections completed so far: each pending section is listed
+// under the KeyErrors Extra key as an ErrorDetail with the "timeout" code and its name as Field, Extra gains
+// "partial": true and, if the aggregate still succeeds under its policy, Code is set to status (with string
+// codes, its decimal form).
+//
+// Parameters:
+//   - ctx: The context whose deadline or cancellation ends the fan-out; it is also passed to every section.
+//   - status: The HTTP status of partial responses, typically http.StatusOK or http.StatusPartialContent.
+//
+// Returns:
+//   - FanOutOption: The option to pass to FanOut.
+func WithDeadlinePartial(ctx context.Context, status int) FanOutOption {
+	return func(fanOutOptions *fanOutOptions) {
+
+		fanOutOptions.ctx = ctx
+		fanOutOptions.partialStatus = status
+
+	}
+}
+
+// FanOut runs every section concurrently, waits for them and combines their responses with Aggregate.
+// Without WithDeadlinePartial it waits for every section. Sections abandoned at the deadline keep
+// running in the background; their late results are discarded and never touch the returned builder.
+//
+// Parameters:
+//   - sections: The sections keyed by name; the names become the keys of the aggregated Data.
+//   - policy: The policy deciding the overall Success.
+//   - opts: Options such as WithDeadlinePartial.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the combined envelope.
+func FanOut[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](sections map[string]Section[C, D, E, T], policy AggregatePolicy, opts ...FanOutOption) *HTTPResponseBuilder[C, map[string]D, E, T] {
+
+	options := fanOutOptions{ctx: context.Background(), partialStatus: http.StatusOK}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	type result struct {
+		name     string
+		response *HTTPResponseOptions[C, D, E, T]
+	}
+
+	// Buffered so that abandoned sections can always deliver their result and exit.
+	results := make(chan result, len(sections))
+
+	for name, section := range sections {
+		go func(name string, section Section[C, D, E, T]) {
+			results <- result{name: name, response: section(options.ctx)}
+		}(name, section)
+	}
+
+	responses := make(map[string]*HTTPResponseOptions[C, D, E, T], len(sections))
+
+	for len(responses) < len(sections) {
+		select {
+		case r := <-results:
+			responses[r.name] = r.response
+		case <-options.ctx.Done():
+			pending := make(map[string]bool)
+			for name := range sections {
+				if _, ok := responses[name]; !ok {
+					pending[name] = true
+					responses[name] = nil
+				}
+			}
+			return aggregate(responses, pending, policy, options.partialStatus)
+		}
+	}
+
+	return aggregate(responses, nil, policy, 0)
+}
+
+// aggregate implements Aggregate. Names in pending are listed as timed-out ErrorDetail values, and when
+// pending is non-empty the response is marked partial, taking partialStatus as its code if it still succeeds.
+func aggregate[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](responses map[string]*HTTPResponseOptions[C, D, E, T], pending map[string]bool, policy AggregatePolicy, partialStatus int) *HTTPResponseBuilder[C, map[string]D, E, T] {
+
+	names := make([]string, 0, len(responses))
+	for name := range responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := make(map[string]D, len(responses))
+	upstreams := make(map[string]any)
+	var errs []ErrorDetail
+
+	var code C
+	severity := 0
+
+	for _, name := range names {
+
+		if pending[name] {
+			errs = append(errs, ErrorDetail{Code: "timeout", Message: "no response before the deadline", Field: name})
+			continue
+		}
+
+		response := responses[name]
+		if response == nil {
+			errs = append(errs, ErrorDetail{Code: "no_response", Message: "no response", Field: name})
+			continue
+		}
+
+		if status := response.StatusCode(); status > severity {
+			severity = status
+			code = response.Code
+		}
+
+		if len(response.Extra) > 0 {
+			upstreams[name] = response.Extra
+		}
+
+		if !response.Success {
+			errs = append(errs, ErrorDetail{
+				Code:    fmt.Sprint(response.Code),
+				SubCode: response.SubCode,
+				Message: response.Message,
+				Field:   name,
+			})
+			continue
+		}
+
+		data[name] = response.Data
+	}
+
+	succeeded := len(responses) - len(errs)
+
+	var success bool
+	switch policy {
+	case AggregateAny:
+		success = succeeded > 0
+	case AggregateQuorum:
+		success = succeeded*2 > len(responses)
+	default:
+		success = len(errs) == 0
+	}
+
+	extra := make(E)
+	if len(errs) > 0 {
+		extra[KeyErrors] = errs
+	}
+	if len(upstreams) > 0 {
+		extra["upstreams"] = upstreams
+	}
+	if len(pending) > 0 {
+		extra["partial"] = true
+
+		if success {
+			code, _ = parseCode[C](strconv.Itoa(partialStatus))
+		}
+	}
+
+	httpResponseBuilder := HTTPResponse[C, map[string]D, E, T]().
+		setSuccess(success).
+		SetCode(code).
+		SetData(data)
+
+	if len(extra) > 0 {
+		httpResponseBuilder = httpResponseBuilder.SetExtra(extra)
+	}
+
+	if !success {
+		httpResponseBuilder = httpResponseBuilder.SetMessage(fmt.Sprintf("%d of %d upstreams failed", len(errs), len(responses)))
+	}
+
+	return httpResponseBuilder
+}
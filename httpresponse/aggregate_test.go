@@ -0,0 +1,238 @@
+package httpresponse_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+type upstream = httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+
+type aggregated = httpresponse.HTTPResponseOptions[int, map[string]string, map[string]interface{}, int]
+
+// upstreamsFixture returns two successful upstreams and one failing upstream.
+func upstreamsFixture() map[string]*upstream {
+	return map[string]*upstream{
+		"users":   {Success: true, Code: 200, Data: "u", Extra: map[string]interface{}{"trace_id": "a"}},
+		"orders":  {Success: true, Code: 200, Data: "o", Extra: map[string]interface{}{"trace_id": "b"}},
+		"billing": {Success: false, Code: 503, Message: "unavailable"},
+	}
+}
+
+// TestAggregate_Policies tests the overall Success decided by each policy for a mix of upstreams.
+func TestAggregate_Policies(t *testing.T) {
+	tests := []struct {
+		policy httpresponse.AggregatePolicy
+		want   bool
+	}{
+		{httpresponse.AggregateAll, false},
+		{httpresponse.AggregateAny, true},
+		{httpresponse.AggregateQuorum, true},
+	}
+
+	for _, tt := range tests {
+		response, err := rpsutil.Build[aggregated](httpresponse.Aggregate(upstreamsFixture(), tt.policy))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if response.Success != tt.want {
+			t.Errorf("Expected Success %v for policy %v, got %v", tt.want, tt.policy, response.Success)
+		}
+	}
+}
+
+// TestAggregate_Quorum tests that the quorum policy requires a strict majority.
+func TestAggregate_Quorum(t *testing.T) {
+	responses := map[string]*upstream{
+		"a": {Success: true, Code: 200},
+		"b": {Success: false, Code: 500},
+	}
+
+	response, err := rpsutil.Build[aggregated](httpresponse.Aggregate(responses, httpresponse.AggregateQuorum))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Success {
+		t.Error("Expected a tie to fail the quorum")
+	}
+}
+
+// TestAggregate_Combined tests the Data, Code, errors and nested Extra of a mixed aggregation.
+func TestAggregate_Combined(t *testing.T) {
+	responses := upstreamsFixture()
+	responses["search"] = nil
+
+	response, err := rpsutil.Build[aggregated](httpresponse.Aggregate(responses, httpresponse.AggregateAll))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := map[string]string{"users": "u", "orders": "o"}; !reflect.DeepEqual(response.Data, want) {
+		t.Errorf("Expected Data %v, got %v", want, response.Data)
+	}
+	if response.Code != 503 {
+		t.Errorf("Expected the most severe code 503, got %v", response.Code)
+	}
+	if response.Message != "2 of 4 upstreams failed" {
+		t.Errorf("Expected failure summary, got %q", response.Message)
+	}
+
+	wantErrors := []httpresponse.ErrorDetail{
+		{Code: "503", Message: "unavailable", Field: "billing"},
+		{Code: "no_response", Message: "no response", Field: "search"},
+	}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyErrors], wantErrors) {
+		t.Errorf("Expected errors %v, got %v", wantErrors, response.Extra[httpresponse.KeyErrors])
+	}
+
+	upstreams, ok := response.Extra["upstreams"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected nested upstream Extra, got %T", response.Extra["upstreams"])
+	}
+	if !reflect.DeepEqual(upstreams["users"], map[string]interface{}{"trace_id": "a"}) {
+		t.Errorf("Expected users Extra nested under its name, got %v", upstreams["users"])
+	}
+	if _, ok := response.Extra["trace_id"]; ok {
+		t.Error("Expected upstream Extra keys not to be merged at the top level")
+	}
+}
+
+// TestAggregate_AllSucceed tests an aggregation in which every upstream succeeds.
+func TestAggregate_AllSucceed(t *testing.T) {
+	responses := map[string]*upstream{
+		"a": {Success: true, Code: 200, Data: "x"},
+		"b": {Success: true, Code: 201, Data: "y"},
+	}
+
+	response, err := rpsutil.Build[aggregated](httpresponse.Aggregate(responses, httpresponse.AggregateAll))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Message != "" {
+		t.Errorf("Expected success without message, got %+v", response)
+	}
+	if response.Code != 201 {
+		t.Errorf("Expected code 201, got %v", response.Code)
+	}
+	if response.Extra != nil {
+		t.Errorf("Expected no Extra, got %v", response.Extra)
+	}
+}
+
+// blockingSection returns a section that responds only once release is closed, ignoring ctx.
+func blockingSection(release <-chan struct{}, data string) httpresponse.Section[int, string, map[string]interface{}, int] {
+	return func(ctx context.Context) *upstream {
+		<-release
+		return &upstream{Success: true, Code: 200, Data: data}
+	}
+}
+
+// readySection returns a section that responds immediately.
+func readySection(data string) httpresponse.Section[int, string, map[string]interface{}, int] {
+	return func(ctx context.Context) *upstream {
+		return &upstream{Success: true, Code: 200, Data: data}
+	}
+}
+
+// TestFanOut tests that FanOut waits for every section without a deadline.
+func TestFanOut(t *testing.T) {
+	release := make(chan struct{})
+	sections := map[string]httpresponse.Section[int, string, map[string]interface{}, int]{
+		"fast": readySection("f"),
+		"slow": blockingSection(release, "s"),
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	response, err := rpsutil.Build[aggregated](httpresponse.FanOut(sections, httpresponse.AggregateAll))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || len(response.Data) != 2 {
+		t.Errorf("Expected both sections, got %+v", response)
+	}
+	if _, ok := response.Extra["partial"]; ok {
+		t.Error("Expected no partial marker")
+	}
+}
+
+// TestFanOut_DeadlinePartial tests that sections pending at the deadline are reported as timed out.
+func TestFanOut_DeadlinePartial(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusPartialContent} {
+
+		release := make(chan struct{})
+		sections := map[string]httpresponse.Section[int, string, map[string]interface{}, int]{
+			"fast": readySection("f"),
+			"slow": blockingSection(release, "s"),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+
+		builder := httpresponse.FanOut(sections, httpresponse.AggregateAny, httpresponse.WithDeadlinePartial(ctx, status))
+		cancel()
+
+		// The abandoned section completes late; its result must not reach the built response.
+		close(release)
+
+		response, err := rpsutil.Build[aggregated](builder)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !response.Success {
+			t.Error("Expected the any policy to succeed")
+		}
+		if response.Code != status {
+			t.Errorf("Expected code %d, got %d", status, response.Code)
+		}
+		if want := map[string]string{"fast": "f"}; !reflect.DeepEqual(response.Data, want) {
+			t.Errorf("Expected Data %v, got %v", want, response.Data)
+		}
+		if response.Extra["partial"] != true {
+			t.Errorf("Expected partial marker, got %v", response.Extra)
+		}
+		want := []httpresponse.ErrorDetail{{Code: "timeout", Message: "no response before the deadline", Field: "slow"}}
+		if !reflect.DeepEqual(response.Extra[httpresponse.KeyErrors], want) {
+			t.Errorf("Expected errors %v, got %v", want, response.Extra[httpresponse.KeyErrors])
+		}
+	}
+}
+
+// TestFanOut_DeadlinePartialFailure tests that a partial response failing its policy keeps the failure code.
+func TestFanOut_DeadlinePartialFailure(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	sections := map[string]httpresponse.Section[int, string, map[string]interface{}, int]{
+		"fast": readySection("f"),
+		"slow": blockingSection(release, "s"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	response, err := rpsutil.Build[aggregated](httpresponse.FanOut(sections, httpresponse.AggregateAll, httpresponse.WithDeadlinePartial(ctx, http.StatusPartialContent)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success {
+		t.Error("Expected the all policy to fail")
+	}
+	if response.Code != 200 {
+		t.Errorf("Expected the code of the completed sections, got %d", response.Code)
+	}
+	if response.Extra["partial"] != true {
+		t.Errorf("Expected partial marker, got %v", response.Extra)
+	}
+}
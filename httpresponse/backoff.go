@@ -0,0 +1,70 @@
+package httpresponse
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	// KeyRetryAfterSeconds is the Extra key carrying the delay before the next attempt, in whole seconds.
+	KeyRetryAfterSeconds = "retry_after_seconds"
+
+	// KeyNextAttempt is the Extra key carrying the time of the next attempt.
+	KeyNextAttempt = "next_attempt"
+)
+
+// SetBackoff advises the client when to retry after its failed attempt: the delay is base doubled for each
+// attempt after the first, with equal jitter so that clients failing together do not retry together, that
+// is a random delay between half the exponential delay and the exponential delay. The delay is stored,
+// rounded up to whole seconds, under the "retry_after_seconds" Extra key and in the Retry-After header, and
+// the time of the next attempt under the "next_attempt" Extra key. The jitter and the time come from the
+// Rand and Clock dependencies of the response (see Deps); call SetDeps before SetBackoff to override them.
+//
+// Parameters:
+//   - attempt: The number of the failed attempt, starting at 1; lower numbers count as 1.
+//   - base: The delay after the first attempt.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetBackoff(attempt int, base time.Duration) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		deps := args.deps()
+
+		delay := backoffDelay(attempt, base, deps.Rand())
+		seconds := int64(math.Ceil(delay.Seconds()))
+
+		extra := make(E, len(args.Extra)+2)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyRetryAfterSeconds] = seconds
+		extra[KeyNextAttempt] = deps.Clock().Add(delay).UTC().Format(time.RFC3339)
+		args.Extra = extra
+
+		args.setHeader("Retry-After", strconv.FormatInt(seconds, 10))
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// backoffDelay returns the delay before the attempt following attempt, jittered by r in [0.0, 1.0).
+// Delays too long to be represented are capped.
+func backoffDelay(attempt int, base time.Duration, r float64) time.Duration {
+
+	if attempt < 1 {
+		attempt = 1
+	}
+	if base < 0 {
+		base = 0
+	}
+
+	delay := float64(base) * math.Pow(2, float64(attempt-1))
+	delay = delay/2 + r*delay/2
+
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,76 @@
+package httpresponse_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetBackoff tests the delay, next attempt and Retry-After header of a deterministic jitter.
+func TestSetBackoff(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetSuccess(false).
+			SetDeps(httpresponse.Deps{
+				Clock: func() time.Time { return time.Unix(1700000000, 0) },
+				Rand:  func() float64 { return 0.5 },
+			}).
+			SetBackoff(3, 2*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 2s doubled twice is 8s, jittered to 4s + 0.5*4s
+	if got := response.Extra[httpresponse.KeyRetryAfterSeconds]; got != int64(6) {
+		t.Errorf("Expected 6 seconds, got %v", got)
+	}
+	if got := response.Extra[httpresponse.KeyNextAttempt]; got != "2023-11-14T22:13:26Z" {
+		t.Errorf("Expected the next attempt 6 seconds later, got %v", got)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := recorder.Header().Get("Retry-After"); got != "6" {
+		t.Errorf("Expected Retry-After 6, got %q", got)
+	}
+}
+
+// TestSetBackoff_Grows tests that the delay grows with the attempt, whatever the jitter.
+func TestSetBackoff_Grows(t *testing.T) {
+	previous := int64(-1)
+
+	for attempt := 1; attempt <= 8; attempt++ {
+		// The delays of an attempt reach at most the shortest delay of the next one
+		var delays []int64
+		for _, jitter := range []float64{0, 0.99} {
+			jitter := jitter
+
+			response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+				httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+					SetSuccess(false).
+					SetDeps(httpresponse.Deps{
+						Clock: func() time.Time { return time.Unix(1700000000, 0) },
+						Rand:  func() float64 { return jitter },
+					}).
+					SetBackoff(attempt, 2*time.Second),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			delays = append(delays, response.Extra[httpresponse.KeyRetryAfterSeconds].(int64))
+		}
+		shortest, longest := delays[0], delays[1]
+
+		if shortest < previous || longest <= shortest {
+			t.Errorf("Expected attempt %d to wait at least %d seconds, got %d to %d", attempt, previous, shortest, longest)
+		}
+		previous = longest
+	}
+}
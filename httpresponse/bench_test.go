@@ -0,0 +1,50 @@
+package httpresponse_test
+
+import (
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// BenchmarkHTTPResponse measures the cost of creating a builder.
+func BenchmarkHTTPResponse(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = httpresponse.HTTPResponse[int, string, map[string]interface{}, int]()
+	}
+}
+
+// BenchmarkHTTPResponse_TypicalBuild measures creating a builder with typical setters and building it.
+func BenchmarkHTTPResponse_TypicalBuild(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetMessage("ok").
+			SetCode(200).
+			SetData("payload").
+			SetTotal(1)
+
+		if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBuildDirect measures building the typical response of BenchmarkHTTPResponse_TypicalBuild with BuildDirect.
+func BenchmarkBuildDirect(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := httpresponse.BuildDirect(httpresponse.Options[int, string, map[string]interface{}, int]{
+			Message: "ok",
+			Code:    200,
+			Data:    "payload",
+			Total:   1,
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package httpresponse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the body size limit applied when BodyLimits.MaxBytes is not set.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Codes of the error details reported by DecodeJSONBody.
+const (
+	BodyErrorTooLarge     = "body_too_large"
+	BodyErrorContentType  = "unsupported_content_type"
+	BodyErrorEmpty        = "empty_body"
+	BodyErrorSyntax       = "malformed_json"
+	BodyErrorType         = "invalid_type"
+	BodyErrorUnknownField = "unknown_field"
+	BodyErrorRead         = "unreadable_body"
+)
+
+const (
+	// invalidBodyMessage is the message of the envelope rejecting a request body.
+	invalidBodyMessage = "Invalid request body."
+
+	// unknownFieldPrefix prefixes the message of the error reported by json.Decoder for unknown fields.
+	unknownFieldPrefix = "json: unknown field "
+)
+
+// BodyLimits configures the checks DecodeJSONBody applies to request bodies.
+type BodyLimits struct {
+	MaxBytes           int64    // The maximum body size in bytes; DefaultMaxBodyBytes if zero or negative.
+	ContentTypes       []string // The accepted media types; only "application/json" if empty.
+	AllowUnknownFields bool     // Accepts object keys that do not map to a field of the decoded type.
+}
+
+// DecodeJSONBody returns a handler that decodes the request body as JSON into a value of type T and passes it
+// to next. Bodies larger than limits.MaxBytes, with a media type not listed in limits.ContentTypes, empty,
+// malformed, holding a value of the wrong type, holding unknown fields (unless allowed) or followed by further
+// data are rejected with a 400 envelope instead. The rejection lists a single ErrorDetail under the "errors"
+// Extra key, with the byte offset of syntax errors and the field of type and unknown-field errors.
+//
+// Parameters:
+//   - next: The handler receiving the decoded value.
+//   - limits: The checks applied to the body.
+//
+// Returns:
+//   - http.HandlerFunc: The decoding handler.
+func DecodeJSONBody[T any](next func(w http.ResponseWriter, r *http.Request, value T), limits BodyLimits) http.HandlerFunc {
+
+	maxBytes := limits.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	contentTypes := limits.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{contentTypeJSON}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		value, detail := decodeBody[T](r, maxBytes, contentTypes, !limits.AllowUnknownFields)
+		if detail != nil {
+			writeBodyError(w, *detail)
+			return
+		}
+
+		next(w, r, value)
+	}
+}
+
+// decodeBody decodes the body of r, returning a detail describing the failure if the body is rejected.
+func decodeBody[T any](r *http.Request, maxBytes int64, contentTypes []string, strict bool) (T, *ErrorDetail) {
+
+	var value T
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !containsFold(contentTypes, mediaType) {
+		return value, &ErrorDetail{
+			Code:    BodyErrorContentType,
+			Message: fmt.Sprintf("content type must be one of %s", strings.Join(contentTypes, ", ")),
+		}
+	}
+
+	if r.Body == nil {
+		return value, &ErrorDetail{Code: BodyErrorEmpty, Message: "request body must not be empty"}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		return value, &ErrorDetail{Code: BodyErrorRead, Message: "request body could not be read"}
+	}
+	if int64(len(body)) > maxBytes {
+		return value, &ErrorDetail{Code: BodyErrorTooLarge, Message: fmt.Sprintf("request body must not exceed %d bytes", maxBytes)}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return value, &ErrorDetail{Code: BodyErrorEmpty, Message: "request body must not be empty"}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(&value); err != nil {
+		return value, describeDecodeError(err)
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return value, &ErrorDetail{
+			Code:    BodyErrorSyntax,
+			Message: "request body must contain a single JSON value",
+			Offset:  decoder.InputOffset(),
+		}
+	}
+
+	return value, nil
+}
+
+// describeDecodeError converts a decoding error into an error detail, locating it where possible.
+func describeDecodeError(err error) *ErrorDetail {
+
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxError):
+		return &ErrorDetail{
+			Code:    BodyErrorSyntax,
+			Message: fmt.Sprintf("malformed JSON at offset %d", syntaxError.Offset),
+			Offset:  syntaxError.Offset,
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &ErrorDetail{Code: BodyErrorSyntax, Message: "malformed JSON: unexpected end of input"}
+	case errors.As(err, &typeError):
+		return &ErrorDetail{
+			Code:    BodyErrorType,
+			Message: fmt.Sprintf("field %q must be of type %s", typeError.Field, typeError.Type),
+			Field:   typeError.Field,
+			Offset:  typeError.Offset,
+		}
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		// The decoder reports unknown fields with an unexported error type
+		field := strings.Trim(strings.TrimPrefix(err.Error(), unknownFieldPrefix), `"`)
+		return &ErrorDetail{
+			Code:    BodyErrorUnknownField,
+			Message: fmt.Sprintf("unknown field %q", field),
+			Field:   field,
+		}
+	default:
+		return &ErrorDetail{Code: BodyErrorSyntax, Message: "malformed JSON"}
+	}
+}
+
+// writeBodyError writes the 400 envelope rejecting a request body.
+func writeBodyError(w http.ResponseWriter, detail ErrorDetail) {
+
+	response := &HTTPResponseOptions[int, any, map[string]any, int]{
+		Success: false,
+		Message: invalidBodyMessage,
+		Code:    http.StatusBadRequest,
+		Extra:   map[string]any{KeyErrors: []ErrorDetail{detail}},
+	}
+
+	// WriteJSON logs its failures with a preview of the envelope
+	_ = WriteJSON(w, response)
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+type signup struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+// serveDecode sends body with the given content type through DecodeJSONBody and returns the recorder
+// and the decoded value passed to the handler, if any.
+func serveDecode(t *testing.T, contentType, body string, limits httpresponse.BodyLimits) (*httptest.ResponseRecorder, *signup) {
+	t.Helper()
+
+	var decoded *signup
+	handler := httpresponse.DecodeJSONBody(func(w http.ResponseWriter, r *http.Request, value signup) {
+		decoded = &value
+		w.WriteHeader(http.StatusNoContent)
+	}, limits)
+
+	request := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	return recorder, decoded
+}
+
+// bodyErrorDetail decodes the single error detail of a 400 envelope.
+func bodyErrorDetail(t *testing.T, recorder *httptest.ResponseRecorder) httpresponse.ErrorDetail {
+	t.Helper()
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", recorder.Code)
+	}
+
+	var envelope struct {
+		Success bool                       `json:"success"`
+		Code    int                        `json:"code"`
+		Errors  []httpresponse.ErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Expected a JSON envelope, got %v", err)
+	}
+	if envelope.Success || envelope.Code != http.StatusBadRequest || len(envelope.Errors) != 1 {
+		t.Fatalf("Expected a failed 400 envelope with one error, got %s", recorder.Body.String())
+	}
+
+	return envelope.Errors[0]
+}
+
+// TestDecodeJSONBody tests that a valid body is decoded and passed to the handler.
+func TestDecodeJSONBody(t *testing.T) {
+	recorder, decoded := serveDecode(t, "application/json; charset=utf-8", `{"name":"alice","age":30}`, httpresponse.BodyLimits{})
+
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected the handler to run, got status %d", recorder.Code)
+	}
+	if decoded == nil || *decoded != (signup{Name: "alice", Age: 30}) {
+		t.Errorf("Expected the decoded value, got %+v", decoded)
+	}
+}
+
+// TestDecodeJSONBody_Oversize tests that bodies beyond the size limit are rejected.
+func TestDecodeJSONBody_Oversize(t *testing.T) {
+	recorder, decoded := serveDecode(t, "application/json", `{"name":"`+strings.Repeat("a", 64)+`"}`, httpresponse.BodyLimits{MaxBytes: 32})
+
+	if decoded != nil {
+		t.Error("Expected the handler not to run")
+	}
+	if detail := bodyErrorDetail(t, recorder); detail.Code != httpresponse.BodyErrorTooLarge {
+		t.Errorf("Expected %s, got %+v", httpresponse.BodyErrorTooLarge, detail)
+	}
+}
+
+// TestDecodeJSONBody_ContentType tests that bodies with an unaccepted media type are rejected.
+func TestDecodeJSONBody_ContentType(t *testing.T) {
+	recorder, decoded := serveDecode(t, "text/plain", `{"name":"alice"}`, httpresponse.BodyLimits{})
+
+	if decoded != nil {
+		t.Error("Expected the handler not to run")
+	}
+	if detail := bodyErrorDetail(t, recorder); detail.Code != httpresponse.BodyErrorContentType {
+		t.Errorf("Expected %s, got %+v", httpresponse.BodyErrorContentType, detail)
+	}
+}
+
+// TestDecodeJSONBody_Syntax tests that syntax errors are reported with their offset.
+func TestDecodeJSONBody_Syntax(t *testing.T) {
+	recorder, _ := serveDecode(t, "application/json", `{"name":"alice",}`, httpresponse.BodyLimits{})
+
+	detail := bodyErrorDetail(t, recorder)
+	if detail.Code != httpresponse.BodyErrorSyntax || detail.Offset != 17 {
+		t.Errorf("Expected a syntax error at offset 17, got %+v", detail)
+	}
+}
+
+// TestDecodeJSONBody_Type tests that type errors are reported with their field.
+func TestDecodeJSONBody_Type(t *testing.T) {
+	recorder, _ := serveDecode(t, "application/json", `{"name":"alice","age":"thirty"}`, httpresponse.BodyLimits{})
+
+	detail := bodyErrorDetail(t, recorder)
+	if detail.Code != httpresponse.BodyErrorType || detail.Field != "age" {
+		t.Errorf("Expected a type error for age, got %+v", detail)
+	}
+}
+
+// TestDecodeJSONBody_UnknownField tests strict rejection of unknown fields and its opt-out.
+func TestDecodeJSONBody_UnknownField(t *testing.T) {
+	recorder, _ := serveDecode(t, "application/json", `{"name":"alice","admin":true}`, httpresponse.BodyLimits{})
+
+	detail := bodyErrorDetail(t, recorder)
+	if detail.Code != httpresponse.BodyErrorUnknownField || detail.Field != "admin" {
+		t.Errorf("Expected an unknown field error for admin, got %+v", detail)
+	}
+
+	recorder, decoded := serveDecode(t, "application/json", `{"name":"alice","admin":true}`, httpresponse.BodyLimits{AllowUnknownFields: true})
+	if recorder.Code != http.StatusNoContent || decoded == nil {
+		t.Errorf("Expected unknown fields to be allowed, got status %d", recorder.Code)
+	}
+}
+
+// TestDecodeJSONBody_TrailingData tests that a body holding more than one value is rejected.
+func TestDecodeJSONBody_TrailingData(t *testing.T) {
+	recorder, _ := serveDecode(t, "application/json", `{"name":"alice"} {"name":"bob"}`, httpresponse.BodyLimits{})
+
+	if detail := bodyErrorDetail(t, recorder); detail.Code != httpresponse.BodyErrorSyntax {
+		t.Errorf("Expected %s, got %+v", httpresponse.BodyErrorSyntax, detail)
+	}
+}
@@ -3,6 +3,8 @@
 // for consistent and customizable HTTP responses across applications.
 package httpresponse
 
+import "fmt"
+
 // HTTPResponseBuilder is a generic builder for constructing structured HTTP response configurations.
 // It allows setting various response fields such as success status, message, response code, data, total count, and additional metadata.
 //
@@ -11,6 +13,10 @@ package httpresponse
 //   - D: Defines the type for the data field, which can be any data type (e.g., string, struct, array, etc.).
 //   - E: Defines the type for extra metadata, represented as a map with string keys and any values.
 //   - T: Defines the type for the total field, supporting various integer types (e.g., int, uint, int64).
+//
+// A builder may be copied by value, such as to derive several responses from a base configuration; the
+// setters of each copy then only affect that copy. Options appended to Opts directly bypass this protection
+// and may clobber the options of another copy; packages extending the builder append with AddOpt instead.
 type HTTPResponseBuilder[
 	C int | string,
 	D any,
@@ -18,6 +24,22 @@ type HTTPResponseBuilder[
 	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
 ] struct {
 	Opts []func(*HTTPResponseOptions[C, D, E, T]) error
+
+	// finalizers run after all Opts, so that validations observe the fully configured options
+	// regardless of the order in which setters were called.
+	finalizers []func(*HTTPResponseOptions[C, D, E, T]) error
+
+	// pageETag requests the ETag of SetPageETag, derived once every other option and finalizer has run.
+	pageETag bool
+
+	// strict, successSet and errorSet track explicit setters for the checks of Strict.
+	strict     bool
+	successSet bool
+	errorSet   bool
+
+	// owner is the builder whose option slices these are. A builder copied by value shares the backing
+	// arrays of the slices with the original, so a copy takes its own slices before its first append.
+	owner *HTTPResponseBuilder[C, D, E, T]
 }
 
 // HTTPResponse initializes a new instance of HTTPResponseBuilder with default settings.
@@ -32,16 +54,33 @@ func HTTPResponse[
 	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
 ]() *HTTPResponseBuilder[C, D, E, T] {
 
-	httpResponseBuilder := new(HTTPResponseBuilder[C, D, E, T])
+	// Allocate the builder and room for the typical number of setters at once
+	httpResponseBuilder := &HTTPResponseBuilder[C, D, E, T]{
+		Opts: make([]func(*HTTPResponseOptions[C, D, E, T]) error, 1, defaultOptsCapacity),
+	}
 
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.Opts[0] = setDefaultSuccess[C, D, E, T]
+	httpResponseBuilder.owner = httpResponseBuilder
 
-		args.Success = true
+	return httpResponseBuilder
+}
 
-		return nil
-	})
+// defaultOptsCapacity is the initial capacity of the options of a builder, sized for the default option
+// followed by the typical number of setters.
+const defaultOptsCapacity = 8
 
-	return httpResponseBuilder
+// setDefaultSuccess is the default option of HTTPResponse, shared by all builders instead of allocating
+// a closure per builder.
+func setDefaultSuccess[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](args *HTTPResponseOptions[C, D, E, T]) error {
+
+	args.Success = true
+
+	return nil
 }
 
 // SetSuccess specifies the Success field in the HTTP response options.
@@ -49,7 +88,15 @@ func HTTPResponse[
 // Parameters:
 //   - success: A boolean indicating whether the response is successful (true) or not (false).
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSuccess(success bool) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+	httpResponseBuilder.successSet = true
+
+	return httpResponseBuilder.setSuccess(success)
+}
+
+// setSuccess queues an option setting the Success field, without counting as an explicit SetSuccess call.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) setSuccess(success bool) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
 
 		args.Success = success
 
@@ -59,12 +106,55 @@ func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSuccess(success b
 	return httpResponseBuilder
 }
 
+// SuccessReporter is implemented by data types that know whether they represent a successful outcome.
+type SuccessReporter interface {
+	IsSuccess() bool
+}
+
+// SetSuccessFromData derives the Success field from the response data when the data implements SuccessReporter.
+// The derivation runs after all other options of the builder, so it observes the final data; data that does not
+// implement SuccessReporter leaves Success unchanged.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSuccessFromData() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if successReporter, ok := any(args.Data).(SuccessReporter); ok {
+			args.Success = successReporter.IsSuccess()
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
 // SetMessage adds a message to the HTTP response options for providing additional context or detail.
 //
 // Parameters:
 //   - message: A string containing the message, such as a success confirmation or error description.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetMessage(message string) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Message = message
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SetSuccessMessage sets the message to the catalog message registered for the given operation.
+// The message is resolved when the options are applied, so catalog overrides registered before
+// building are honored. Building fails if no message is registered for the operation.
+//
+// Parameters:
+//   - operation: The operation whose standard success message is used (e.g. OperationCreated).
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSuccessMessage(operation Operation) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		message, ok := SuccessMessage(operation)
+		if !ok {
+			return fmt.Errorf("httpresponse: no success message registered for operation %v", operation)
+		}
 
 		args.Message = message
 
@@ -79,7 +169,7 @@ func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetMessage(message s
 // Parameters:
 //   - data: The content to include in the response, defined by type parameter D, which can be any type.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetCode(code C) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
 
 		args.Code = code
 
@@ -89,12 +179,29 @@ func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetCode(code C) *HTT
 	return httpResponseBuilder
 }
 
+// SetSubCode sets the fine-grained code of the response, emitted as "sub_code" next to the code, so that
+// clients can branch on a coarse category in the code (e.g. "VALIDATION") and on the precise condition in
+// the sub-code (e.g. "EMAIL_TAKEN").
+//
+// Parameters:
+//   - subCode: The fine-grained code; empty omits the key.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSubCode(subCode string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.SubCode = subCode
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
 // SetData assigns the main content or payload to the HTTP response options.
 //
 // Parameters:
 //   - data: The data to include in the response, defined by type parameter D, which can be any type.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetData(data D) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
 
 		args.Data = data
 
@@ -104,13 +211,48 @@ func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetData(data D) *HTT
 	return httpResponseBuilder
 }
 
+// SetNullAs sets the representation MarshalJSON emits as Data when Data is nil or the zero value of its type,
+// instead of omitting it, for clients that expect, for example, "data": {} rather than no data at all.
+//
+// Parameters:
+//   - v: The substitute representation, such as struct{}{} or a sentinel; nil restores the default handling.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetNullAs(v any) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.NullAs = v
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
 // SetExtra adds supplementary metadata to the HTTP response options.
 //
 // Parameters:
 //   - extra: A map of additional metadata, defined by type parameter E, for providing extra details beyond standard fields.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtra(extra E) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Extra = extra
 
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// addExtra queues an option adding entries to the Extra fields set so far, copying them first.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) addExtra(entries E) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		extra := make(E, len(args.Extra)+len(entries))
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		for k, v := range entries {
+			extra[k] = v
+		}
 		args.Extra = extra
 
 		return nil
@@ -119,25 +261,182 @@ func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtra(extra E) *H
 	return httpResponseBuilder
 }
 
+// AddOpt appends a custom option to the builder, such as one defined by a package extending it. Like the
+// setters, it leaves the options of the builders this one was copied from, or copied to, unchanged.
+//
+// Parameters:
+//   - opt: The option, applied to the response in order with the other options.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) AddOpt(opt func(*HTTPResponseOptions[C, D, E, T]) error) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.addOpt(opt)
+}
+
+// addOpt appends opt to the options of the builder, taking its own copy of the options first if the builder
+// is a copy of another one.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) addOpt(opt func(*HTTPResponseOptions[C, D, E, T]) error) *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder.own()
+	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, opt)
+
+	return httpResponseBuilder
+}
+
+// addFinalizer appends finalizer to the finalizers of the builder, taking its own copy of the finalizers first
+// if the builder is a copy of another one.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) addFinalizer(finalizer func(*HTTPResponseOptions[C, D, E, T]) error) *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder.own()
+	httpResponseBuilder.finalizers = append(httpResponseBuilder.finalizers, finalizer)
+
+	return httpResponseBuilder
+}
+
+// own clips the option slices of a builder that is not their owner, such as a copy made by value or a builder
+// created as a literal, so that its next appends reallocate rather than write into the backing arrays shared
+// with the builder it was copied from.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) own() {
+
+	if httpResponseBuilder.owner == httpResponseBuilder {
+		return
+	}
+
+	httpResponseBuilder.Opts = httpResponseBuilder.Opts[:len(httpResponseBuilder.Opts):len(httpResponseBuilder.Opts)]
+	httpResponseBuilder.finalizers = httpResponseBuilder.finalizers[:len(httpResponseBuilder.finalizers):len(httpResponseBuilder.finalizers)]
+	httpResponseBuilder.owner = httpResponseBuilder
+}
+
+// SetHeader sets an HTTP header sent along with the response by the writers, replacing any value set before
+// for the same key.
+//
+// Parameters:
+//   - key: The name of the header.
+//   - value: The value of the header.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetHeader(key, value string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.setHeader(key, value)
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SetExtraKeyOrder controls the order in which Extra keys are emitted by MarshalJSON.
+// Keys listed in order are written first, in the given order; any remaining Extra keys follow alphabetically.
+// Keys in order that are not present in Extra are ignored.
+//
+// Parameters:
+//   - order: The preferred emission order of Extra keys (e.g. placing "links" last).
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtraKeyOrder(order []string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.ExtraKeyOrder = order
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// BareData switches the response to bare data mode, in which MarshalJSON emits only the encoded Data value
+// (for example a top-level JSON array) instead of the envelope. Success and Message are dropped silently,
+// while Extra fields, such as the errors of UnprocessableEntity or the links of Paginate, and a non-zero
+// Total cannot be represented and cause marshaling to fail.
+//
+// Parameters:
+//   - bare: A boolean enabling (true) or disabling (false) bare data mode.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) BareData(bare bool) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.BareData = bare
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
 // SetTotal specifies a total count or amount in the HTTP response, typically used for pagination or summaries.
+// The total is validated after all other options: building fails with ErrNegativeTotal for negative totals
+// unless AllowNegativeTotal is set, and with ErrTotalOutOfRange for totals above SetMaxTotal or, by default,
+// for unsigned 32- and 64-bit totals that look like wrapped negative values.
 //
 // Parameters:
 //   - total: The total value, defined by integer type parameter T.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetTotal(total T) *HTTPResponseBuilder[C, D, E, T] {
-	httpResponseBuilder.Opts = append(httpResponseBuilder.Opts, func(args *HTTPResponseOptions[C, D, E, T]) error {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
 
 		args.Total = total
 
 		return nil
 	})
 
+	httpResponseBuilder.addFinalizer(validateTotal[C, D, E, T])
+
+	return httpResponseBuilder
+}
+
+// SetTotalAsString serializes the total as a JSON string when T is a 64-bit integer type, preserving the
+// precision of totals beyond 2^53 for JavaScript clients. Totals of narrower types are always exact as
+// JSON numbers and are left unchanged.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetTotalAsString() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.TotalAsString = true
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// AddVary declares request headers the response varies by, such as "Accept-Language". Contributions from
+// every AddVary call, from the response headers and from earlier handlers or middleware are merged by the
+// writers into a single deduplicated, sorted Vary header, keeping shared caches from mixing up variants.
+//
+// Parameters:
+//   - fields: The request header names the response depends on.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) AddVary(fields ...string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Vary = append(args.Vary, fields...)
+
+		return nil
+	})
+
 	return httpResponseBuilder
 }
 
 // List retrieves the list of option functions that configure the HTTP response.
+// Finalizing functions, such as build-time validations, are listed after all other options, followed by
+// the inclusion of the default support contact block while one is registered (see SetDefaultSupportInfo),
+// by the global finalizers of the response type (see RegisterGlobalFinalizer) and, last, by the derivation
+// of the ETag requested by SetPageETag.
 //
 // Returns:
 //   - []func(*HTTPResponseOptions[C, D, E, T]) error: A slice of functions used to configure the response options.
 func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) List() []func(*HTTPResponseOptions[C, D, E, T]) error {
-	return httpResponseBuilder.Opts
+
+	support := defaultSupportInfo.Load().(*SupportInfo) != nil
+	global := globalFinalizersOf[C, D, E, T]()
+
+	if len(httpResponseBuilder.finalizers) == 0 && !support && len(global) == 0 && !httpResponseBuilder.pageETag {
+		return httpResponseBuilder.Opts
+	}
+
+	opts := make([]func(*HTTPResponseOptions[C, D, E, T]) error, 0, len(httpResponseBuilder.Opts)+len(httpResponseBuilder.finalizers)+len(global)+2)
+	opts = append(opts, httpResponseBuilder.Opts...)
+	opts = append(opts, httpResponseBuilder.finalizers...)
+
+	if support {
+		opts = append(opts, defaultSupport[C, D, E, T])
+	}
+
+	opts = append(opts, global...)
+
+	if httpResponseBuilder.pageETag {
+		opts = append(opts, setPageETag[C, D, E, T])
+	}
+
+	return opts
 }
@@ -0,0 +1,141 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// KeyRange is the Extra key under which SetByteRange describes the range of a chunk.
+const KeyRange = "range"
+
+// ErrInvalidByteRange is returned when building a response whose byte range lies outside its source.
+var ErrInvalidByteRange = errors.New("httpresponse: invalid byte range")
+
+// ByteRange describes the position of a chunk within its source.
+type ByteRange struct {
+	Offset    int64 `json:"offset"`     // The offset of the first byte of the chunk.
+	Length    int64 `json:"length"`     // The number of bytes in the chunk.
+	TotalSize int64 `json:"total_size"` // The size of the whole source.
+	Completed bool  `json:"completed"`  // Whether the chunk reaches the end of the source.
+}
+
+// SetByteRange describes the data of the response as the chunk [offset, offset+length) of a source of
+// totalSize bytes, under the "range" Extra key. The completed flag is set when the chunk reaches the end of
+// the source. Building fails with ErrInvalidByteRange if the range is negative or exceeds totalSize.
+//
+// Parameters:
+//   - offset: The offset of the first byte of the chunk.
+//   - length: The number of bytes in the chunk.
+//   - totalSize: The size of the whole source.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetByteRange(offset, length, totalSize int64) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if offset < 0 || length < 0 || totalSize < 0 || offset > totalSize || length > totalSize-offset {
+			return fmt.Errorf("%w: offset %d, length %d, total size %d", ErrInvalidByteRange, offset, length, totalSize)
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyRange] = ByteRange{
+			Offset:    offset,
+			Length:    length,
+			TotalSize: totalSize,
+			Completed: offset+length == totalSize,
+		}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SliceByteRange initializes a builder carrying the chunk of source selected by a Range-style parameter,
+// described with SetByteRange. The data is encoded as base64 by MarshalJSON. Supported forms are
+// "bytes=first-last", "bytes=first-" and the suffix form "bytes=-n", with or without the "bytes=" prefix;
+// an empty parameter selects the whole source, and a last position beyond the end is clamped to it.
+//
+// Malformed or unsatisfiable ranges yield a failed response with code 416 and a
+// "Content-Range: bytes */size" header instead.
+//
+// Parameters:
+//   - source: The whole payload.
+//   - rangeParam: The requested range, such as the value of a Range header or query parameter.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the chunk, or with the 416 error response.
+func SliceByteRange[
+	C int | string,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](source []byte, rangeParam string) *HTTPResponseBuilder[C, []byte, E, T] {
+
+	size := int64(len(source))
+
+	offset, length, ok := parseByteRange(rangeParam, size)
+	if !ok {
+		httpResponseBuilder := errorPreset[C, []byte, E, T](http.StatusRequestedRangeNotSatisfiable)
+
+		httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, []byte, E, T]) error {
+
+			args.setHeader("Content-Range", fmt.Sprintf("bytes */%d", size))
+
+			return nil
+		})
+
+		return httpResponseBuilder
+	}
+
+	return HTTPResponse[C, []byte, E, T]().
+		SetData(source[offset:offset+length]).
+		SetByteRange(offset, length, size)
+}
+
+// parseByteRange resolves a Range-style parameter against a source of size bytes, returning the offset
+// and length of the selected chunk, or false if the parameter is malformed or unsatisfiable.
+func parseByteRange(rangeParam string, size int64) (int64, int64, bool) {
+
+	spec := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rangeParam), "bytes="))
+	if spec == "" {
+		return 0, size, true
+	}
+
+	first, last, found := strings.Cut(spec, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	// Suffix form: the final n bytes
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, size > 0
+	}
+
+	offset, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || offset < 0 || offset >= size {
+		return 0, 0, false
+	}
+
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < offset {
+			return 0, 0, false
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+
+	return offset, end - offset + 1, true
+}
@@ -0,0 +1,87 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+type chunkResponse = httpresponse.HTTPResponseOptions[int, []byte, map[string]interface{}, int]
+
+// report is a 10-byte source.
+var report = []byte("0123456789")
+
+// TestSliceByteRange_Mid tests a chunk in the middle of the source.
+func TestSliceByteRange_Mid(t *testing.T) {
+	response, err := rpsutil.Build[chunkResponse](httpresponse.SliceByteRange[int, map[string]interface{}, int](report, "bytes=2-5"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(response.Data) != "2345" {
+		t.Errorf("Expected chunk 2345, got %q", response.Data)
+	}
+
+	want := httpresponse.ByteRange{Offset: 2, Length: 4, TotalSize: 10, Completed: false}
+	if response.Extra[httpresponse.KeyRange] != want {
+		t.Errorf("Expected %+v, got %+v", want, response.Extra[httpresponse.KeyRange])
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(body), `"data":"MjM0NQ=="`) || !contains(string(body), `"range":{"offset":2,"length":4,"total_size":10,"completed":false}`) {
+		t.Errorf("Expected a base64 chunk with its range, got %s", body)
+	}
+}
+
+// TestSliceByteRange_Final tests that the final chunk is flagged as completed, for open and suffix forms.
+func TestSliceByteRange_Final(t *testing.T) {
+	for _, rangeParam := range []string{"bytes=6-", "-4", "bytes=6-100"} {
+		response, err := rpsutil.Build[chunkResponse](httpresponse.SliceByteRange[int, map[string]interface{}, int](report, rangeParam))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := httpresponse.ByteRange{Offset: 6, Length: 4, TotalSize: 10, Completed: true}
+		if string(response.Data) != "6789" || response.Extra[httpresponse.KeyRange] != want {
+			t.Errorf("Expected the completed final chunk for %q, got %q %+v", rangeParam, response.Data, response.Extra[httpresponse.KeyRange])
+		}
+	}
+}
+
+// TestSliceByteRange_OutOfBounds tests that unsatisfiable ranges yield a 416 error envelope.
+func TestSliceByteRange_OutOfBounds(t *testing.T) {
+	for _, rangeParam := range []string{"bytes=10-12", "bytes=5-2", "bytes=x-1", "items=1-2"} {
+		response, err := rpsutil.Build[chunkResponse](httpresponse.SliceByteRange[int, map[string]interface{}, int](report, rangeParam))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		recorder := httptest.NewRecorder()
+		if err := httpresponse.WriteJSON(recorder, response); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if recorder.Code != http.StatusRequestedRangeNotSatisfiable || response.Success {
+			t.Errorf("Expected a failed 416 for %q, got %d", rangeParam, recorder.Code)
+		}
+		if got := recorder.Header().Get("Content-Range"); got != "bytes */10" {
+			t.Errorf("Expected Content-Range bytes */10, got %q", got)
+		}
+	}
+}
+
+// TestSetByteRange_Validation tests that ranges exceeding the total size fail the build.
+func TestSetByteRange_Validation(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, []byte, map[string]interface{}, int]().SetByteRange(8, 4, 10)
+
+	if _, err := rpsutil.Build[chunkResponse](builder); !errors.Is(err, httpresponse.ErrInvalidByteRange) {
+		t.Errorf("Expected ErrInvalidByteRange, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package httpresponse
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes the caching intent of a response.
+type CachePolicy struct {
+	Public               bool          // Allows shared caches to store the response.
+	Private              bool          // Restricts storage to the client's private cache.
+	NoStore              bool          // Forbids storing the response at all.
+	MaxAge               time.Duration // Freshness lifetime, truncated to whole seconds.
+	StaleWhileRevalidate time.Duration // Period during which a stale response may be served while revalidating.
+}
+
+// CacheControl renders the policy as a Cache-Control header value.
+//
+// Returns:
+//   - string: The directives, such as "public, max-age=60".
+func (cachePolicy *CachePolicy) CacheControl() string {
+
+	if cachePolicy.NoStore {
+		return "no-store"
+	}
+
+	directives := make([]string, 0, 3)
+
+	switch {
+	case cachePolicy.Public:
+		directives = append(directives, "public")
+	case cachePolicy.Private:
+		directives = append(directives, "private")
+	}
+
+	directives = append(directives, "max-age="+seconds(cachePolicy.MaxAge))
+
+	if cachePolicy.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(cachePolicy.StaleWhileRevalidate))
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// Expires renders the policy as an Expires header value relative to now. Responses that must not be
+// stored are given a date in the past, which HTTP/1.0 caches treat as already expired.
+//
+// Parameters:
+//   - now: The time the response is sent.
+//
+// Returns:
+//   - string: The expiry date in HTTP date format.
+func (cachePolicy *CachePolicy) Expires(now time.Time) string {
+
+	if cachePolicy.NoStore {
+		return time.Unix(0, 0).UTC().Format(http.TimeFormat)
+	}
+
+	return now.Add(cachePolicy.MaxAge).UTC().Format(http.TimeFormat)
+}
+
+// seconds formats d as a whole number of seconds, as used by Cache-Control directives.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// CachePublic allows the response to be stored by shared and private caches for maxAge.
+// Caching presets do not combine: the last one applied wins, and overriding an earlier preset
+// is reported through the logger hook.
+//
+// Parameters:
+//   - maxAge: The freshness lifetime of the response.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) CachePublic(maxAge time.Duration) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setCache(&CachePolicy{Public: true, MaxAge: maxAge})
+}
+
+// CachePrivate allows the response to be stored only by the client's private cache for maxAge.
+// See CachePublic for how presets combine.
+//
+// Parameters:
+//   - maxAge: The freshness lifetime of the response.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) CachePrivate(maxAge time.Duration) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setCache(&CachePolicy{Private: true, MaxAge: maxAge})
+}
+
+// NoStore forbids any cache from storing the response. See CachePublic for how presets combine.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) NoStore() *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setCache(&CachePolicy{NoStore: true})
+}
+
+// StaleWhileRevalidate allows shared caches to store the response for maxAge and then to keep serving it
+// for swr while revalidating in the background. See CachePublic for how presets combine.
+//
+// Parameters:
+//   - maxAge: The freshness lifetime of the response.
+//   - swr: The period during which the stale response may be served.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) StaleWhileRevalidate(maxAge, swr time.Duration) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setCache(&CachePolicy{Public: true, MaxAge: maxAge, StaleWhileRevalidate: swr})
+}
+
+// CacheExpires additionally renders the caching intent as an Expires header, for HTTP/1.0 caches
+// that ignore Cache-Control.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) CacheExpires() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.CacheExpires = true
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// setCache queues an option replacing the caching intent, warning when an earlier preset is overridden.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) setCache(cachePolicy *CachePolicy) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if args.Cache != nil {
+			args.deps().logf("httpresponse: caching preset %q overrides %q", cachePolicy.CacheControl(), args.Cache.CacheControl())
+		}
+
+		args.Cache = cachePolicy
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,101 @@
+package httpresponse_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// writeCached builds and writes a response configured by configure, returning the recorded headers.
+func writeCached(t *testing.T, configure func(*httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int])) http.Header {
+	t.Helper()
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]()
+	configure(builder)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return rec.Header()
+}
+
+// TestHTTPResponseBuilder_CachePresets tests the exact Cache-Control header of each preset.
+func TestHTTPResponseBuilder_CachePresets(t *testing.T) {
+	type builder = httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]
+
+	cases := map[string]func(*builder){
+		"public, max-age=300": func(b *builder) { b.CachePublic(5 * time.Minute) },
+		"private, max-age=60": func(b *builder) { b.CachePrivate(time.Minute) },
+		"no-store":            func(b *builder) { b.NoStore() },
+		"public, max-age=60, stale-while-revalidate=3600": func(b *builder) { b.StaleWhileRevalidate(time.Minute, time.Hour) },
+	}
+
+	for expected, configure := range cases {
+		header := writeCached(t, configure)
+
+		if header.Get("Cache-Control") != expected {
+			t.Errorf("Expected Cache-Control %q, got %q", expected, header.Get("Cache-Control"))
+		}
+		if header.Get("Expires") != "" {
+			t.Errorf("Expected no Expires header by default, got %q", header.Get("Expires"))
+		}
+	}
+
+	if header := writeCached(t, func(*builder) {}); header.Get("Cache-Control") != "" {
+		t.Errorf("Expected no Cache-Control without a preset, got %q", header.Get("Cache-Control"))
+	}
+}
+
+// TestHTTPResponseBuilder_CacheExpires tests the optional Expires header.
+func TestHTTPResponseBuilder_CacheExpires(t *testing.T) {
+	type builder = httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]
+
+	header := writeCached(t, func(b *builder) { b.CacheExpires().CachePublic(time.Hour) })
+
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		t.Fatalf("Expected a valid Expires header, got %q", header.Get("Expires"))
+	}
+	if delta := time.Until(expires); delta < 59*time.Minute || delta > time.Hour {
+		t.Errorf("Expected Expires about one hour ahead, got %v", delta)
+	}
+
+	header = writeCached(t, func(b *builder) { b.NoStore().CacheExpires() })
+	if header.Get("Expires") != "Thu, 01 Jan 1970 00:00:00 GMT" {
+		t.Errorf("Expected Expires in the past for no-store, got %q", header.Get("Expires"))
+	}
+}
+
+// TestHTTPResponseBuilder_CacheConflict tests that the last preset wins and the override is logged.
+func TestHTTPResponseBuilder_CacheConflict(t *testing.T) {
+	type builder = httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]
+
+	var logged []string
+	httpresponse.SetLogger(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	defer httpresponse.SetLogger(nil)
+
+	header := writeCached(t, func(b *builder) { b.CachePublic(time.Hour).NoStore() })
+
+	if header.Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected the last preset to win, got %q", header.Get("Cache-Control"))
+	}
+
+	expected := `httpresponse: caching preset "no-store" overrides "public, max-age=3600"`
+	if len(logged) != 1 || logged[0] != expected {
+		t.Errorf("Expected warning %q, got %v", expected, logged)
+	}
+}
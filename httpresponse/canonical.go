@@ -0,0 +1,233 @@
+package httpresponse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrInexactNumber is returned by CanonicalJSON when the envelope holds a number that RFC 8785 cannot represent
+// exactly, such as an int64 beyond 2^53 or a json.Number with more precision than an IEEE 754 double.
+var ErrInexactNumber = errors.New("httpresponse: number not exactly representable in canonical JSON")
+
+// CanonicalJSON encodes the envelope as MarshalJSON does and canonicalizes the result as RFC 8785 (JCS)
+// specifies: object keys sorted by their UTF-16 code units, numbers formatted as ECMAScript does, strings
+// escaped minimally and no insignificant whitespace. Settings such as SetExtraKeyOrder and SetFieldOrder
+// therefore have no effect on the output, while those changing the content, such as SetOmit, do.
+//
+// JCS represents numbers as IEEE 754 doubles, so every number in the envelope must be one exactly: float64
+// values always are, while integers beyond 2^53 and overly precise json.Number values are not.
+//
+// Returns:
+//   - []byte: The canonical encoding of the envelope.
+//   - error: An error if the envelope cannot be encoded, or ErrInexactNumber if it holds a number JCS cannot
+//     represent exactly.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) CanonicalJSON() ([]byte, error) {
+
+	r, err := httpResponseOptions.marshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(r))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v, "$"); err != nil {
+		return nil, err
+	}
+
+	recordMarshal(buf.Len())
+
+	return buf.Bytes(), nil
+}
+
+// writeCanonical writes the canonical encoding of v, a value decoded with json.Decoder.UseNumber, to buf.
+// path locates v in the envelope for error messages.
+func writeCanonical(buf *bytes.Buffer, v any, path string) error {
+
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buf, v)
+	case json.Number:
+		n, err := canonicalNumber(v)
+		if err != nil {
+			return fmt.Errorf("%w at %s", err, path)
+		}
+		buf.WriteString(n)
+	case []any:
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, e, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[k], path+"."+strconv.Quote(k)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("httpresponse: unexpected %T in canonical JSON at %s", v, path)
+	}
+
+	return nil
+}
+
+// canonicalNumber formats n as ECMAScript formats the IEEE 754 double it denotes, failing with
+// ErrInexactNumber if that double does not denote the same value as n.
+func canonicalNumber(n json.Number) (string, error) {
+
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%w: %s", ErrInexactNumber, n)
+	}
+
+	s := formatES6(f)
+	if s == string(n) {
+		return s, nil
+	}
+
+	exact, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInexactNumber, n)
+	}
+	formatted, _ := new(big.Rat).SetString(s)
+	if exact.Cmp(formatted) != 0 {
+		return "", fmt.Errorf("%w: %s", ErrInexactNumber, n)
+	}
+
+	return s, nil
+}
+
+// formatES6 formats f as ECMAScript's Number.prototype.toString does: the shortest decimal that reads back as f,
+// in exponential notation below 1e-6 and from 1e21 on, and with -0 written as 0.
+func formatES6(f float64) string {
+
+	if f == 0 {
+		return "0"
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	s := strconv.FormatFloat(f, format, -1, 64)
+
+	// Go writes exponents with at least two digits, ECMAScript with as few as needed: 1e-07 becomes 1e-7.
+	if format == 'e' {
+		if n := len(s); s[n-4] == 'e' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+
+	return s
+}
+
+// writeCanonicalString writes s as a JSON string escaped as RFC 8785 specifies: quotation mark, reverse
+// solidus and control characters only, with the short escapes where JSON has them.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hex[r>>4])
+			buf.WriteByte(hex[r&0xf])
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 reports whether a sorts before b when both are compared by their UTF-16 code units, as RFC 8785
+// requires. It differs from comparing their UTF-8 bytes for characters beyond the basic multilingual plane.
+func lessUTF16(a, b string) bool {
+
+	for a != "" && b != "" {
+
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		a, b = a[na:], b[nb:]
+
+		if ra == rb {
+			continue
+		}
+
+		ua, ub := utf16Units(ra), utf16Units(rb)
+		for i := 0; i < len(ua) && i < len(ub); i++ {
+			if ua[i] != ub[i] {
+				return ua[i] < ub[i]
+			}
+		}
+
+		return len(ua) < len(ub)
+	}
+
+	return a == "" && b != ""
+}
+
+// utf16Units returns the UTF-16 code units of r.
+func utf16Units(r rune) []uint16 {
+
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		return []uint16{uint16(r1), uint16(r2)}
+	}
+
+	return []uint16{uint16(r)}
+}
@@ -0,0 +1,166 @@
+package httpresponse_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// canonicalResponse is the response type of the canonical JSON tests.
+type canonicalResponse = httpresponse.HTTPResponseOptions[int, any, map[string]interface{}, int]
+
+// TestCanonicalJSON_Vectors tests the RFC 8785 test vectors that fit an envelope: the number, string and
+// literal sample of section 3.2.2, key sorting by UTF-16 code units, and the ECMAScript number formats.
+func TestCanonicalJSON_Vectors(t *testing.T) {
+	cases := []struct {
+		name     string
+		extra    map[string]interface{}
+		expected string
+	}{
+		{
+			name: "sample",
+			extra: map[string]interface{}{
+				"numbers":  []interface{}{333333333.33333329, 1e30, 4.50, 2e-3, 0.000000000000000000000000001},
+				"string":   "€$\u000F\u000aA'B\"\\\\\"/",
+				"literals": []interface{}{nil, true, false},
+			},
+			expected: `{"code":200,"literals":[null,true,false],"message":"ok",` +
+				`"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/","success":true}`,
+		},
+		{
+			name: "sorting",
+			extra: map[string]interface{}{
+				"sort": map[string]interface{}{
+					"\u20ac":     "Euro Sign",
+					"\r":         "Carriage Return",
+					"\ufb33":     "Hebrew Letter Dalet With Dagesh",
+					"1":          "One",
+					"\U0001f600": "Emoji: Grinning Face",
+					"\u0080":     "Control",
+					"\u00f6":     "Latin Small Letter O With Diaeresis",
+				},
+			},
+			expected: "{\"code\":200,\"message\":\"ok\",\"sort\":{\"\\r\":\"Carriage Return\",\"1\":\"One\"," +
+				"\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+				"\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"},\"success\":true}",
+		},
+		{
+			name: "numbers",
+			extra: map[string]interface{}{
+				"n": []interface{}{
+					math.Copysign(0, -1), 5e-324, math.MaxFloat64, 9007199254740992, -9007199254740992,
+					295147905179352830000.0, 1e21, 1e-7, 1e-6, 123456789012345680000.0, uint64(1) << 53,
+				},
+			},
+			expected: `{"code":200,"message":"ok","n":[0,5e-324,1.7976931348623157e+308,9007199254740992,-9007199254740992,` +
+				`295147905179352830000,1e+21,1e-7,0.000001,123456789012345680000,9007199254740992],"success":true}`,
+		},
+		{
+			name:     "escaping",
+			extra:    map[string]interface{}{"html": "<a href=\"x\">&</a> "},
+			expected: `{"code":200,"html":"<a href=\"x\">&</a>` + " " + `","message":"ok","success":true}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			response, err := rpsutil.Build[canonicalResponse](
+				httpresponse.HTTPResponse[int, any, map[string]interface{}, int]().SetCode(200).SetMessage("ok").SetExtra(c.extra),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			canonical, err := response.CanonicalJSON()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if string(canonical) != c.expected {
+				t.Errorf("Expected %s, got %s", c.expected, canonical)
+			}
+		})
+	}
+}
+
+// TestCanonicalJSON_InexactNumbers tests that numbers an IEEE 754 double cannot hold exactly are reported.
+func TestCanonicalJSON_InexactNumbers(t *testing.T) {
+	cases := []interface{}{
+		int64(9007199254740993),
+		uint64(math.MaxUint64),
+		json.Number("0.10000000000000000001"),
+		map[string]interface{}{"nested": []interface{}{json.Number("1e400")}},
+	}
+
+	for _, v := range cases {
+		response, err := rpsutil.Build[canonicalResponse](
+			httpresponse.HTTPResponse[int, any, map[string]interface{}, int]().SetCode(200).SetMessage("ok").SetExtra(map[string]interface{}{"value": v}),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		_, err = response.CanonicalJSON()
+		if !errors.Is(err, httpresponse.ErrInexactNumber) {
+			t.Errorf("Expected ErrInexactNumber for %v, got %v", v, err)
+		}
+	}
+}
+
+// TestCanonicalJSON_SignatureRoundTrip tests that an HMAC computed over the canonical encoding of a response
+// verifies against the canonical encoding of the same response after a regular marshal and unmarshal, whose
+// output orders keys and formats values differently.
+func TestCanonicalJSON_SignatureRoundTrip(t *testing.T) {
+	key := []byte("shared secret")
+	sign := func(payload []byte) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(payload)
+		return mac.Sum(nil)
+	}
+
+	extra := map[string]interface{}{
+		"zeta":    "<last>",
+		"alpha":   map[string]interface{}{"b": 1.50, "a": []interface{}{int64(1) << 40, 1e-9}},
+		"unicode": "café \U0001f600",
+	}
+
+	response, err := rpsutil.Build[canonicalResponse](
+		httpresponse.HTTPResponse[int, any, map[string]interface{}, int]().SetCode(200).SetMessage("ok").SetExtra(extra),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response.ExtraKeyOrder = []string{"zeta", "unicode", "alpha"}
+
+	canonical, err := response.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	signature := sign(canonical)
+
+	marshaled, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(marshaled) == string(canonical) {
+		t.Fatalf("Expected the regular encoding to differ from the canonical one, got %s", marshaled)
+	}
+
+	var received canonicalResponse
+	if err := json.Unmarshal(marshaled, &received); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recanonical, err := received.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !hmac.Equal(sign(recanonical), signature) {
+		t.Errorf("Expected the signature to verify, got %s for %s", recanonical, canonical)
+	}
+}
@@ -0,0 +1,106 @@
+package httpresponse
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// HeaderClientCapabilities is the request header in which clients declare their capabilities, as a
+// comma-separated list such as "rich-text, inline-media".
+const HeaderClientCapabilities = "X-Client-Capabilities"
+
+// capabilitiesContextKey is the context key used by WithCapabilities.
+type capabilitiesContextKey struct{}
+
+// WithCapabilities returns a copy of ctx carrying the capabilities of the client, adding to those ctx
+// already carries. Capabilities are matched case-insensitively; surrounding spaces and empty capabilities
+// are ignored.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - capabilities: The capabilities of the client.
+//
+// Returns:
+//   - context.Context: The context carrying the capabilities.
+func WithCapabilities(ctx context.Context, capabilities ...string) context.Context {
+
+	previous, _ := ctx.Value(capabilitiesContextKey{}).(map[string]bool)
+
+	merged := make(map[string]bool, len(previous)+len(capabilities))
+	for capability := range previous {
+		merged[capability] = true
+	}
+	for _, capability := range capabilities {
+		if capability = strings.ToLower(strings.TrimSpace(capability)); capability != "" {
+			merged[capability] = true
+		}
+	}
+
+	return context.WithValue(ctx, capabilitiesContextKey{}, merged)
+}
+
+// ClientCapabilities returns a handler storing the capabilities declared in the X-Client-Capabilities header
+// of each request into its context, for IncludeIf, before calling next.
+//
+// Parameters:
+//   - next: The handler receiving the requests.
+//
+// Returns:
+//   - http.Handler: The parsing handler.
+func ClientCapabilities(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		capabilities := strings.Split(strings.Join(r.Header.Values(HeaderClientCapabilities), ","), ",")
+
+		next.ServeHTTP(w, r.WithContext(WithCapabilities(r.Context(), capabilities...)))
+	})
+}
+
+// HasCapability reports whether ctx carries capability, matched case-insensitively.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - capability: The capability.
+//
+// Returns:
+//   - bool: True if the client declared the capability.
+func HasCapability(ctx context.Context, capability string) bool {
+
+	capabilities, _ := ctx.Value(capabilitiesContextKey{}).(map[string]bool)
+
+	return capabilities[strings.ToLower(capability)]
+}
+
+// IncludeIf includes value under the key Extra key only if the client declared capability, as carried by ctx
+// (see ClientCapabilities and WithCapabilities), so that newer clients receive richer data than older ones.
+// Since the response then depends on the declared capabilities, X-Client-Capabilities is added to its Vary
+// header either way.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - capability: The capability gating the field.
+//   - key: The Extra key of the field.
+//   - value: The value of the field.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) IncludeIf(ctx context.Context, capability string, key string, value any) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Vary = append(args.Vary, HeaderClientCapabilities)
+
+		if !HasCapability(ctx, capability) {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[key] = value
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
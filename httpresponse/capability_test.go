@@ -0,0 +1,78 @@
+package httpresponse_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestIncludeIf tests that the field is included for clients declaring the capability.
+func TestIncludeIf(t *testing.T) {
+	ctx := httpresponse.WithCapabilities(context.Background(), "Rich-Text")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetData("hello").
+			IncludeIf(ctx, "rich-text", "html", "<b>hello</b>"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Extra["html"] != "<b>hello</b>" {
+		t.Errorf("Expected the rich field, got %v", response.Extra)
+	}
+	if vary := response.Header().Get("Vary"); vary != httpresponse.HeaderClientCapabilities {
+		t.Errorf("Expected Vary %s, got %q", httpresponse.HeaderClientCapabilities, vary)
+	}
+}
+
+// TestIncludeIf_Missing tests that the field is omitted for clients not declaring the capability.
+func TestIncludeIf_Missing(t *testing.T) {
+	for _, ctx := range []context.Context{
+		context.Background(),
+		httpresponse.WithCapabilities(context.Background(), "inline-media"),
+	} {
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+			httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+				SetData("hello").
+				IncludeIf(ctx, "rich-text", "html", "<b>hello</b>"),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, ok := response.Extra["html"]; ok {
+			t.Errorf("Expected no rich field, got %v", response.Extra)
+		}
+		if vary := response.Header().Get("Vary"); vary != httpresponse.HeaderClientCapabilities {
+			t.Errorf("Expected Vary %s, got %q", httpresponse.HeaderClientCapabilities, vary)
+		}
+	}
+}
+
+// TestClientCapabilities tests that the middleware stores the capabilities of the request header in the context.
+func TestClientCapabilities(t *testing.T) {
+	var got map[string]bool
+
+	handler := httpresponse.ClientCapabilities(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = map[string]bool{}
+		for _, capability := range []string{"rich-text", "inline-media", "dark-mode"} {
+			got[capability] = httpresponse.HasCapability(r.Context(), capability)
+		}
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(httpresponse.HeaderClientCapabilities, " Rich-Text ,, inline-media")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	want := map[string]bool{"rich-text": true, "inline-media": true, "dark-mode": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
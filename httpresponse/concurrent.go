@@ -0,0 +1,57 @@
+package httpresponse
+
+import "sync"
+
+// ConcurrentExtra accumulates Extra metadata from multiple goroutines. Its zero value is ready to use.
+//
+// HTTPResponseBuilder itself is not safe for concurrent use: goroutines should only call Set, and the
+// accumulated map should be passed to SetExtra once all of them have finished.
+//
+// Example usage:
+//
+//	var extra httpresponse.ConcurrentExtra
+//	g.Go(func() error { extra.Set("quota", quota); return nil })
+//	if err := g.Wait(); err != nil { /* handle error */ }
+//	builder.SetExtra(extra.Map())
+type ConcurrentExtra struct {
+	mu    sync.Mutex
+	extra map[string]any
+}
+
+// Set stores value under key, replacing any previous value. It is safe for concurrent use.
+//
+// Parameters:
+//   - key: The Extra key.
+//   - value: The value to store.
+func (concurrentExtra *ConcurrentExtra) Set(key string, value any) {
+
+	concurrentExtra.mu.Lock()
+	defer concurrentExtra.mu.Unlock()
+
+	if concurrentExtra.extra == nil {
+		concurrentExtra.extra = make(map[string]any)
+	}
+
+	concurrentExtra.extra[key] = value
+}
+
+// Map returns a copy of the accumulated metadata, suitable for SetExtra. It is safe for concurrent use.
+//
+// Returns:
+//   - map[string]any: The accumulated key-value pairs; nil if nothing was set.
+func (concurrentExtra *ConcurrentExtra) Map() map[string]any {
+
+	concurrentExtra.mu.Lock()
+	defer concurrentExtra.mu.Unlock()
+
+	if concurrentExtra.extra == nil {
+		return nil
+	}
+
+	extra := make(map[string]any, len(concurrentExtra.extra))
+	for k, v := range concurrentExtra.extra {
+		extra[k] = v
+	}
+
+	return extra
+}
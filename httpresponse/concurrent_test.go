@@ -0,0 +1,59 @@
+package httpresponse_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestConcurrentExtra tests that concurrent writers accumulate into a map usable with SetExtra; run with -race.
+func TestConcurrentExtra(t *testing.T) {
+	const writers = 32
+
+	var extra httpresponse.ConcurrentExtra
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			extra.Set(fmt.Sprintf("key%d", i), i)
+			extra.Set("shared", i)
+		}(i)
+	}
+	wg.Wait()
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetExtra(extra.Map())
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Extra) != writers+1 {
+		t.Errorf("Expected %d Extra keys, got %d", writers+1, len(response.Extra))
+	}
+	for i := 0; i < writers; i++ {
+		if response.Extra[fmt.Sprintf("key%d", i)] != i {
+			t.Errorf("Expected key%d to be %d, got %v", i, i, response.Extra[fmt.Sprintf("key%d", i)])
+		}
+	}
+
+	// The returned map is a copy, so later writes do not affect the built response
+	extra.Set("late", true)
+	if _, ok := response.Extra["late"]; ok {
+		t.Error("Expected the built Extra to be independent of later writes")
+	}
+}
+
+// TestConcurrentExtra_Empty tests that an unused accumulator yields a nil map.
+func TestConcurrentExtra_Empty(t *testing.T) {
+	var extra httpresponse.ConcurrentExtra
+
+	if m := extra.Map(); m != nil {
+		t.Errorf("Expected nil map, got %v", m)
+	}
+}
@@ -0,0 +1,146 @@
+package httpresponse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Conditional sets the ETag and Last-Modified validators of the response. ServeJSON uses them to answer
+// conditional GET and HEAD requests with 304 Not Modified.
+//
+// Parameters:
+//   - etag: The entity tag; it is quoted if not already quoted (weak tags such as W/"v1" are kept as is).
+//     An empty etag sets no ETag header.
+//   - lastModified: The modification time of the resource; the zero time sets no Last-Modified header.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) Conditional(etag string, lastModified time.Time) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if etag != "" {
+			args.setHeader("ETag", quoteETag(etag))
+		}
+
+		if !lastModified.IsZero() {
+			args.setHeader("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// ETag returns a strong entity tag derived from the encoded content of the response, suitable for Conditional.
+// The content is encoded with sorted keys regardless of ExtraKeyOrder, so responses with the same logical
+// content always yield the same tag, however their output is ordered for display.
+//
+// Returns:
+//   - string: The quoted entity tag.
+//   - error: An error if the response cannot be encoded.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) ETag() (string, error) {
+
+	stable := *httpResponseOptions
+	stable.ExtraKeyOrder = nil
+
+	body, err := stable.marshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// ServeJSON writes the responder like WriteJSON, unless r is a GET or HEAD request whose preconditions
+// show that the client's copy is current, in which case only the headers and a 304 Not Modified status
+// are written. As required by RFC 9110, If-None-Match takes precedence over If-Modified-Since: the latter
+// is only evaluated when the request has no If-None-Match header.
+//
+// Parameters:
+//   - w: The destination http.ResponseWriter.
+//   - r: The request being answered; a nil request disables conditional handling.
+//   - responder: The response to write; its validators are read from its Header() method, if any.
+//
+// Returns:
+//   - error: An error if encoding the body or writing it fails.
+func ServeJSON(w http.ResponseWriter, r *http.Request, responder Responder) error {
+
+	// Evaluate the preconditions against the build that is written
+	responder, err := snapshotOf(responder)
+	if err != nil {
+		logResponseError("encode", responder, err)
+		return err
+	}
+
+	if r != nil && notModified(r, responder) {
+		commit := walRecord(responder, http.StatusNotModified, nil)
+		copyHeaders(w, responder)
+		normalizeVary(w.Header())
+		w.WriteHeader(http.StatusNotModified)
+		commit()
+		return nil
+	}
+
+	return WriteJSON(w, responder)
+}
+
+// notModified reports whether the request's preconditions match the responder's validators.
+func notModified(r *http.Request, responder Responder) bool {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	headerResponder, ok := responder.(interface{ Header() http.Header })
+	if !ok {
+		return false
+	}
+	header := headerResponder.Header()
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, header.Get("ETag"))
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ims)
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag using weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+
+	if etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteETag returns etag as a quoted entity tag, leaving already quoted and weak tags unchanged.
+func quoteETag(etag string) string {
+
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+
+	return `"` + etag + `"`
+}
@@ -0,0 +1,120 @@
+package httpresponse_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// serveConditional builds a response with both validators and serves it to a GET request with the given headers.
+func serveConditional(t *testing.T, lastModified time.Time, requestHeaders map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetData("resource").
+		Conditional("v1", lastModified)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	for k, v := range requestHeaders {
+		r.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.ServeJSON(rec, r, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return rec
+}
+
+// TestServeJSON_Conditional tests ETag and Last-Modified validation, including ETag precedence.
+func TestServeJSON_Conditional(t *testing.T) {
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+	}{
+		{"no preconditions", nil, http.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": `"v0", "v1"`}, http.StatusNotModified},
+		{"matching weak etag", map[string]string{"If-None-Match": `W/"v1"`}, http.StatusNotModified},
+		{"wildcard etag", map[string]string{"If-None-Match": `*`}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `"v0"`}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK},
+		{"stale etag wins over date", map[string]string{
+			"If-None-Match":     `"v0"`,
+			"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat),
+		}, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		rec := serveConditional(t, lastModified, c.headers)
+
+		if rec.Code != c.expected {
+			t.Errorf("%s: expected status %d, got %d", c.name, c.expected, rec.Code)
+		}
+		if rec.Header().Get("ETag") != `"v1"` {
+			t.Errorf("%s: expected ETag header \"v1\", got %v", c.name, rec.Header().Get("ETag"))
+		}
+		if rec.Header().Get("Last-Modified") != "Wed, 01 May 2024 12:00:00 GMT" {
+			t.Errorf("%s: expected Last-Modified header, got %v", c.name, rec.Header().Get("Last-Modified"))
+		}
+		if c.expected == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf("%s: expected empty body, got %v", c.name, rec.Body.String())
+		}
+	}
+}
+
+// TestETag_Stable tests that the ETag ignores the order of Extra keys, in the map and in the output.
+func TestETag_Stable(t *testing.T) {
+	first := map[string]interface{}{}
+	second := map[string]interface{}{}
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for i := range keys {
+		first[keys[i]] = i
+		second[keys[len(keys)-1-i]] = len(keys) - 1 - i
+	}
+
+	a, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("x").SetExtra(first))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("x").SetExtra(second).SetExtraKeyOrder([]string{"h", "a"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	etagA, err := a.ETag()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	etagB, err := b.ETag()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if etagA != etagB {
+		t.Errorf("Expected identical ETags, got %s and %s", etagA, etagB)
+	}
+	if len(etagA) != 34 || etagA[0] != '"' || etagA[33] != '"' {
+		t.Errorf("Expected a quoted 32 digit tag, got %s", etagA)
+	}
+
+	b.Data = "y"
+	if etagC, _ := b.ETag(); etagC == etagA {
+		t.Error("Expected a different ETag for different content")
+	}
+}
@@ -0,0 +1,73 @@
+package httpresponse
+
+import "net/http"
+
+const (
+	// KeyConsistencyToken is the Extra key, and the query parameter, carrying the consistency token.
+	KeyConsistencyToken = "consistency_token"
+
+	// HeaderConsistencyToken is the header carrying the consistency token.
+	HeaderConsistencyToken = "X-Consistency-Token"
+)
+
+// SetConsistencyToken includes the consistency token under the "consistency_token" Extra key.
+// An empty token omits the key, removing any token set before.
+//
+// Parameters:
+//   - token: The consistency token the client should echo back.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetConsistencyToken(token string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if _, ok := args.Extra[KeyConsistencyToken]; !ok && token == "" {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		if token == "" {
+			delete(extra, KeyConsistencyToken)
+		} else {
+			extra[KeyConsistencyToken] = token
+		}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// ConsistencyTokenHeader also sends the consistency token set by SetConsistencyToken in the
+// X-Consistency-Token header. It runs after all other options, so it sends the last token set regardless
+// of the order of the setters, and sends nothing if no token was set.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) ConsistencyTokenHeader() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if token, ok := args.Extra[KeyConsistencyToken].(string); ok && token != "" {
+			args.setHeader(HeaderConsistencyToken, token)
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// ConsistencyTokenFromRequest returns the consistency token echoed back by the client, read from the
+// X-Consistency-Token header or, if the header is absent, from the "consistency_token" query parameter.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - string: The consistency token; empty if the client sent none.
+func ConsistencyTokenFromRequest(r *http.Request) string {
+
+	if token := r.Header.Get(HeaderConsistencyToken); token != "" {
+		return token
+	}
+
+	return r.URL.Query().Get(KeyConsistencyToken)
+}
@@ -0,0 +1,69 @@
+package httpresponse_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetConsistencyToken tests that the token is emitted in the body and the header.
+func TestSetConsistencyToken(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			ConsistencyTokenHeader().
+			SetConsistencyToken("lsn:42"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if header := recorder.Header().Get(httpresponse.HeaderConsistencyToken); header != "lsn:42" {
+		t.Errorf("Expected header lsn:42, got %q", header)
+	}
+	if !contains(recorder.Body.String(), `"consistency_token":"lsn:42"`) {
+		t.Errorf("Expected the token in the body, got %s", recorder.Body.String())
+	}
+}
+
+// TestSetConsistencyToken_Empty tests that an empty token omits both the body key and the header.
+func TestSetConsistencyToken_Empty(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			ConsistencyTokenHeader().
+			SetConsistencyToken(""),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := response.Extra[httpresponse.KeyConsistencyToken]; ok {
+		t.Errorf("Expected no token in Extra, got %v", response.Extra)
+	}
+	if header := response.Header().Get(httpresponse.HeaderConsistencyToken); header != "" {
+		t.Errorf("Expected no header, got %q", header)
+	}
+}
+
+// TestConsistencyTokenFromRequest tests that the header takes precedence over the query parameter.
+func TestConsistencyTokenFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?consistency_token=query", nil)
+	if token := httpresponse.ConsistencyTokenFromRequest(r); token != "query" {
+		t.Errorf("Expected token query, got %q", token)
+	}
+
+	r.Header.Set(httpresponse.HeaderConsistencyToken, "header")
+	if token := httpresponse.ConsistencyTokenFromRequest(r); token != "header" {
+		t.Errorf("Expected token header, got %q", token)
+	}
+
+	if token := httpresponse.ConsistencyTokenFromRequest(httptest.NewRequest("GET", "/items", nil)); token != "" {
+		t.Errorf("Expected no token, got %q", token)
+	}
+}
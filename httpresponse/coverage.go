@@ -0,0 +1,147 @@
+package httpresponse
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TB is the subset of testing.TB used to report failures, so that checkers can be verified against fakes
+// as well as *testing.T.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// fieldSetters lists, by field of HTTPResponseOptions, the builder methods setting it. Add an entry along
+// with every new field.
+var fieldSetters = map[string][]string{
+	"Success":            {"SetSuccess", "SetSuccessFromData", "SetError"},
+	"Message":            {"SetMessage", "SetSuccessMessage"},
+	"Code":               {"SetCode"},
+	"SubCode":            {"SetSubCode", "SetError"},
+	"Data":               {"SetData"},
+	"Total":              {"SetTotal", "SetTotalFromInt"},
+	"Extra":              {"SetExtra", "SetExtraNamespaced", "SetExtraClaimed"},
+	"ExtraKeyOrder":      {"SetExtraKeyOrder"},
+	"BareData":           {"BareData"},
+	"TotalAsString":      {"SetTotalAsString"},
+	"AllowNegativeTotal": {"AllowNegativeTotal"},
+	"MaxTotal":           {"SetMaxTotal"},
+	"CoreKeyCase":        {"SetCoreKeyCase"},
+	"ExtraKeyCase":       {"SetExtraKeyCase"},
+	"SuccessKey":         {"SetSuccessKey"},
+	"Omit":               {"OmitWhen"},
+	"NullAs":             {"SetNullAs"},
+	"Unserializable":     {"SetUnserializablePolicy"},
+	"Deps":               {"SetDeps"},
+	"Headers":            {"SetHeader", "Conditional", "SetRateLimit", "SetQuota", "SetUsage", "SetBackoff", "SetPageETag"},
+	"Cache":              {"CachePublic", "CachePrivate", "NoStore", "StaleWhileRevalidate"},
+	"CacheExpires":       {"CacheExpires"},
+	"Vary":               {"AddVary"},
+}
+
+// FieldCoverage describes how the fields of an options struct are covered, for VerifyFieldCoverage and
+// for forks checking their own options types.
+type FieldCoverage struct {
+	Options reflect.Type        // The options struct type.
+	Builder reflect.Type        // The builder type, whose methods are the setters.
+	Setters map[string][]string // The names of the setters, by field name.
+
+	Fields      []Field // The Field entries of the envelope.
+	Marshaled   []Field // The envelope fields placed by the encoder.
+	Ordered     []Field // The envelope fields placed in the emission order of streams.
+	Unmarshaled []Field // The envelope fields decoded by FromJSON.
+}
+
+// VerifyFieldCoverage checks that every exported field of HTTPResponseOptions is set by a registered builder
+// setter that exists and, for the fields of the JSON envelope (those whose json tag is not "-"), that it has
+// a Field entry, is placed by the encoder and in the emission order of streams, and is decoded by FromJSON.
+// Each missing piece is reported with t.Errorf.
+//
+// Parameters:
+//   - t: The test, such as *testing.T.
+func VerifyFieldCoverage(t TB) {
+	t.Helper()
+
+	decoded := (&HTTPResponseOptions[int, any, map[string]any, int]{}).envelopeTargets()
+	unmarshaled := make([]Field, 0, len(decoded))
+	for key := range decoded {
+		unmarshaled = append(unmarshaled, Field(key))
+	}
+
+	marshaled := make([]Field, 0, len(envelopeFields))
+	for field := range (&HTTPResponseOptions[int, any, map[string]any, int]{}).Keys().byField() {
+		marshaled = append(marshaled, field)
+	}
+
+	FieldCoverage{
+		Options:     reflect.TypeOf(HTTPResponseOptions[int, any, map[string]any, int]{}),
+		Builder:     reflect.TypeOf(&HTTPResponseBuilder[int, any, map[string]any, int]{}),
+		Setters:     fieldSetters,
+		Fields:      envelopeFields,
+		Marshaled:   marshaled,
+		Ordered:     defaultStreamOrder,
+		Unmarshaled: unmarshaled,
+	}.Verify(t)
+}
+
+// Verify checks the coverage of the exported fields of fieldCoverage.Options, as described for
+// VerifyFieldCoverage.
+//
+// Parameters:
+//   - t: The test, such as *testing.T.
+func (fieldCoverage FieldCoverage) Verify(t TB) {
+	t.Helper()
+
+	for i := 0; i < fieldCoverage.Options.NumField(); i++ {
+
+		structField := fieldCoverage.Options.Field(i)
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		setters, ok := fieldCoverage.Setters[structField.Name]
+		if !ok || len(setters) == 0 {
+			t.Errorf("Field %s has no registered builder setter", structField.Name)
+		}
+		for _, setter := range setters {
+			if _, ok := fieldCoverage.Builder.MethodByName(setter); !ok {
+				t.Errorf("Field %s lists setter %s, which %s does not have", structField.Name, setter, fieldCoverage.Builder)
+			}
+		}
+
+		key := strings.Split(structField.Tag.Get("json"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = structField.Name
+		}
+
+		for _, registry := range []struct {
+			name   string
+			fields []Field
+		}{
+			{"Field entry", fieldCoverage.Fields},
+			{"encoder placement", fieldCoverage.Marshaled},
+			{"stream order placement", fieldCoverage.Ordered},
+			{"decoder mapping", fieldCoverage.Unmarshaled},
+		} {
+			if !containsField(registry.fields, Field(key)) {
+				t.Errorf("Envelope field %s (%q) has no %s", structField.Name, key, registry.name)
+			}
+		}
+	}
+}
+
+// containsField reports whether fields contains field.
+func containsField(fields []Field, field Field) bool {
+
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package httpresponse_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestVerifyFieldCoverage tests that every field of HTTPResponseOptions is covered.
+func TestVerifyFieldCoverage(t *testing.T) {
+	httpresponse.VerifyFieldCoverage(t)
+}
+
+// recordingTB records the failures reported to it.
+type recordingTB struct {
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// fixtureOptions is an options type whose Priority field was added without any of its wiring.
+type fixtureOptions struct {
+	Success  bool   `json:"success"`
+	Priority int    `json:"priority"`
+	Label    string `json:"-"`
+}
+
+// fixtureBuilder is the builder of fixtureOptions.
+type fixtureBuilder struct{}
+
+func (*fixtureBuilder) SetSuccess(bool) *fixtureBuilder { return nil }
+
+// TestFieldCoverage_Verify tests that the checker reports each missing piece of a field.
+func TestFieldCoverage_Verify(t *testing.T) {
+	var tb recordingTB
+
+	httpresponse.FieldCoverage{
+		Options:     reflect.TypeOf(fixtureOptions{}),
+		Builder:     reflect.TypeOf(&fixtureBuilder{}),
+		Setters:     map[string][]string{"Success": {"SetSuccess"}, "Label": {"SetLabel"}},
+		Fields:      []httpresponse.Field{httpresponse.FieldSuccess},
+		Marshaled:   []httpresponse.Field{httpresponse.FieldSuccess},
+		Ordered:     []httpresponse.Field{httpresponse.FieldSuccess},
+		Unmarshaled: []httpresponse.Field{httpresponse.FieldSuccess},
+	}.Verify(&tb)
+
+	want := []string{
+		"Field Priority has no registered builder setter",
+		`Envelope field Priority ("priority") has no Field entry`,
+		`Envelope field Priority ("priority") has no encoder placement`,
+		`Envelope field Priority ("priority") has no stream order placement`,
+		`Envelope field Priority ("priority") has no decoder mapping`,
+		"Field Label lists setter SetLabel, which *httpresponse_test.fixtureBuilder does not have",
+	}
+	if !reflect.DeepEqual(tb.failures, want) {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(tb.failures, "\n"))
+	}
+}
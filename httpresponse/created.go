@@ -0,0 +1,49 @@
+package httpresponse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// ErrEmptyLocation is returned when building a Created response without a location.
+var ErrEmptyLocation = errors.New("httpresponse: created response requires a location")
+
+// Created initializes a builder for the response of a create endpoint. The response is successful,
+// carries data, has code 201 and sends location in the Location header when written with WriteJSON
+// or ServeJSON. With string codes, Code is set to "201".
+//
+// Building fails with ErrEmptyLocation if location is empty.
+//
+// Parameters:
+//   - data: The created resource.
+//   - location: The URL of the created resource.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the created response.
+func Created[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](data D, location string) *HTTPResponseBuilder[C, D, E, T] {
+
+	code, _ := parseCode[C](strconv.Itoa(http.StatusCreated))
+
+	httpResponseBuilder := HTTPResponse[C, D, E, T]().
+		SetCode(code).
+		SetData(data)
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if location == "" {
+			return ErrEmptyLocation
+		}
+
+		args.setHeader("Location", location)
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
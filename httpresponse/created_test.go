@@ -0,0 +1,67 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestCreated tests that a Created response is written with status 201, its data and the Location header.
+func TestCreated(t *testing.T) {
+	type user struct {
+		ID int `json:"id"`
+	}
+
+	builder := httpresponse.Created[int, user, map[string]interface{}, int](user{ID: 42}, "/users/42")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, user, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Code != http.StatusCreated || response.Data.ID != 42 {
+		t.Errorf("Expected a successful 201 with the data, got %+v", response)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Location"); got != "/users/42" {
+		t.Errorf("Expected Location /users/42, got %q", got)
+	}
+	if !contains(recorder.Body.String(), `"data":{"id":42}`) {
+		t.Errorf("Expected the data in the body, got %s", recorder.Body.String())
+	}
+}
+
+// TestCreated_StringCode tests that Created sets the code "201" for string codes.
+func TestCreated_StringCode(t *testing.T) {
+	builder := httpresponse.Created[string, string, map[string]interface{}, int]("x", "/x/1")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Code != "201" {
+		t.Errorf("Expected code \"201\", got %q", response.Code)
+	}
+}
+
+// TestCreated_EmptyLocation tests that building a Created response without a location fails.
+func TestCreated_EmptyLocation(t *testing.T) {
+	builder := httpresponse.Created[int, string, map[string]interface{}, int]("x", "")
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if !errors.Is(err, httpresponse.ErrEmptyLocation) {
+		t.Errorf("Expected ErrEmptyLocation, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package httpresponse
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	dataMarshalersMu sync.Mutex
+	dataMarshalers   atomic.Value // dataMarshalerRegistry, replaced on every registration
+)
+
+// dataMarshalerRegistry holds the registered marshalers, by concrete type and, in registration order, by
+// interface type.
+type dataMarshalerRegistry struct {
+	types      map[reflect.Type]func(any) ([]byte, error)
+	interfaces []interfaceMarshaler
+}
+
+// interfaceMarshaler is a marshaler registered for an interface type.
+type interfaceMarshaler struct {
+	iface reflect.Type
+	fn    func(any) ([]byte, error)
+}
+
+// lookup returns the marshaler of values of type t: the one registered for t, or else the first one
+// registered for an interface that t implements; nil if there is none.
+func (dataMarshalerRegistry dataMarshalerRegistry) lookup(t reflect.Type) func(any) ([]byte, error) {
+
+	if fn, ok := dataMarshalerRegistry.types[t]; ok {
+		return fn
+	}
+
+	for _, marshaler := range dataMarshalerRegistry.interfaces {
+		if t.Implements(marshaler.iface) {
+			return marshaler.fn
+		}
+	}
+
+	return nil
+}
+
+// RegisterDataMarshaler registers fn as the encoding of Data values of type T, and of the elements of type T
+// of a Data slice or array, typically from an init function. Registered marshalers take precedence over
+// encoding/json, including json.Marshaler implementations of T; values nested deeper in Data are unaffected.
+// If T is an interface type, fn encodes the values of the types implementing T that have no marshaler of
+// their own; among several interfaces, the first registered applies.
+// fn must return valid JSON. Registering a second marshaler for T replaces the first. Registration is safe
+// for concurrent use with encoding; responses encoded concurrently with it may use either marshaler.
+//
+// Parameters:
+//   - fn: The function encoding a value of type T.
+//
+// Example usage:
+//
+//	httpresponse.RegisterDataMarshaler(func(m Money) ([]byte, error) {
+//		return json.Marshal(m.String())
+//	})
+func RegisterDataMarshaler[T any](fn func(T) ([]byte, error)) {
+
+	dataMarshalersMu.Lock()
+	defer dataMarshalersMu.Unlock()
+
+	current, _ := dataMarshalers.Load().(dataMarshalerRegistry)
+
+	// Copy on write, so that concurrent encodings keep reading the previous registry
+	updated := dataMarshalerRegistry{
+		types:      make(map[reflect.Type]func(any) ([]byte, error), len(current.types)+1),
+		interfaces: make([]interfaceMarshaler, 0, len(current.interfaces)+1),
+	}
+	for k, v := range current.types {
+		updated.types[k] = v
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	marshal := func(v any) ([]byte, error) {
+		return fn(v.(T))
+	}
+
+	replaced := false
+	for _, marshaler := range current.interfaces {
+		if marshaler.iface == t {
+			marshaler.fn, replaced = marshal, true
+		}
+		updated.interfaces = append(updated.interfaces, marshaler)
+	}
+
+	switch {
+	case t.Kind() != reflect.Interface:
+		updated.types[t] = marshal
+	case !replaced:
+		updated.interfaces = append(updated.interfaces, interfaceMarshaler{iface: t, fn: marshal})
+	}
+
+	dataMarshalers.Store(updated)
+}
+
+// marshalData encodes data with the registered marshalers, reporting false if none applies to data or
+// to the elements of a data slice or array.
+func marshalData(data any) (json.RawMessage, bool, error) {
+
+	registered, _ := dataMarshalers.Load().(dataMarshalerRegistry)
+	if len(registered.types) == 0 && len(registered.interfaces) == 0 || data == nil {
+		return nil, false, nil
+	}
+
+	v := reflect.ValueOf(data)
+
+	if fn := registered.lookup(v.Type()); fn != nil {
+		b, err := fn(data)
+		return b, err == nil, err
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Kind() == reflect.Slice && v.IsNil() {
+		return nil, false, nil
+	}
+
+	// Only elements of a registered type, directly or behind an interface, are encoded differently
+	elemFn := func(elem reflect.Value) func(any) ([]byte, error) {
+		if elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				return nil
+			}
+			elem = elem.Elem()
+		}
+		return registered.lookup(elem.Type())
+	}
+
+	matched := false
+	for i := 0; i < v.Len() && !matched; i++ {
+		matched = elemFn(v.Index(i)) != nil
+	}
+	if !matched {
+		return nil, false, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		elem := v.Index(i)
+
+		var b []byte
+		var err error
+		if fn := elemFn(elem); fn != nil {
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			b, err = fn(elem.Interface())
+		} else {
+			b, err = json.Marshal(elem.Interface())
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		buf.Write(b)
+	}
+
+	buf.WriteByte(']')
+
+	return buf.Bytes(), true, nil
+}
+
+// encodeData encodes data as Data is encoded in envelopes: with the registered marshalers, or else with
+// encoding/json.
+func encodeData(data any) ([]byte, error) {
+
+	if b, ok, err := marshalData(data); ok || err != nil {
+		return b, err
+	}
+
+	return json.Marshal(data)
+}
@@ -0,0 +1,132 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// money is a data type whose wire representation is overridden by a registered marshaler.
+type money struct {
+	Cents int64
+}
+
+// MarshalJSON encodes money as a number, which the registered marshaler must take precedence over.
+func (m money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Cents)
+}
+
+// labeled is an interface whose implementations are encoded by a marshaler registered for the interface.
+type labeled interface {
+	skuLabel() string
+}
+
+// sku is a data type implementing labeled.
+type sku string
+
+func (s sku) skuLabel() string {
+	return "sku:" + string(s)
+}
+
+func init() {
+	httpresponse.RegisterDataMarshaler(func(m money) ([]byte, error) {
+		return json.Marshal(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100))
+	})
+	httpresponse.RegisterDataMarshaler(func(l labeled) ([]byte, error) {
+		return json.Marshal(l.skuLabel())
+	})
+}
+
+// TestRegisterDataMarshaler tests that a registered marshaler encodes Data of its type, taking precedence
+// over the type's own MarshalJSON.
+func TestRegisterDataMarshaler(t *testing.T) {
+	expected := `{"data":"12.50","message":"","success":true}`
+	response := &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: money{Cents: 1250}}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+// TestRegisterDataMarshaler_SliceElements tests that elements of a registered type are encoded with the
+// registered marshaler, while other elements keep their encoding.
+func TestRegisterDataMarshaler_SliceElements(t *testing.T) {
+	expected := `{"data":["1.00","0.05"],"message":"","success":true}`
+	response := &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: []money{{Cents: 100}, {Cents: 5}}}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+
+	expected = `{"data":["1.00",2,null],"message":"","success":true}`
+	response = &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: []any{money{Cents: 100}, 2, nil}}
+
+	body, err = response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+// TestRegisterDataMarshaler_Interface tests that a marshaler registered for an interface encodes Data, and
+// elements of a Data slice, implementing it.
+func TestRegisterDataMarshaler_Interface(t *testing.T) {
+	expected := `{"data":"sku:A1","message":"","success":true}`
+	response := &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: sku("A1")}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+
+	expected = `{"data":["sku:B2","1.00"],"message":"","success":true}`
+	response = &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: []any{sku("B2"), money{Cents: 100}}}
+
+	body, err = response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+// TestRegisterDataMarshaler_Passthrough tests that data of other types keeps its encoding/json encoding.
+func TestRegisterDataMarshaler_Passthrough(t *testing.T) {
+	expected := `{"data":{"cents":3},"message":"","success":true}`
+	response := &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: map[string]int{"cents": 3}}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+
+	expected = `{"data":[1,2],"message":"","success":true}`
+	response = &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Success: true, Data: []int{1, 2}}
+
+	body, err = response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
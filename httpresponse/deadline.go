@@ -0,0 +1,35 @@
+package httpresponse
+
+import (
+	"context"
+	"time"
+)
+
+// KeyDeadline is the Extra key under which SetDeadlineFromContext advertises the deadline of the request.
+const KeyDeadline = "deadline"
+
+// SetDeadlineFromContext advertises the deadline of ctx under the "deadline" Extra key, formatted as RFC 3339
+// in UTC. If ctx has no deadline, the key is omitted.
+//
+// Parameters:
+//   - ctx: The context of the request.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetDeadlineFromContext(ctx context.Context) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyDeadline] = deadline.UTC().Format(time.RFC3339)
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,43 @@
+package httpresponse_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetDeadlineFromContext tests that the context deadline is advertised in Extra.
+func TestSetDeadlineFromContext(t *testing.T) {
+	deadline := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetDeadlineFromContext(ctx),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := response.Extra[httpresponse.KeyDeadline]; got != "2030-01-02T02:04:05Z" {
+		t.Errorf("Expected deadline 2030-01-02T02:04:05Z, got %v", got)
+	}
+}
+
+// TestSetDeadlineFromContext_NoDeadline tests that the key is omitted without a context deadline.
+func TestSetDeadlineFromContext_NoDeadline(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetDeadlineFromContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := response.Extra[httpresponse.KeyDeadline]; ok {
+		t.Errorf("Expected no deadline, got %v", response.Extra)
+	}
+}
@@ -0,0 +1,54 @@
+package httpresponse
+
+import (
+	"sync/atomic"
+)
+
+// Logger receives diagnostic messages from the package, with the same signature as log.Printf.
+type Logger func(format string, args ...any)
+
+var debugMode int32
+
+// SetDebug enables or disables debug mode. In debug mode, features that would otherwise hide internal
+// details from clients, such as error translation, include them in responses. Debug mode is off by default
+// and must never be enabled in production.
+//
+// Parameters:
+//   - enabled: A boolean enabling (true) or disabling (false) debug mode.
+func SetDebug(enabled bool) {
+
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&debugMode, v)
+}
+
+// Debug reports whether debug mode is enabled.
+//
+// Returns:
+//   - bool: True if debug mode is enabled.
+func Debug() bool {
+	return atomic.LoadInt32(&debugMode) == 1
+}
+
+// SetLogger installs the logger hook receiving the package's diagnostic messages; nil discards them,
+// which is the default. It replaces the Logger of the package-wide dependencies (see SetDeps).
+//
+// Parameters:
+//   - l: The logger, such as log.Printf.
+func SetLogger(l Logger) {
+
+	depsMu.Lock()
+	defer depsMu.Unlock()
+
+	deps := packageDeps.Load().(Deps)
+	deps.Logger = l
+	packageDeps.Store(deps)
+}
+
+// logf sends a diagnostic message to the package-wide logger, if one is installed.
+func logf(format string, args ...any) {
+	CurrentDeps().logf(format, args...)
+}
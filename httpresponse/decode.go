@@ -0,0 +1,139 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// KeyTimestamp is the Extra key carrying the time an envelope was produced, checked by ValidateTimestamp.
+const KeyTimestamp = "timestamp"
+
+var (
+	// ErrStale is returned when decoding an envelope whose timestamp is older than the maximum age.
+	ErrStale = errors.New("httpresponse: envelope timestamp is stale")
+
+	// ErrFromFuture is returned when decoding an envelope whose timestamp is ahead of the clock by more than
+	// the tolerated skew.
+	ErrFromFuture = errors.New("httpresponse: envelope timestamp is in the future")
+
+	// ErrMissingTimestamp is returned when decoding an envelope without a timestamp while RequireTimestamp is set.
+	ErrMissingTimestamp = errors.New("httpresponse: envelope timestamp is missing")
+
+	// ErrInvalidTimestamp is returned when decoding an envelope whose timestamp is neither an RFC 3339 string
+	// nor a number of Unix seconds.
+	ErrInvalidTimestamp = errors.New("httpresponse: invalid envelope timestamp")
+)
+
+// DecodeOption configures the validation of decoded envelopes, such as by FromJSON.
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions holds the configuration of a decoding.
+type decodeOptions struct {
+	validateTimestamp bool
+	maxAge, maxSkew   time.Duration
+	clock             func() time.Time
+	requireTimestamp  bool
+	deps              *Deps
+}
+
+// ValidateTimestamp rejects envelopes whose "timestamp" Extra value, an RFC 3339 string or a number of Unix
+// seconds, is outside tolerance: decoding fails with ErrStale if it is older than maxAge, or with
+// ErrFromFuture if it is ahead of the clock, each allowing for maxSkew of clock skew between the services.
+// Envelopes without a timestamp are accepted unless RequireTimestamp is also set.
+//
+// Parameters:
+//   - maxAge: The maximum age of an envelope.
+//   - maxSkew: The tolerated clock skew.
+//   - clock: The clock of the consumer; nil uses the Clock dependency (see Deps and DecodeDeps).
+//
+// Returns:
+//   - DecodeOption: The option.
+func ValidateTimestamp(maxAge, maxSkew time.Duration, clock func() time.Time) DecodeOption {
+	return func(decodeOptions *decodeOptions) {
+
+		decodeOptions.validateTimestamp = true
+		decodeOptions.maxAge, decodeOptions.maxSkew, decodeOptions.clock = maxAge, maxSkew, clock
+	}
+}
+
+// RequireTimestamp makes ValidateTimestamp reject envelopes without a timestamp with ErrMissingTimestamp.
+//
+// Returns:
+//   - DecodeOption: The option.
+func RequireTimestamp() DecodeOption {
+	return func(decodeOptions *decodeOptions) {
+		decodeOptions.requireTimestamp = true
+	}
+}
+
+// DecodeDeps overrides the package-wide dependencies for this decoding, field by field, like
+// HTTPResponseBuilder.SetDeps does for a response: nil fields of deps keep the package-wide dependency.
+//
+// Parameters:
+//   - deps: The dependencies of the decoding, such as the Clock of ValidateTimestamp.
+//
+// Returns:
+//   - DecodeOption: The option.
+func DecodeDeps(deps Deps) DecodeOption {
+	return func(decodeOptions *decodeOptions) {
+		decodeOptions.deps = &deps
+	}
+}
+
+// validate checks the decoded Extra of an envelope against the options.
+func (decodeOptions *decodeOptions) validate(extra map[string]any) error {
+
+	if !decodeOptions.validateTimestamp {
+		return nil
+	}
+
+	raw, ok := extra[KeyTimestamp]
+	if !ok || raw == nil {
+		if decodeOptions.requireTimestamp {
+			return ErrMissingTimestamp
+		}
+		return nil
+	}
+
+	timestamp, err := parseTimestamp(raw)
+	if err != nil {
+		return err
+	}
+
+	clock := decodeOptions.clock
+	if clock == nil {
+		clock = effectiveDeps(decodeOptions.deps).Clock
+	}
+	now := clock()
+
+	if timestamp.After(now.Add(decodeOptions.maxSkew)) {
+		return fmt.Errorf("%w: %s is ahead of %s", ErrFromFuture, timestamp.Format(time.RFC3339), now.Format(time.RFC3339))
+	}
+	if now.Sub(timestamp) > decodeOptions.maxAge+decodeOptions.maxSkew {
+		return fmt.Errorf("%w: %s is older than %s", ErrStale, timestamp.Format(time.RFC3339), decodeOptions.maxAge)
+	}
+
+	return nil
+}
+
+// parseTimestamp parses a decoded timestamp: an RFC 3339 string or a number of Unix seconds.
+func parseTimestamp(raw any) (time.Time, error) {
+
+	switch v := raw.(type) {
+	case string:
+		if timestamp, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return timestamp, nil
+		}
+	case float64:
+		return time.Unix(0, int64(v*float64(time.Second))), nil
+	case json.Number:
+		if seconds, err := strconv.ParseFloat(string(v), 64); err == nil {
+			return time.Unix(0, int64(seconds*float64(time.Second))), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTimestamp, raw)
+}
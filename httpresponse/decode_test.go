@@ -0,0 +1,46 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestValidateTimestamp tests the tolerance of envelope timestamps.
+func TestValidateTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		body    string
+		require bool
+		want    error
+	}{
+		{"fresh", `{"success":true,"timestamp":"2024-05-01T11:59:00Z"}`, false, nil},
+		{"stale", `{"success":true,"timestamp":"2024-05-01T11:50:00Z"}`, false, httpresponse.ErrStale},
+		{"future", `{"success":true,"timestamp":"2024-05-01T12:01:00Z"}`, false, httpresponse.ErrFromFuture},
+		{"within skew", `{"success":true,"timestamp":"2024-05-01T12:00:20Z"}`, false, nil},
+		{"unix seconds", `{"success":true,"timestamp":1714564740}`, false, nil},
+		{"invalid", `{"success":true,"timestamp":"yesterday"}`, false, httpresponse.ErrInvalidTimestamp},
+		{"absent, optional", `{"success":true}`, false, nil},
+		{"absent, required", `{"success":true}`, true, httpresponse.ErrMissingTimestamp},
+	}
+
+	for _, tt := range tests {
+		opts := []httpresponse.DecodeOption{httpresponse.ValidateTimestamp(5*time.Minute, 30*time.Second, clock)}
+		if tt.require {
+			opts = append(opts, httpresponse.RequireTimestamp())
+		}
+
+		_, err := httpresponse.FromJSON[int, string, map[string]interface{}, int]([]byte(tt.body), opts...)
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
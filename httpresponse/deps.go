@@ -0,0 +1,164 @@
+package httpresponse
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	mathrand "math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Deps holds the dependencies the package reads instead of calling the standard library directly.
+// Nil fields fall back to the package default, and then to the standard implementation.
+type Deps struct {
+	Clock  func() time.Time // Returns the current time, such as for Expires headers and memoization; time.Now by default.
+	IDGen  func() string    // Returns a new unique identifier; 32 random hexadecimal characters by default.
+	Rand   func() float64   // Returns a pseudo-random number in [0.0, 1.0), such as for sampling; math/rand by default.
+	Logger Logger           // Receives diagnostic messages; discarded by default.
+}
+
+var (
+	depsMu      sync.Mutex
+	packageDeps atomic.Value // Deps
+)
+
+func init() {
+	packageDeps.Store(Deps{})
+}
+
+// SetDeps replaces the package-wide dependencies. Responses built with HTTPResponseBuilder.SetDeps
+// override them field by field.
+//
+// Parameters:
+//   - deps: The package-wide dependencies.
+func SetDeps(deps Deps) {
+
+	depsMu.Lock()
+	defer depsMu.Unlock()
+
+	packageDeps.Store(deps)
+}
+
+// CurrentDeps returns the package-wide dependencies, with nil fields set to the standard implementations,
+// except for Logger, which stays nil if unset.
+//
+// Returns:
+//   - Deps: The effective package-wide dependencies.
+func CurrentDeps() Deps {
+	return packageDeps.Load().(Deps).withDefaults()
+}
+
+// WithDeps replaces the package-wide dependencies for the duration of a test, restoring the previous ones
+// on cleanup. Tests replacing the dependencies must not run in parallel with each other.
+//
+// Parameters:
+//   - t: The test, such as *testing.T.
+//   - deps: The package-wide dependencies during the test.
+//
+// Example usage:
+//
+//	httpresponse.WithDeps(t, httpresponse.Deps{Clock: func() time.Time { return fixed }})
+func WithDeps(t interface{ Cleanup(func()) }, deps Deps) {
+
+	depsMu.Lock()
+	previous := packageDeps.Load().(Deps)
+	packageDeps.Store(deps)
+	depsMu.Unlock()
+
+	t.Cleanup(func() {
+		SetDeps(previous)
+	})
+}
+
+// SetDeps overrides the package-wide dependencies for this response, field by field: nil fields of deps
+// keep the package-wide dependency.
+//
+// Parameters:
+//   - deps: The dependencies of the response.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetDeps(deps Deps) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Deps = &deps
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// deps returns the effective dependencies of the response.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) deps() Deps {
+	return effectiveDeps(httpResponseOptions.Deps)
+}
+
+// effectiveDeps returns the package-wide dependencies overridden by the non-nil fields of deps, if any.
+func effectiveDeps(deps *Deps) Deps {
+
+	if deps == nil {
+		return CurrentDeps()
+	}
+
+	return deps.override(packageDeps.Load().(Deps)).withDefaults()
+}
+
+// depsProvider is implemented by responses carrying their own dependencies, such as HTTPResponseOptions.
+type depsProvider interface {
+	deps() Deps
+}
+
+// depsOf returns the effective dependencies of responder, or the package-wide ones if it carries none.
+func depsOf(responder any) Deps {
+
+	if provider, ok := responder.(depsProvider); ok {
+		return provider.deps()
+	}
+
+	return CurrentDeps()
+}
+
+// override returns base with the non-nil fields of deps.
+func (deps Deps) override(base Deps) Deps {
+
+	if deps.Clock != nil {
+		base.Clock = deps.Clock
+	}
+	if deps.IDGen != nil {
+		base.IDGen = deps.IDGen
+	}
+	if deps.Rand != nil {
+		base.Rand = deps.Rand
+	}
+	if deps.Logger != nil {
+		base.Logger = deps.Logger
+	}
+
+	return base
+}
+
+// withDefaults returns deps with nil fields, except Logger, set to the standard implementations.
+func (deps Deps) withDefaults() Deps {
+	return deps.override(Deps{Clock: time.Now, IDGen: randomID, Rand: mathrand.Float64})
+}
+
+// logf sends a diagnostic message to the logger, if one is set.
+func (deps Deps) logf(format string, args ...any) {
+
+	if deps.Logger != nil {
+		deps.Logger(format, args...)
+	}
+}
+
+// randomID returns 16 random bytes in hexadecimal.
+func randomID() string {
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// Fall back to the pseudo-random generator rather than failing the caller
+		for i := range b {
+			b[i] = byte(mathrand.Intn(256))
+		}
+	}
+
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,146 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// fixedClock returns a clock always reporting t.
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+// TestSetDeps tests that per-response dependencies take precedence over the package-wide ones.
+func TestSetDeps(t *testing.T) {
+	packageTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	responseTime := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var logged []string
+	httpresponse.WithDeps(t, httpresponse.Deps{
+		Clock:  fixedClock(packageTime),
+		Logger: func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) },
+	})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			CachePublic(time.Minute).
+			CacheExpires(),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := packageTime.Add(time.Minute).Format(http.TimeFormat)
+	if expires := response.Header().Get("Expires"); expires != expected {
+		t.Errorf("Expected the package clock %s, got %s", expected, expires)
+	}
+
+	response, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			CachePublic(time.Minute).
+			CacheExpires().
+			SetDeps(httpresponse.Deps{Clock: fixedClock(responseTime)}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected = responseTime.Add(time.Minute).Format(http.TimeFormat)
+	if expires := response.Header().Get("Expires"); expires != expected {
+		t.Errorf("Expected the response clock %s, got %s", expected, expires)
+	}
+
+	// Fields not overridden by the response keep the package-wide dependency
+	_, _ = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetDeps(httpresponse.Deps{Clock: fixedClock(responseTime)}).
+			CachePublic(time.Minute).
+			NoStore(),
+	)
+	if len(logged) != 1 {
+		t.Errorf("Expected the package logger to receive 1 message, got %v", logged)
+	}
+}
+
+// TestSetDeps_Logger tests that error responses are logged with the logger of their own dependencies.
+func TestSetDeps_Logger(t *testing.T) {
+	var packageLogged, responseLogged []string
+	httpresponse.WithDeps(t, httpresponse.Deps{
+		Logger: func(format string, args ...any) { packageLogged = append(packageLogged, fmt.Sprintf(format, args...)) },
+	})
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](errors.New("boom")).
+			SetDeps(httpresponse.Deps{
+				Logger: func(format string, args ...any) {
+					responseLogged = append(responseLogged, fmt.Sprintf(format, args...))
+				},
+			}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(responseLogged) != 1 || !strings.Contains(responseLogged[0], "boom") {
+		t.Errorf("Expected the response logger to receive the error, got %v", responseLogged)
+	}
+	if len(packageLogged) != 0 {
+		t.Errorf("Expected the package logger to receive nothing, got %v", packageLogged)
+	}
+}
+
+// TestDecodeDeps tests that ValidateTimestamp without a clock uses the clock of the decoding dependencies.
+func TestDecodeDeps(t *testing.T) {
+	httpresponse.WithDeps(t, httpresponse.Deps{Clock: fixedClock(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))})
+
+	body := []byte(`{"success":true,"timestamp":"2024-05-01T11:59:00Z"}`)
+	opts := []httpresponse.DecodeOption{
+		httpresponse.ValidateTimestamp(5*time.Minute, 30*time.Second, nil),
+		httpresponse.DecodeDeps(httpresponse.Deps{Clock: fixedClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))}),
+	}
+
+	if _, err := httpresponse.FromJSON[int, string, map[string]interface{}, int](body, opts...); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	_, err := httpresponse.FromJSON[int, string, map[string]interface{}, int](body, opts[0])
+	if !errors.Is(err, httpresponse.ErrStale) {
+		t.Errorf("Expected %v with the package clock, got %v", httpresponse.ErrStale, err)
+	}
+}
+
+// TestWithDeps tests that the package-wide dependencies are restored on cleanup.
+func TestWithDeps(t *testing.T) {
+	fixed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("swap", func(t *testing.T) {
+		httpresponse.WithDeps(t, httpresponse.Deps{
+			Clock: fixedClock(fixed),
+			IDGen: func() string { return "id-1" },
+			Rand:  func() float64 { return 0.5 },
+		})
+
+		deps := httpresponse.CurrentDeps()
+		if !deps.Clock().Equal(fixed) || deps.IDGen() != "id-1" || deps.Rand() != 0.5 {
+			t.Errorf("Expected the swapped dependencies")
+		}
+	})
+
+	deps := httpresponse.CurrentDeps()
+	if deps.Clock().Equal(fixed) {
+		t.Errorf("Expected the clock to be restored")
+	}
+	if id := deps.IDGen(); len(id) != 32 || id == "id-1" {
+		t.Errorf("Expected a random 32-character ID, got %q", id)
+	}
+	if r := deps.Rand(); r < 0 || r >= 1 {
+		t.Errorf("Expected a number in [0, 1), got %v", r)
+	}
+}
@@ -0,0 +1,73 @@
+package httpresponse
+
+import "net/http"
+
+// Options holds the settings of a response built with BuildDirect. Zero fields leave the corresponding
+// response fields at their zero value, except Success, which defaults to true as with HTTPResponse.
+type Options[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	Success *bool       // The success status; nil means true.
+	Message string      // The message of the response.
+	Code    C           // The code of the response.
+	SubCode string      // The fine-grained code refining Code, as set by SetSubCode.
+	Data    D           // The data payload of the response.
+	Total   T           // The total count, validated as by SetTotal.
+	Extra   E           // The additional metadata of the response.
+	Headers http.Header // The HTTP headers sent along with the response, copied as by SetHeader.
+
+	AllowNegativeTotal bool   // Accepts a negative Total, as AllowNegativeTotal does.
+	MaxTotal           uint64 // The largest accepted Total, as set by SetMaxTotal; zero applies the default check.
+}
+
+// BuildDirect builds a response from opts, equivalent to building a builder with the corresponding setters
+// through rpsutil.Build, but without allocating a builder or option closures. Since it bypasses
+// rpsutil.Build, build interceptors, statistics and the build error hook are not notified.
+//
+// Parameters:
+//   - opts: The settings of the response.
+//
+// Returns:
+//   - HTTPResponseOptions[C, D, E, T]: The response.
+//   - error: ErrNegativeTotal or ErrTotalOutOfRange if Total is invalid; otherwise, nil.
+//
+// Example usage:
+//
+//	response, err := httpresponse.BuildDirect(httpresponse.Options[int, Item, map[string]any, int]{
+//		Code: 200, Data: item,
+//	})
+func BuildDirect[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](opts Options[C, D, E, T]) (HTTPResponseOptions[C, D, E, T], error) {
+
+	response := HTTPResponseOptions[C, D, E, T]{
+		Success:            opts.Success == nil || *opts.Success,
+		Message:            opts.Message,
+		Code:               opts.Code,
+		SubCode:            opts.SubCode,
+		Data:               opts.Data,
+		Total:              opts.Total,
+		Extra:              opts.Extra,
+		AllowNegativeTotal: opts.AllowNegativeTotal,
+		MaxTotal:           opts.MaxTotal,
+	}
+
+	if len(opts.Headers) > 0 {
+		response.Headers = make(http.Header, len(opts.Headers))
+		for key, values := range opts.Headers {
+			response.Headers[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+
+	if err := validateTotal(&response); err != nil {
+		return HTTPResponseOptions[C, D, E, T]{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,81 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestBuildDirect tests that BuildDirect is equivalent to the builder path, including Extra, SubCode and
+// Headers.
+func TestBuildDirect(t *testing.T) {
+	extra := map[string]interface{}{"request_id": "abc"}
+
+	expected, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetSuccess(false).
+			SetMessage("not found").
+			SetCode(404).
+			SetSubCode("USER_NOT_FOUND").
+			SetData("payload").
+			SetTotal(3).
+			SetExtra(extra).
+			SetHeader("Retry-After", "30"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	success := false
+	response, err := httpresponse.BuildDirect(httpresponse.Options[int, string, map[string]interface{}, int]{
+		Success: &success,
+		Message: "not found",
+		Code:    404,
+		SubCode: "USER_NOT_FOUND",
+		Data:    "payload",
+		Total:   3,
+		Extra:   extra,
+		Headers: http.Header{"retry-after": {"30"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(&response, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, response)
+	}
+}
+
+// TestBuildDirect_Defaults tests that zero options yield the defaults of the builder path.
+func TestBuildDirect_Defaults(t *testing.T) {
+	expected, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, []int, map[string]interface{}, uint]](
+		httpresponse.HTTPResponse[string, []int, map[string]interface{}, uint](),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := httpresponse.BuildDirect(httpresponse.Options[string, []int, map[string]interface{}, uint]{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(&response, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, response)
+	}
+}
+
+// TestBuildDirect_InvalidTotal tests that totals are validated as by SetTotal.
+func TestBuildDirect_InvalidTotal(t *testing.T) {
+	if _, err := httpresponse.BuildDirect(httpresponse.Options[int, string, map[string]interface{}, int]{Total: -1}); !errors.Is(err, httpresponse.ErrNegativeTotal) {
+		t.Errorf("Expected ErrNegativeTotal, got %v", err)
+	}
+
+	if _, err := httpresponse.BuildDirect(httpresponse.Options[int, string, map[string]interface{}, int]{Total: -1, AllowNegativeTotal: true}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
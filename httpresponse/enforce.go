@@ -0,0 +1,177 @@
+package httpresponse
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// EnforceEnvelope returns a handler that captures the response written by next and, if it is a raw JSON value
+// rather than an envelope, wraps it as the Data of one. The code of the wrapping envelope is the status written
+// by next (200 if none), the response is successful below 400, and failures carry the status text as message.
+//
+// Responses are passed through unchanged when they are already envelopes (responses of this package written with
+// WriteJSON or SeqResponse.WriteJSON, whatever their key names, and other JSON objects with a boolean "success"
+// key), empty, not JSON (a Content-Type other than "application/json", including problem+json) or not valid
+// JSON. A response without Content-Type is treated as JSON if its body is valid JSON.
+//
+// The response of next is buffered until it returns, so flushes and streams reach the client only at the end.
+//
+// Parameters:
+//   - next: The handler whose responses are enforced.
+//
+// Returns:
+//   - http.Handler: The enforcing handler.
+func EnforceEnvelope(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		capture := &envelopeWriter{w: w}
+		next.ServeHTTP(capture, r)
+
+		if !capture.needsEnvelope() {
+			capture.flush()
+			return
+		}
+
+		status := capture.statusCode()
+		response := &HTTPResponseOptions[int, json.RawMessage, map[string]any, int]{
+			Success: status < http.StatusBadRequest,
+			Code:    status,
+			Data:    json.RawMessage(bytes.TrimSpace(capture.buf.Bytes())),
+		}
+		if !response.Success {
+			response.Message = http.StatusText(status)
+		}
+
+		w.Header().Del("Content-Length")
+		// WriteJSON logs its failures with a preview of the envelope
+		_ = WriteJSON(w, response)
+	})
+}
+
+// envelopeWriter captures the status and body written by a handler, sharing the headers of the
+// underlying writer.
+type envelopeWriter struct {
+	w           http.ResponseWriter
+	status      int
+	wroteHeader bool
+	enveloped   bool
+	buf         bytes.Buffer
+}
+
+// envelopeMarker is implemented by writers that need to know that a response of this package was written to them.
+type envelopeMarker interface {
+	markEnvelope()
+}
+
+// packageResponse is implemented by the responses of this package, which are envelopes or deliberately bare.
+type packageResponse interface {
+	packageResponse()
+}
+
+// packageResponse marks HTTPResponseOptions as a response of this package.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) packageResponse() {}
+
+// markEnvelope tells w, if it is an envelopeMarker, that responder, if it is a response of this package, is
+// written to it.
+func markEnvelope(w http.ResponseWriter, responder Responder) {
+
+	if _, ok := responder.(packageResponse); !ok {
+		return
+	}
+
+	if marker, ok := w.(envelopeMarker); ok {
+		marker.markEnvelope()
+	}
+}
+
+// markEnvelope records that a response of this package was written, so that it is never wrapped again.
+func (envelopeWriter *envelopeWriter) markEnvelope() {
+	envelopeWriter.enveloped = true
+}
+
+// Header returns the headers of the underlying writer.
+func (envelopeWriter *envelopeWriter) Header() http.Header {
+	return envelopeWriter.w.Header()
+}
+
+// WriteHeader records the first final status; informational statuses are sent right away.
+func (envelopeWriter *envelopeWriter) WriteHeader(status int) {
+
+	if status < http.StatusOK {
+		envelopeWriter.w.WriteHeader(status)
+		return
+	}
+
+	if !envelopeWriter.wroteHeader {
+		envelopeWriter.wroteHeader = true
+		envelopeWriter.status = status
+	}
+}
+
+// Write buffers p, recording a 200 status if none was written.
+func (envelopeWriter *envelopeWriter) Write(p []byte) (int, error) {
+
+	if !envelopeWriter.wroteHeader {
+		envelopeWriter.WriteHeader(http.StatusOK)
+	}
+
+	return envelopeWriter.buf.Write(p)
+}
+
+// statusCode returns the recorded status, 200 if none was written.
+func (envelopeWriter *envelopeWriter) statusCode() int {
+
+	if !envelopeWriter.wroteHeader {
+		return http.StatusOK
+	}
+
+	return envelopeWriter.status
+}
+
+// needsEnvelope reports whether the captured response is a JSON value that is not an envelope.
+func (envelopeWriter *envelopeWriter) needsEnvelope() bool {
+
+	if envelopeWriter.enveloped {
+		return false
+	}
+
+	body := bytes.TrimSpace(envelopeWriter.buf.Bytes())
+	if len(body) == 0 || !json.Valid(body) {
+		return false
+	}
+
+	if contentType := envelopeWriter.Header().Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != contentTypeJSON {
+			return false
+		}
+	}
+
+	var object map[string]json.RawMessage
+	if json.Unmarshal(body, &object) != nil {
+		return true
+	}
+
+	var success bool
+
+	return json.Unmarshal(object[KeySuccess], &success) != nil
+}
+
+// flush writes the captured response unchanged to the underlying writer.
+func (envelopeWriter *envelopeWriter) flush() {
+
+	if envelopeWriter.wroteHeader {
+		envelopeWriter.w.WriteHeader(envelopeWriter.status)
+	}
+
+	if envelopeWriter.buf.Len() == 0 {
+		return
+	}
+
+	if _, err := envelopeWriter.w.Write(envelopeWriter.buf.Bytes()); err != nil {
+		logf("httpresponse: write enforced response: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package httpresponse_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// serveEnforced serves a request with handler behind EnforceEnvelope.
+func serveEnforced(handler http.HandlerFunc) *httptest.ResponseRecorder {
+
+	recorder := httptest.NewRecorder()
+	httpresponse.EnforceEnvelope(handler).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return recorder
+}
+
+// TestEnforceEnvelope_Raw tests that raw JSON values are wrapped into an envelope with the inferred code.
+func TestEnforceEnvelope_Raw(t *testing.T) {
+	recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "abc")
+		_, _ = w.Write([]byte(`[1,2,3]` + "\n"))
+	})
+
+	if want := `{"code":200,"data":[1,2,3],"message":"","success":true}`; recorder.Body.String() != want {
+		t.Errorf("Expected %s, got %s", want, recorder.Body.String())
+	}
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("X-Request-Id") != "abc" {
+		t.Errorf("Expected the handler headers to be kept, got %v", recorder.Header())
+	}
+	if recorder.Header().Get("Content-Length") != "55" {
+		t.Errorf("Expected the envelope length as Content-Length, got %q", recorder.Header().Get("Content-Length"))
+	}
+}
+
+// TestEnforceEnvelope_RawError tests that raw JSON written with an error status is wrapped into a failure.
+func TestEnforceEnvelope_RawError(t *testing.T) {
+	recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"no such item"}`))
+	})
+
+	want := `{"code":404,"data":{"error":"no such item"},"message":"Not Found","success":false}`
+	if recorder.Body.String() != want {
+		t.Errorf("Expected %s, got %s", want, recorder.Body.String())
+	}
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", recorder.Code)
+	}
+}
+
+// TestEnforceEnvelope_NonBooleanSuccess tests that objects whose success key is not a boolean are wrapped.
+func TestEnforceEnvelope_NonBooleanSuccess(t *testing.T) {
+	recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":"yes"}`))
+	})
+
+	if want := `{"code":200,"data":{"success":"yes"},"message":"","success":true}`; recorder.Body.String() != want {
+		t.Errorf("Expected %s, got %s", want, recorder.Body.String())
+	}
+}
+
+// TestEnforceEnvelope_Enveloped tests that envelopes are not wrapped twice.
+func TestEnforceEnvelope_Enveloped(t *testing.T) {
+	recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+		_ = httpresponse.WriteJSON(w, &httpresponse.HTTPResponseOptions[int, string, map[string]any, int]{
+			Success: false,
+			Message: "conflict",
+			Code:    http.StatusConflict,
+		})
+	})
+
+	if want := `{"code":409,"message":"conflict","success":false}`; recorder.Body.String() != want {
+		t.Errorf("Expected %s, got %s", want, recorder.Body.String())
+	}
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", recorder.Code)
+	}
+}
+
+// TestEnforceEnvelope_RenamedSuccessKey tests that envelopes of this package with a renamed success key are
+// not wrapped twice.
+func TestEnforceEnvelope_RenamedSuccessKey(t *testing.T) {
+	recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, int, map[string]any, int]](
+			httpresponse.HTTPResponse[int, int, map[string]any, int]().SetSuccessKey("ok").SetData(1),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_ = httpresponse.WriteJSON(w, response)
+	})
+
+	if want := `{"data":1,"message":"","ok":true}`; recorder.Body.String() != want {
+		t.Errorf("Expected %s, got %s", want, recorder.Body.String())
+	}
+}
+
+// TestEnforceEnvelope_PassThrough tests that empty, non-JSON and invalid JSON responses are left unchanged.
+func TestEnforceEnvelope_PassThrough(t *testing.T) {
+	for name, test := range map[string]struct {
+		contentType string
+		status      int
+		body        string
+	}{
+		"empty":      {"", http.StatusNoContent, ""},
+		"text":       {"text/plain", http.StatusOK, `"quoted"`},
+		"problem":    {"application/problem+json", http.StatusBadRequest, `{"title":"bad"}`},
+		"invalid":    {"application/json", http.StatusOK, `{"broken"`},
+		"undeclared": {"", http.StatusOK, `not json`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			recorder := serveEnforced(func(w http.ResponseWriter, r *http.Request) {
+				if test.contentType != "" {
+					w.Header().Set("Content-Type", test.contentType)
+				}
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			})
+
+			if recorder.Code != test.status || recorder.Body.String() != test.body {
+				t.Errorf("Expected %d %s, got %d %s", test.status, test.body, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
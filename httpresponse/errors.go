@@ -0,0 +1,282 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// KeyErrors is the Extra key under which responses reporting several errors list them as ErrorDetail values.
+const KeyErrors = "errors"
+
+// GenericErrorMessage is the public message of error responses whose error matches no registered translation.
+const GenericErrorMessage = "An internal error occurred."
+
+// CodedError is implemented by errors that carry their own public code. FromError uses the code of a coded
+// error matching no registered translation.
+type CodedError interface {
+	error
+
+	// ErrorCode returns the public code of the error.
+	ErrorCode() string
+}
+
+// ErrorDetail describes one of several errors reported by a single response, under the "errors" Extra key.
+type ErrorDetail struct {
+	Code    string `json:"code,omitempty"`     // The public code of the error; omitted if the error has none.
+	SubCode string `json:"sub_code,omitempty"` // The fine-grained public code of the error; omitted if the error has none.
+	Message string `json:"message"`            // The public message of the error.
+	Field   string `json:"field,omitempty"`    // The input field the error relates to, if any.
+	Offset  int64  `json:"offset,omitempty"`   // The byte offset in the input the error relates to, if any.
+}
+
+// errorTranslation maps matching internal errors to a public code and message.
+type errorTranslation struct {
+	match   func(error) bool
+	code    string
+	subCode string
+	message string
+}
+
+var (
+	errorTranslationsMu sync.RWMutex
+	errorTranslations   []errorTranslation
+)
+
+// RegisterErrorTranslation registers a translation consulted by FromError. Translations are tried
+// in registration order and the first one whose match function returns true is used.
+//
+// Parameters:
+//   - match: Reports whether the translation applies to an error, typically using errors.Is or errors.As.
+//   - publicCode: The code exposed to clients. It is used as the response code when C is string,
+//     or when C is int and the code is numeric; otherwise the code is left unset.
+//   - publicMessage: The message exposed to clients.
+func RegisterErrorTranslation(match func(error) bool, publicCode, publicMessage string) {
+
+	errorTranslationsMu.Lock()
+	defer errorTranslationsMu.Unlock()
+
+	errorTranslations = append(errorTranslations, errorTranslation{match: match, code: publicCode, message: publicMessage})
+}
+
+// RegisterErrorTranslationWithSubCode registers a translation consulted by FromError, as RegisterErrorTranslation
+// does, that also declares the fine-grained code of matching errors. Typically, publicCode is a coarse category
+// such as "VALIDATION" and publicSubCode the precise condition, such as "EMAIL_TAKEN".
+//
+// Parameters:
+//   - match: Reports whether the translation applies to an error, typically using errors.Is or errors.As.
+//   - publicCode: The code exposed to clients, used as for RegisterErrorTranslation.
+//   - publicSubCode: The fine-grained code exposed to clients as the sub-code of the response.
+//   - publicMessage: The message exposed to clients.
+func RegisterErrorTranslationWithSubCode(match func(error) bool, publicCode, publicSubCode, publicMessage string) {
+
+	errorTranslationsMu.Lock()
+	defer errorTranslationsMu.Unlock()
+
+	errorTranslations = append(errorTranslations, errorTranslation{match: match, code: publicCode, subCode: publicSubCode, message: publicMessage})
+}
+
+// FromError initializes a builder describing a failed response for err. The error message itself is never
+// exposed: the first registered translation matching err supplies the public code and message, and errors
+// matching no translation get GenericErrorMessage, with the code of a CodedError if err implements it.
+// Translations registered with RegisterErrorTranslationWithSubCode also supply the sub-code.
+// The original error is always sent to the logger hook and, only in debug mode, included under the "error"
+// Extra key.
+//
+// Errors joining several errors, such as those returned by errors.Join, are reported leaf by leaf: each leaf
+// of the (possibly nested) join is translated on its own and listed as an ErrorDetail under the "errors"
+// Extra key, the response code and sub-code are those of the leaf with the most severe code (the highest numeric
+// code, or else the first code), and the message summarizes the count, as in "3 errors occurred".
+//
+// Parameters:
+//   - err: The internal error; a nil error yields a builder with default settings.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder describing the failed response, to which further setters can be chained.
+func FromError[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](err error) *HTTPResponseBuilder[C, D, E, T] {
+	return HTTPResponse[C, D, E, T]().setError(err)
+}
+
+// SetError describes a failed response for err, as FromError does, on an existing builder: Success is set
+// to false, the message and code to those of the translation of err, and the error details are added to
+// Extra. Since options are applied in order, setters called afterwards override these fields; use Strict
+// to reject builders that also set Success explicitly.
+//
+// Parameters:
+//   - err: The internal error; a nil error leaves the builder unchanged.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetError(err error) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setError(err)
+}
+
+// setError queues the options describing a failed response for err.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) setError(err error) *HTTPResponseBuilder[C, D, E, T] {
+
+	if err == nil {
+		return httpResponseBuilder
+	}
+
+	httpResponseBuilder.errorSet = true
+
+	// Logged when the response is built, with the logger of its dependencies
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.deps().logf("httpresponse: error response: %v", err)
+
+		return nil
+	})
+
+	if leaves := errorLeaves(err); len(leaves) > 1 {
+		return fromJoinedError(httpResponseBuilder, err, leaves)
+	}
+
+	translation := translateError(err)
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Success = false
+		args.Message = translation.message
+		args.SubCode = translation.subCode
+
+		if code, ok := parseCode[C](translation.code); ok {
+			args.Code = code
+		}
+
+		return nil
+	})
+
+	if Debug() {
+		httpResponseBuilder.addExtra(E{"error": err.Error()})
+	}
+
+	return httpResponseBuilder
+}
+
+// fromJoinedError seeds httpResponseBuilder with the failed response for err, a join of leaves.
+func fromJoinedError[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](httpResponseBuilder *HTTPResponseBuilder[C, D, E, T], err error, leaves []error) *HTTPResponseBuilder[C, D, E, T] {
+
+	details := make([]ErrorDetail, len(leaves))
+
+	var code, subCode string
+	severity := -1
+
+	for i, leaf := range leaves {
+
+		translation := translateError(leaf)
+		details[i] = ErrorDetail{Code: translation.code, SubCode: translation.subCode, Message: translation.message}
+
+		if translation.code == "" {
+			continue
+		}
+
+		// Numeric codes rank by value; non-numeric codes rank below them, in order of appearance
+		leafSeverity := 0
+		if n, err := strconv.Atoi(translation.code); err == nil {
+			leafSeverity = n
+		}
+		if leafSeverity > severity {
+			severity = leafSeverity
+			code = translation.code
+			subCode = translation.subCode
+		}
+	}
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Success = false
+		args.Message = fmt.Sprintf("%d errors occurred", len(leaves))
+		args.SubCode = subCode
+
+		if code, ok := parseCode[C](code); ok {
+			args.Code = code
+		}
+
+		return nil
+	})
+
+	extra := E{KeyErrors: details}
+	if Debug() {
+		extra["error"] = err.Error()
+	}
+
+	return httpResponseBuilder.addExtra(extra)
+}
+
+// errorLeaves flattens err into the leaves of its joins, depth first. An error that does not join
+// other errors is its own single leaf; errors wrapping a join with %w are flattened through.
+func errorLeaves(err error) []error {
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		var leaves []error
+		for _, inner := range e.Unwrap() {
+			if inner != nil {
+				leaves = append(leaves, errorLeaves(inner)...)
+			}
+		}
+		return leaves
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			if innerLeaves := errorLeaves(inner); len(innerLeaves) > 1 {
+				return innerLeaves
+			}
+		}
+	}
+
+	return []error{err}
+}
+
+// translateError returns the first registered translation matching err. Otherwise, it returns the
+// catch-all translation, carrying the code of the first CodedError in err's chain, if any.
+func translateError(err error) errorTranslation {
+
+	errorTranslationsMu.RLock()
+	defer errorTranslationsMu.RUnlock()
+
+	for _, translation := range errorTranslations {
+		if translation.match(err) {
+			return translation
+		}
+	}
+
+	var codedError CodedError
+	if errors.As(err, &codedError) {
+		return errorTranslation{code: codedError.ErrorCode(), message: GenericErrorMessage}
+	}
+
+	return errorTranslation{message: GenericErrorMessage}
+}
+
+// parseCode converts a textual code to the code type C. It fails for empty codes and,
+// when C is int, for non-numeric codes.
+func parseCode[C int | string](s string) (C, bool) {
+
+	var code C
+
+	if s == "" {
+		return code, false
+	}
+
+	switch p := any(&code).(type) {
+	case *string:
+		*p = s
+	case *int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return code, false
+		}
+		*p = n
+	}
+
+	return code, true
+}
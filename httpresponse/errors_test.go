@@ -0,0 +1,231 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// errUnmatched matches no registered translation.
+var errUnmatched = errors.New("unmatched")
+
+// errDuplicateKey stands in for a driver error whose message must not reach clients.
+var errDuplicateKey = errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`)
+
+// errEmailTaken is translated to a coarse code with a fine-grained sub-code.
+var errEmailTaken = errors.New("email taken")
+
+func init() {
+	httpresponse.RegisterErrorTranslation(func(err error) bool {
+		return errors.Is(err, errDuplicateKey)
+	}, "409", "The resource already exists.")
+	httpresponse.RegisterErrorTranslationWithSubCode(func(err error) bool {
+		return errors.Is(err, errEmailTaken)
+	}, "VALIDATION", "EMAIL_TAKEN", "The email address is already registered.")
+}
+
+// TestFromError_Translation tests that a matched translation supplies the public code and message.
+func TestFromError_Translation(t *testing.T) {
+	var logged []string
+	httpresponse.SetLogger(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	defer httpresponse.SetLogger(nil)
+
+	err := fmt.Errorf("create user: %w", errDuplicateKey)
+
+	response, buildErr := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](err),
+	)
+	if buildErr != nil {
+		t.Fatalf("Expected no error, got %v", buildErr)
+	}
+
+	if response.Success {
+		t.Error("Expected Success to be false")
+	}
+	if response.Code != 409 {
+		t.Errorf("Expected Code to be 409, got %v", response.Code)
+	}
+	if response.Message != "The resource already exists." {
+		t.Errorf("Expected public message, got %v", response.Message)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "pq: duplicate key") {
+		t.Errorf("Expected the original error to be logged, got %v", logged)
+	}
+
+	// String codes receive the public code verbatim
+	stringCoded, buildErr := rpsutil.Build[httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]](
+		httpresponse.FromError[string, string, map[string]interface{}, int](err),
+	)
+	if buildErr != nil {
+		t.Fatalf("Expected no error, got %v", buildErr)
+	}
+	if stringCoded.Code != "409" {
+		t.Errorf("Expected Code to be '409', got %v", stringCoded.Code)
+	}
+}
+
+// TestFromError_CatchAll tests that unmatched errors are replaced by the generic message.
+func TestFromError_CatchAll(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](errors.New("dial tcp 10.0.0.7:5432: connection refused")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success {
+		t.Error("Expected Success to be false")
+	}
+	if response.Message != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected generic message, got %v", response.Message)
+	}
+	if response.Code != 0 {
+		t.Errorf("Expected Code to be unset, got %v", response.Code)
+	}
+	if _, ok := response.Extra["error"]; ok {
+		t.Errorf("Expected the original error to be hidden, got %v", response.Extra["error"])
+	}
+}
+
+// TestFromError_DebugMode tests that the original error is only included in Extra in debug mode.
+func TestFromError_DebugMode(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](errors.New("dial tcp: connection refused")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Message != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected generic message, got %v", response.Message)
+	}
+	if response.Extra["error"] != "dial tcp: connection refused" {
+		t.Errorf("Expected the original error in debug mode, got %v", response.Extra["error"])
+	}
+}
+
+// codedError is an error carrying its own public code.
+type codedError struct {
+	code string
+}
+
+func (e codedError) Error() string { return "coded " + e.code }
+
+func (e codedError) ErrorCode() string { return e.code }
+
+// TestFromError_Joined tests that joined errors are reported leaf by leaf, including nested joins.
+func TestFromError_Joined(t *testing.T) {
+	err := errors.Join(
+		codedError{code: "404"},
+		fmt.Errorf("save: %w", errors.Join(errUnmatched, fmt.Errorf("insert: %w", errDuplicateKey))),
+		codedError{code: "503"},
+	)
+
+	response, buildErr := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](err),
+	)
+	if buildErr != nil {
+		t.Fatalf("Expected no error, got %v", buildErr)
+	}
+
+	if response.Success {
+		t.Error("Expected Success to be false")
+	}
+	if response.Message != "4 errors occurred" {
+		t.Errorf("Expected summary message, got %q", response.Message)
+	}
+	if response.Code != 503 {
+		t.Errorf("Expected the most severe code 503, got %v", response.Code)
+	}
+
+	want := []httpresponse.ErrorDetail{
+		{Code: "404", Message: httpresponse.GenericErrorMessage},
+		{Message: httpresponse.GenericErrorMessage},
+		{Code: "409", Message: "The resource already exists."},
+		{Code: "503", Message: httpresponse.GenericErrorMessage},
+	}
+	details, ok := response.Extra["errors"].([]httpresponse.ErrorDetail)
+	if !ok || len(details) != len(want) {
+		t.Fatalf("Expected %d error details, got %v", len(want), response.Extra["errors"])
+	}
+	for i := range want {
+		if details[i] != want[i] {
+			t.Errorf("Expected detail %d to be %+v, got %+v", i, want[i], details[i])
+		}
+	}
+}
+
+// TestFromError_JoinedStringCodes tests that numeric codes outrank non-numeric ones with string codes.
+func TestFromError_JoinedStringCodes(t *testing.T) {
+	err := errors.Join(codedError{code: "VALIDATION"}, errUnmatched, codedError{code: "422"})
+
+	response, buildErr := rpsutil.Build[httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]](
+		httpresponse.FromError[string, string, map[string]interface{}, int](err),
+	)
+	if buildErr != nil {
+		t.Fatalf("Expected no error, got %v", buildErr)
+	}
+
+	if response.Code != "422" {
+		t.Errorf("Expected code 422, got %q", response.Code)
+	}
+	if response.Message != "3 errors occurred" {
+		t.Errorf("Expected summary message, got %q", response.Message)
+	}
+}
+
+// TestFromError_SingleCoded tests that a single error keeps the unjoined behavior, using its own code.
+func TestFromError_SingleCoded(t *testing.T) {
+	response, buildErr := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](errors.Join(codedError{code: "404"})),
+	)
+	if buildErr != nil {
+		t.Fatalf("Expected no error, got %v", buildErr)
+	}
+
+	if response.Code != 404 || response.Message != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected the single error response, got %+v", response)
+	}
+	if _, ok := response.Extra["errors"]; ok {
+		t.Errorf("Expected no error details, got %v", response.Extra)
+	}
+}
+
+// TestFromError_SubCode tests that a translation registered with a sub-code supplies both codes.
+func TestFromError_SubCode(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]](
+		httpresponse.FromError[string, string, map[string]interface{}, int](fmt.Errorf("sign up: %w", errEmailTaken)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Code != "VALIDATION" || response.SubCode != "EMAIL_TAKEN" {
+		t.Errorf("Expected code VALIDATION and sub-code EMAIL_TAKEN, got %q and %q", response.Code, response.SubCode)
+	}
+
+	// Joined errors report the sub-code of each leaf and of the most severe leaf
+	response, err = rpsutil.Build[httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]](
+		httpresponse.FromError[string, string, map[string]interface{}, int](errors.Join(errUnmatched, errEmailTaken)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Code != "VALIDATION" || response.SubCode != "EMAIL_TAKEN" {
+		t.Errorf("Expected code VALIDATION and sub-code EMAIL_TAKEN, got %q and %q", response.Code, response.SubCode)
+	}
+	details, _ := response.Extra["errors"].([]httpresponse.ErrorDetail)
+	if len(details) != 2 || details[0].SubCode != "" || details[1].SubCode != "EMAIL_TAKEN" {
+		t.Errorf("Expected the sub-code of the second detail only, got %+v", details)
+	}
+}
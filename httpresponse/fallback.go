@@ -0,0 +1,60 @@
+package httpresponse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Fallback tries primary and then each fallback in order, stopping at the first source that succeeds.
+// The returned builder carries that source's data, its index under the "served_by" Extra key (0 for the
+// primary source) and, if earlier sources failed, their errors under the "warnings" Extra key.
+// If every source fails, or ctx is done before a source succeeds, the builder describes a failed
+// response whose message joins the errors of all attempted sources.
+//
+// Sources are called synchronously, each with ctx; the chain stops as soon as ctx is done.
+//
+// Parameters:
+//   - ctx: Passed to every source; cancellation stops the chain.
+//   - primary: The preferred data source, such as a cache.
+//   - fallbacks: Data sources tried in order when the previous ones fail, such as a database.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the outcome of the chain.
+func Fallback[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](ctx context.Context, primary func(context.Context) (D, error), fallbacks ...func(context.Context) (D, error)) *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder := HTTPResponse[C, D, E, T]()
+
+	sources := append([]func(context.Context) (D, error){primary}, fallbacks...)
+	warnings := make([]string, 0, len(sources))
+
+	for i, source := range sources {
+
+		if err := ctx.Err(); err != nil {
+			warnings = append(warnings, fmt.Sprintf("source %d: %v", i, err))
+			break
+		}
+
+		data, err := source(ctx)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("source %d: %v", i, err))
+			continue
+		}
+
+		extra := E{"served_by": i}
+		if len(warnings) > 0 {
+			extra["warnings"] = warnings
+		}
+
+		return httpResponseBuilder.SetData(data).SetExtra(extra)
+	}
+
+	return httpResponseBuilder.
+		setSuccess(false).
+		SetMessage(strings.Join(warnings, "; "))
+}
@@ -0,0 +1,123 @@
+package httpresponse_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// source returns a data source yielding data or err, counting its calls.
+func source(data string, err error, calls *int) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		*calls++
+		return data, err
+	}
+}
+
+// buildFallback builds the response of a fallback chain.
+func buildFallback(t *testing.T, builder *httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]) *httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int] {
+	t.Helper()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return response
+}
+
+// TestFallback_PrimarySuccess tests that a successful primary source serves the response without calling fallbacks.
+func TestFallback_PrimarySuccess(t *testing.T) {
+	var primaryCalls, fallbackCalls int
+
+	response := buildFallback(t, httpresponse.Fallback[int, string, map[string]interface{}, int](
+		context.Background(),
+		source("cached", nil, &primaryCalls),
+		source("db", nil, &fallbackCalls),
+	))
+
+	if !response.Success || response.Data != "cached" {
+		t.Errorf("Expected successful response with cached data, got %v and %v", response.Success, response.Data)
+	}
+	if response.Extra["served_by"] != 0 {
+		t.Errorf("Expected served_by to be 0, got %v", response.Extra["served_by"])
+	}
+	if _, ok := response.Extra["warnings"]; ok {
+		t.Errorf("Expected no warnings, got %v", response.Extra["warnings"])
+	}
+	if primaryCalls != 1 || fallbackCalls != 0 {
+		t.Errorf("Expected only the primary source to be called, got %d and %d calls", primaryCalls, fallbackCalls)
+	}
+}
+
+// TestFallback_FallbackSuccess tests that a failing primary source falls back and is reported as a warning.
+func TestFallback_FallbackSuccess(t *testing.T) {
+	var primaryCalls, fallbackCalls int
+
+	response := buildFallback(t, httpresponse.Fallback[int, string, map[string]interface{}, int](
+		context.Background(),
+		source("", errors.New("cache miss"), &primaryCalls),
+		source("db", nil, &fallbackCalls),
+	))
+
+	if !response.Success || response.Data != "db" {
+		t.Errorf("Expected successful response with db data, got %v and %v", response.Success, response.Data)
+	}
+	if response.Extra["served_by"] != 1 {
+		t.Errorf("Expected served_by to be 1, got %v", response.Extra["served_by"])
+	}
+	warnings, _ := response.Extra["warnings"].([]string)
+	if len(warnings) != 1 || warnings[0] != "source 0: cache miss" {
+		t.Errorf("Expected a warning for the primary source, got %v", response.Extra["warnings"])
+	}
+}
+
+// TestFallback_TotalFailure tests that a failure of every source produces an error envelope with joined messages.
+func TestFallback_TotalFailure(t *testing.T) {
+	var primaryCalls, fallbackCalls int
+
+	response := buildFallback(t, httpresponse.Fallback[int, string, map[string]interface{}, int](
+		context.Background(),
+		source("", errors.New("cache miss"), &primaryCalls),
+		source("", errors.New("db down"), &fallbackCalls),
+	))
+
+	if response.Success {
+		t.Error("Expected Success to be false")
+	}
+	if response.Message != "source 0: cache miss; source 1: db down" {
+		t.Errorf("Expected joined message, got %v", response.Message)
+	}
+}
+
+// TestFallback_Cancellation tests that a cancelled context stops the chain.
+func TestFallback_Cancellation(t *testing.T) {
+	var primaryCalls, fallbackCalls int
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	primary := func(context.Context) (string, error) {
+		primaryCalls++
+		cancel()
+		return "", errors.New("cache timeout")
+	}
+
+	response := buildFallback(t, httpresponse.Fallback[int, string, map[string]interface{}, int](
+		ctx,
+		primary,
+		source("db", nil, &fallbackCalls),
+	))
+
+	if response.Success {
+		t.Error("Expected Success to be false")
+	}
+	if fallbackCalls != 0 {
+		t.Errorf("Expected the fallback not to be called after cancellation, got %d calls", fallbackCalls)
+	}
+	if response.Message != "source 0: cache timeout; source 1: context canceled" {
+		t.Errorf("Expected message to mention the cancellation, got %v", response.Message)
+	}
+}
@@ -0,0 +1,68 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KeyFeatures is the Extra key under which SetFeatureFlags emits the active feature flags.
+const KeyFeatures = "features"
+
+// ErrInvalidFeatures is returned by the option of SetFeatureFlags when the "features" Extra entry is not an
+// object of flags.
+var ErrInvalidFeatures = errors.New("httpresponse: invalid features")
+
+// SetFeatureFlags emits the active flags of flags, those set to true, under the "features" Extra key, as an
+// object mapping each active flag to true. Other Extra entries are kept, and flags set by earlier calls are
+// merged with these, a flag set to false here deactivating an earlier one. No key is emitted while no flag
+// is active. An earlier "features" entry may be a map[string]bool or, as decoded from JSON, a
+// map[string]any, whose flags set to true stay active; the build fails with ErrInvalidFeatures for any
+// other entry.
+//
+// Parameters:
+//   - flags: The feature flags evaluated for the request.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetFeatureFlags(flags map[string]bool) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		active := make(map[string]bool, len(flags))
+		switch previous := args.Extra[KeyFeatures].(type) {
+		case nil:
+		case map[string]bool:
+			for name, on := range previous {
+				if on {
+					active[name] = true
+				}
+			}
+		case map[string]any:
+			for name, on := range previous {
+				if on == true {
+					active[name] = true
+				}
+			}
+		default:
+			return fmt.Errorf("%w: %q Extra entry of type %T", ErrInvalidFeatures, KeyFeatures, previous)
+		}
+		for name, on := range flags {
+			if on {
+				active[name] = true
+			} else {
+				delete(active, name)
+			}
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		if len(active) > 0 {
+			extra[KeyFeatures] = active
+		} else {
+			delete(extra, KeyFeatures)
+		}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,64 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetFeatureFlags tests that the active flags are merged into Extra as the features object.
+func TestSetFeatureFlags(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{"trace_id": "abc"}).
+			SetFeatureFlags(map[string]bool{"new_checkout": true, "dark_mode": true, "beta_search": false}).
+			SetFeatureFlags(map[string]bool{"dark_mode": false, "fast_lists": true}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"features":{"fast_lists":true,"new_checkout":true},"message":"","success":true,"trace_id":"abc"}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+}
+
+// TestSetFeatureFlags_DecodedFeatures tests that flags decoded from JSON, as a map[string]any, are merged and
+// that any other features entry fails the build.
+func TestSetFeatureFlags_DecodedFeatures(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{httpresponse.KeyFeatures: map[string]interface{}{"dark_mode": true, "beta_search": false}}).
+			SetFeatureFlags(map[string]bool{"fast_lists": true}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"features":{"dark_mode":true,"fast_lists":true},"message":"","success":true}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{httpresponse.KeyFeatures: []string{"dark_mode"}}).
+			SetFeatureFlags(map[string]bool{"fast_lists": true}),
+	)
+	if !errors.Is(err, httpresponse.ErrInvalidFeatures) {
+		t.Errorf("Expected ErrInvalidFeatures, got %v", err)
+	}
+}
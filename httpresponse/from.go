@@ -0,0 +1,242 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// FromResponse initializes a builder seeded with all fields of src. The fields are copied when FromResponse
+// is called, with Extra, Headers and the other reference fields copied deeply, so that neither later changes
+// to src nor changes to built responses affect each other. Within Extra, nested map[string]any, Namespace and
+// []any values are copied deeply as well; values of other reference types, and Data, are shared. Further
+// setters layer on top of the seeded fields.
+//
+// Parameters:
+//   - src: The response to start from; a nil src yields a builder with default settings.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the fields of src.
+func FromResponse[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](src *HTTPResponseOptions[C, D, E, T]) *HTTPResponseBuilder[C, D, E, T] {
+
+	if src == nil {
+		return HTTPResponse[C, D, E, T]()
+	}
+
+	snapshot := src.clone()
+
+	httpResponseBuilder := new(HTTPResponseBuilder[C, D, E, T])
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		*args = *snapshot.clone()
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// FromJSON decodes an encoded envelope, such as an upstream response body, and seeds a builder with it
+// like FromResponse. Keys other than the standard envelope keys are collected into Extra.
+//
+// Parameters:
+//   - body: The JSON encoded envelope.
+//   - opts: Validations of the decoded envelope, such as ValidateTimestamp.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the decoded fields.
+//   - error: An error if body is not a JSON object, a standard field cannot be decoded into its type or a
+//     validation fails.
+func FromJSON[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](body []byte, opts ...DecodeOption) (*HTTPResponseBuilder[C, D, E, T], error) {
+
+	src := new(HTTPResponseOptions[C, D, E, T])
+	if err := src.unmarshalEnvelope(body); err != nil {
+		return nil, err
+	}
+
+	var decodeOptions decodeOptions
+	for _, opt := range opts {
+		opt(&decodeOptions)
+	}
+	if err := decodeOptions.validate(src.Extra); err != nil {
+		return nil, err
+	}
+
+	return FromResponse(src), nil
+}
+
+// clone returns a copy of the response whose Extra, Headers and slice fields do not share memory with the original.
+// Nested map[string]any, Namespace and []any values of Extra are copied deeply; other values of Extra, and
+// Data, are copied shallowly.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) clone() *HTTPResponseOptions[C, D, E, T] {
+
+	c := *httpResponseOptions
+
+	if httpResponseOptions.Extra != nil {
+		c.Extra = make(E, len(httpResponseOptions.Extra))
+		for k, v := range httpResponseOptions.Extra {
+			c.Extra[k] = cloneValue(v)
+		}
+	}
+
+	c.Headers = httpResponseOptions.Headers.Clone()
+	c.ExtraKeyOrder = append([]string(nil), httpResponseOptions.ExtraKeyOrder...)
+	c.Vary = append([]string(nil), httpResponseOptions.Vary...)
+
+	if httpResponseOptions.Omit != nil {
+		c.Omit = make(map[Field]func(any) bool, len(httpResponseOptions.Omit))
+		for k, v := range httpResponseOptions.Omit {
+			c.Omit[k] = v
+		}
+	}
+
+	if httpResponseOptions.Cache != nil {
+		cache := *httpResponseOptions.Cache
+		c.Cache = &cache
+	}
+
+	return &c
+}
+
+// cloneValue returns a deep copy of v if it is a map[string]any, a Namespace or a []any, copying their
+// elements recursively, and v itself otherwise.
+func cloneValue(v any) any {
+
+	switch v := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = cloneValue(e)
+		}
+		return c
+	case Namespace:
+		c := make(Namespace, len(v))
+		for k, e := range v {
+			c[k] = cloneValue(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = cloneValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// envelopeFieldValuePreview is the maximum size of the value quoted by EnvelopeFieldError.Error.
+const envelopeFieldValuePreview = 64
+
+// EnvelopeFieldError is returned when a standard envelope key holds a value that cannot be decoded into the
+// type of its field, such as a string code for an int Code or a total out of the range of T.
+type EnvelopeFieldError struct {
+	Key   string       // The envelope key, such as "code".
+	Type  reflect.Type // The type of the field, such as int.
+	Value string       // The encoded value of the key; Error shortens long values.
+	Err   error        // The error of the decoder.
+}
+
+// Error formats the error as `httpresponse: envelope field "code": cannot decode "NOT_FOUND" into int: ...`.
+func (envelopeFieldError *EnvelopeFieldError) Error() string {
+	return fmt.Sprintf("httpresponse: envelope field %q: cannot decode %s into %s: %v",
+		envelopeFieldError.Key, truncatePreview(envelopeFieldError.Value, envelopeFieldValuePreview), envelopeFieldError.Type, envelopeFieldError.Err)
+}
+
+// Unwrap returns the error of the decoder, so that errors.Is and errors.As see through EnvelopeFieldError.
+func (envelopeFieldError *EnvelopeFieldError) Unwrap() error {
+	return envelopeFieldError.Err
+}
+
+// UnmarshalJSON decodes an envelope encoded by MarshalJSON, mirroring it: the standard envelope keys are decoded
+// into their fields, with Code, Data and Total decoded into their types C, D and T, and every other key is
+// collected into Extra. Extra is left nil when there are no other keys. A Total encoded as a string, as with
+// SetTotalAsString, is decoded as well.
+//
+// Parameters:
+//   - data: The JSON encoded envelope.
+//
+// Returns:
+//   - error: An error if data is not a JSON object, or an *EnvelopeFieldError if a standard field cannot be
+//     decoded into its type.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) UnmarshalJSON(data []byte) error {
+	return httpResponseOptions.unmarshalEnvelope(data)
+}
+
+// unmarshalEnvelope decodes an encoded envelope into the response, collecting keys other than the standard
+// envelope keys into Extra. Extra is left nil when there are no such keys.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) unmarshalEnvelope(body []byte) error {
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return fmt.Errorf("httpresponse: decode envelope: %w", err)
+	}
+
+	targets := httpResponseOptions.envelopeTargets()
+
+	for key, raw := range fields {
+
+		if target, ok := targets[key]; ok {
+			if key == KeyTotal && len(raw) > 0 && raw[0] == '"' {
+				var total string
+				if err := json.Unmarshal(raw, &total); err != nil {
+					return &EnvelopeFieldError{Key: key, Type: reflect.TypeOf(target).Elem(), Value: string(raw), Err: err}
+				}
+				raw = json.RawMessage(total)
+			}
+			if err := json.Unmarshal(raw, target); err != nil {
+				return &EnvelopeFieldError{Key: key, Type: reflect.TypeOf(target).Elem(), Value: string(raw), Err: err}
+			}
+			continue
+		}
+
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("httpresponse: decode envelope field %q: %w", key, err)
+		}
+
+		if httpResponseOptions.Extra == nil {
+			httpResponseOptions.Extra = make(E)
+		}
+		httpResponseOptions.Extra[key] = v
+	}
+
+	return nil
+}
+
+// envelopeTargets returns the fields of the response into which the standard envelope keys are decoded.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) envelopeTargets() map[string]any {
+	return map[string]any{
+		KeySuccess: &httpResponseOptions.Success,
+		KeyMessage: &httpResponseOptions.Message,
+		KeyCode:    &httpResponseOptions.Code,
+		KeySubCode: &httpResponseOptions.SubCode,
+		KeyData:    &httpResponseOptions.Data,
+		KeyTotal:   &httpResponseOptions.Total,
+	}
+}
+
+// FromTemplate loads a base envelope template, such as a fixture of a stub server, into a builder that can be
+// further customized. Data is kept as raw JSON and keys other than the standard envelope keys become Extra.
+//
+// Parameters:
+//   - data: The JSON encoded template.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the template.
+//   - error: An error if data is not a valid envelope.
+func FromTemplate(data []byte) (*HTTPResponseBuilder[int, json.RawMessage, map[string]any, int], error) {
+	return FromJSON[int, json.RawMessage, map[string]any, int](data)
+}
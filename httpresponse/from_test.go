@@ -0,0 +1,322 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestFromResponse_CopyIndependence tests that the seeded builder is independent of later changes to the source.
+func TestFromResponse_CopyIndependence(t *testing.T) {
+	src := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Message: "upstream",
+		Code:    200,
+		Data:    "payload",
+		Total:   7,
+		Extra:   map[string]interface{}{"trace_id": "abc"},
+	}
+
+	builder := httpresponse.FromResponse(src)
+
+	src.Message = "changed"
+	src.Extra["trace_id"] = "changed"
+
+	first, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first.Message != "upstream" || first.Code != 200 || first.Data != "payload" || first.Total != 7 || !first.Success {
+		t.Errorf("Expected the source fields at seeding time, got %+v", first)
+	}
+	if first.Extra["trace_id"] != "abc" {
+		t.Errorf("Expected Extra to be deep-copied, got %v", first.Extra["trace_id"])
+	}
+
+	// Builds do not share Extra with each other either
+	first.Extra["gateway"] = "edge-1"
+
+	second, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := second.Extra["gateway"]; ok {
+		t.Error("Expected builds to have independent Extra maps")
+	}
+}
+
+// TestFromResponse_NestedExtra tests that nested maps and slices of Extra are copied, so that changing them in
+// a built response does not reach the source.
+func TestFromResponse_NestedExtra(t *testing.T) {
+	src := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Extra: map[string]interface{}{
+			"upstream": map[string]interface{}{"region": "eu", "hops": []interface{}{"a", map[string]interface{}{"id": 1}}},
+		},
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](httpresponse.FromResponse(src))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	upstream := response.Extra["upstream"].(map[string]interface{})
+	upstream["region"] = "us"
+	hops := upstream["hops"].([]interface{})
+	hops[0] = "b"
+	hops[1].(map[string]interface{})["id"] = 2
+
+	want := map[string]interface{}{"region": "eu", "hops": []interface{}{"a", map[string]interface{}{"id": 1}}}
+	if !reflect.DeepEqual(src.Extra["upstream"], want) {
+		t.Errorf("Expected the source to be unchanged, got %v", src.Extra["upstream"])
+	}
+}
+
+// TestFromResponse_Layering tests that setters chained after seeding override the seeded fields.
+func TestFromResponse_Layering(t *testing.T) {
+	src := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: false,
+		Message: "upstream failure",
+		Code:    503,
+	}
+
+	builder := httpresponse.FromResponse(src).
+		SetCode(502).
+		SetExtra(map[string]interface{}{"gateway": "edge-1"})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success {
+		t.Error("Expected Success to stay false")
+	}
+	if response.Message != "upstream failure" {
+		t.Errorf("Expected Message to be kept, got %v", response.Message)
+	}
+	if response.Code != 502 {
+		t.Errorf("Expected Code to be remapped to 502, got %v", response.Code)
+	}
+	if response.Extra["gateway"] != "edge-1" {
+		t.Errorf("Expected gateway Extra, got %v", response.Extra)
+	}
+}
+
+// TestFromJSON tests that FromJSON decodes the standard fields and collects the rest into Extra.
+func TestFromJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	body := []byte(`{"success":true,"message":"ok","code":200,"data":{"name":"alice"},"total":1,"trace_id":"abc"}`)
+
+	builder, err := httpresponse.FromJSON[int, user, map[string]interface{}, int](body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, user, map[string]interface{}, int]](builder.SetMessage("forwarded"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Code != 200 || response.Data.Name != "alice" || response.Total != 1 {
+		t.Errorf("Expected decoded standard fields, got %+v", response)
+	}
+	if response.Message != "forwarded" {
+		t.Errorf("Expected Message to be overridden, got %v", response.Message)
+	}
+	if len(response.Extra) != 1 || response.Extra["trace_id"] != "abc" {
+		t.Errorf("Expected only trace_id in Extra, got %v", response.Extra)
+	}
+}
+
+// TestFromJSON_Errors tests the FromJSON error paths.
+func TestFromJSON_Errors(t *testing.T) {
+	bodies := []string{
+		`not json`,
+		`["array"]`,
+		`{"code":"NOT_FOUND"}`,
+		`{"total":-1}`,
+	}
+
+	for _, body := range bodies {
+		if _, err := httpresponse.FromJSON[int, string, map[string]interface{}, uint](([]byte)(body)); err == nil {
+			t.Errorf("Expected error for %s, got nil", body)
+		}
+	}
+}
+
+// TestFromTemplate tests loading a template and overriding its message.
+func TestFromTemplate(t *testing.T) {
+	template := []byte(`{"success":true,"message":"fixture","code":200,"data":{"items":[1,2]},"total":2,"source":"stub"}`)
+
+	builder, err := httpresponse.FromTemplate(template)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, json.RawMessage, map[string]any, int]](builder.SetMessage("overridden"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"code":200,"data":{"items":[1,2]},"message":"overridden","source":"stub","success":true,"total":2}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+
+	if _, err := httpresponse.FromTemplate([]byte(`{"code":`)); err == nil {
+		t.Error("Expected error for a malformed template, got nil")
+	}
+}
+
+// TestHTTPResponseOptions_UnmarshalJSON tests that json.Unmarshal decodes the standard fields into their types
+// and collects the other keys into Extra.
+func TestHTTPResponseOptions_UnmarshalJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	var response httpresponse.HTTPResponseOptions[int, user, map[string]interface{}, int64]
+	body := `{"success":true,"message":"ok","code":200,"data":{"name":"alice"},"total":"3","trace_id":"abc"}`
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Message != "ok" || response.Code != 200 || response.Data.Name != "alice" || response.Total != 3 {
+		t.Errorf("Expected decoded standard fields, got %+v", response)
+	}
+	if len(response.Extra) != 1 || response.Extra["trace_id"] != "abc" {
+		t.Errorf("Expected only trace_id in Extra, got %v", response.Extra)
+	}
+
+	var empty httpresponse.HTTPResponseOptions[int, user, map[string]interface{}, int64]
+	if err := json.Unmarshal([]byte(`{}`), &empty); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if empty.Extra != nil {
+		t.Errorf("Expected a nil Extra, got %#v", empty.Extra)
+	}
+}
+
+// TestHTTPResponseOptions_UnmarshalJSON_RoundTrip tests that Marshal, Unmarshal and Marshal again yields the same encoding.
+func TestHTTPResponseOptions_UnmarshalJSON_RoundTrip(t *testing.T) {
+	type item struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+
+	for name, original := range map[string]httpresponse.HTTPResponseOptions[string, []item, map[string]interface{}, int64]{
+		"extra": {
+			Success: true,
+			Message: "ok",
+			Code:    "LISTED",
+			Data:    []item{{ID: 1, Tags: []string{"a"}}, {ID: 2}},
+			Total:   2,
+			Extra:   map[string]interface{}{"links": map[string]interface{}{"next": "/items?offset=2"}, "count": 2, "beta": true},
+		},
+		"failure": {Message: "not found", Code: "NOT_FOUND", SubCode: "USER"},
+		"total as string": {
+			Success:       true,
+			Total:         1 << 60,
+			TotalAsString: true,
+		},
+	} {
+		first, err := json.Marshal(&original)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+
+		var decoded httpresponse.HTTPResponseOptions[string, []item, map[string]interface{}, int64]
+		if err := json.Unmarshal(first, &decoded); err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+		decoded.TotalAsString = original.TotalAsString
+
+		second, err := json.Marshal(&decoded)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+		if string(first) != string(second) {
+			t.Errorf("%s: Expected a stable encoding, got %s then %s", name, first, second)
+		}
+	}
+}
+
+// TestHTTPResponseOptions_UnmarshalJSON_TypeErrors tests that values not assignable to the field types are
+// reported with the key and type.
+func TestHTTPResponseOptions_UnmarshalJSON_TypeErrors(t *testing.T) {
+	for body, want := range map[string]struct {
+		key, message string
+	}{
+		`{"code":"NOT_FOUND"}`: {"code", `httpresponse: envelope field "code": cannot decode "NOT_FOUND" into int: `},
+		`{"total":300}`:        {"total", `httpresponse: envelope field "total": cannot decode 300 into uint8: `},
+		`{"total":"-1"}`:       {"total", `httpresponse: envelope field "total": cannot decode -1 into uint8: `},
+		`{"data":[1,2]}`:       {"data", `httpresponse: envelope field "data": cannot decode [1,2] into string: `},
+	} {
+		var response httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint8]
+		err := json.Unmarshal([]byte(body), &response)
+
+		var fieldErr *httpresponse.EnvelopeFieldError
+		if !errors.As(err, &fieldErr) {
+			t.Errorf("Expected an EnvelopeFieldError for %s, got %v", body, err)
+			continue
+		}
+		if fieldErr.Key != want.key || !strings.HasPrefix(err.Error(), want.message) {
+			t.Errorf("Expected %q for %s, got %q", want.message, body, err.Error())
+		}
+
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Errorf("Expected the decoder error to be wrapped for %s, got %v", body, err)
+		}
+	}
+}
+
+// TestHTTPResponseOptions_UnmarshalJSON_Lossless tests that decoding an encoded response restores every field,
+// Extra included.
+func TestHTTPResponseOptions_UnmarshalJSON_Lossless(t *testing.T) {
+	original := httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, uint64]{
+		Success: false,
+		Message: "partial",
+		Code:    207,
+		SubCode: "PARTIAL",
+		Data:    []string{"a", "b"},
+		Total:   2,
+		Extra: map[string]interface{}{
+			"trace_id": "abc",
+			"retry":    true,
+			"attempts": float64(3),
+			"links":    map[string]interface{}{"self": "/items"},
+			"errors":   []interface{}{map[string]interface{}{"code": "E1"}},
+			"none":     nil,
+		},
+	}
+
+	encoded, err := json.Marshal(&original)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var decoded httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, uint64]
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("Expected %+v, got %+v", original, decoded)
+	}
+}
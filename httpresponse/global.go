@@ -0,0 +1,115 @@
+package httpresponse
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// globalFinalizer is a registered global finalizer. Since builders are generic, the registry holds the
+// finalizers of all instantiations as values of type any, and each builder lists those of its own type.
+type globalFinalizer struct {
+	id    uint64
+	order int
+	fn    any // func(*HTTPResponseOptions[C, D, E, T]) error
+}
+
+var (
+	globalFinalizersMu    sync.Mutex
+	globalFinalizers      atomic.Value // []globalFinalizer by order, then registration; replaced on every change
+	nextGlobalFinalizerID uint64
+)
+
+// RegisterGlobalFinalizer registers fn to run on every build of HTTPResponseOptions[C, D, E, T], after all
+// per-builder options and finalizers, typically from an init function. Global finalizers are scoped to one
+// instantiation: fn only runs for builders with exactly these type arguments, so a policy covering several
+// response types registers a finalizer for each. Finalizers run in registration order; see
+// RegisterGlobalFinalizerOrdered to order them otherwise. A finalizer returning an error fails the build.
+//
+// Parameters:
+//   - fn: The finalizer.
+//
+// Returns:
+//   - func(): Removes the finalizer; calling it more than once has no further effect.
+func RegisterGlobalFinalizer[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](fn func(*HTTPResponseOptions[C, D, E, T]) error) func() {
+	return RegisterGlobalFinalizerOrdered(0, fn)
+}
+
+// RegisterGlobalFinalizerOrdered registers fn as RegisterGlobalFinalizer does, running it before the global
+// finalizers of higher order and after those of lower order; finalizers of equal order run in registration
+// order. RegisterGlobalFinalizer uses order 0.
+//
+// Parameters:
+//   - order: The position of the finalizer relative to the other global finalizers.
+//   - fn: The finalizer.
+//
+// Returns:
+//   - func(): Removes the finalizer; calling it more than once has no further effect.
+func RegisterGlobalFinalizerOrdered[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](order int, fn func(*HTTPResponseOptions[C, D, E, T]) error) func() {
+
+	globalFinalizersMu.Lock()
+	defer globalFinalizersMu.Unlock()
+
+	nextGlobalFinalizerID++
+	id := nextGlobalFinalizerID
+
+	current, _ := globalFinalizers.Load().([]globalFinalizer)
+
+	// Copy on write, so that concurrent builds keep listing the previous slice
+	updated := make([]globalFinalizer, 0, len(current)+1)
+	updated = append(updated, current...)
+	updated = append(updated, globalFinalizer{id: id, order: order, fn: fn})
+	sort.SliceStable(updated, func(i, j int) bool { return updated[i].order < updated[j].order })
+
+	globalFinalizers.Store(updated)
+
+	return func() { removeGlobalFinalizer(id) }
+}
+
+// removeGlobalFinalizer removes the global finalizer registered with id, if still registered.
+func removeGlobalFinalizer(id uint64) {
+
+	globalFinalizersMu.Lock()
+	defer globalFinalizersMu.Unlock()
+
+	current, _ := globalFinalizers.Load().([]globalFinalizer)
+
+	updated := make([]globalFinalizer, 0, len(current))
+	for _, finalizer := range current {
+		if finalizer.id != id {
+			updated = append(updated, finalizer)
+		}
+	}
+
+	globalFinalizers.Store(updated)
+}
+
+// globalFinalizersOf returns the registered global finalizers of HTTPResponseOptions[C, D, E, T], in order.
+func globalFinalizersOf[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+]() []func(*HTTPResponseOptions[C, D, E, T]) error {
+
+	registered, _ := globalFinalizers.Load().([]globalFinalizer)
+
+	var finalizers []func(*HTTPResponseOptions[C, D, E, T]) error
+	for _, finalizer := range registered {
+		if fn, ok := finalizer.fn.(func(*HTTPResponseOptions[C, D, E, T]) error); ok {
+			finalizers = append(finalizers, fn)
+		}
+	}
+
+	return finalizers
+}
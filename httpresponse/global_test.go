@@ -0,0 +1,112 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// policyData is the data type of the responses subject to the global finalizers of these tests, so that
+// they do not affect the builds of other tests.
+type policyData string
+
+// policyResponse is the response type subject to the global finalizers of these tests.
+type policyResponse = httpresponse.HTTPResponseOptions[int, policyData, map[string]interface{}, int]
+
+// stamp returns a global finalizer appending name to the "policies" Extra key.
+func stamp(name string) func(*policyResponse) error {
+	return func(args *policyResponse) error {
+
+		policies, _ := args.Extra["policies"].([]string)
+		extra := map[string]interface{}{}
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra["policies"] = append(append([]string{}, policies...), name)
+		args.Extra = extra
+
+		return nil
+	}
+}
+
+// TestRegisterGlobalFinalizer tests that a global finalizer runs on every build of its type, after the builder.
+func TestRegisterGlobalFinalizer(t *testing.T) {
+	remove := httpresponse.RegisterGlobalFinalizer(func(args *policyResponse) error {
+		args.Extra = map[string]interface{}{"compliance": "gdpr", "message_at_finalize": args.Message}
+		return nil
+	})
+	defer remove()
+
+	for _, builder := range []*httpresponse.HTTPResponseBuilder[int, policyData, map[string]interface{}, int]{
+		httpresponse.HTTPResponse[int, policyData, map[string]interface{}, int]().SetMessage("ok"),
+		httpresponse.FromError[int, policyData, map[string]interface{}, int](errUnmatched),
+	} {
+		response, err := rpsutil.Build[policyResponse](builder)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if response.Extra["compliance"] != "gdpr" || response.Extra["message_at_finalize"] != response.Message {
+			t.Errorf("Expected the compliance stamp after the builder options, got %+v", response)
+		}
+	}
+
+	// Other instantiations are not affected
+	other, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int](),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := other.Extra["compliance"]; ok {
+		t.Errorf("Expected no stamp on another response type, got %v", other.Extra)
+	}
+}
+
+// TestRegisterGlobalFinalizerOrdered tests the ordering and removal of global finalizers.
+func TestRegisterGlobalFinalizerOrdered(t *testing.T) {
+	removeB := httpresponse.RegisterGlobalFinalizer(stamp("b"))
+	removeC := httpresponse.RegisterGlobalFinalizerOrdered(1, stamp("c"))
+	removeA := httpresponse.RegisterGlobalFinalizerOrdered(-1, stamp("a"))
+	removeB2 := httpresponse.RegisterGlobalFinalizer(stamp("b2"))
+	defer removeA()
+	defer removeC()
+	defer removeB2()
+
+	build := func() []string {
+		response, err := rpsutil.Build[policyResponse](httpresponse.HTTPResponse[int, policyData, map[string]interface{}, int]())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		policies, _ := response.Extra["policies"].([]string)
+		return policies
+	}
+
+	if got, want := build(), []string{"a", "b", "b2", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	removeB()
+	removeB()
+	if got, want := build(), []string{"a", "b2", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v after removal, got %v", want, got)
+	}
+}
+
+// TestRegisterGlobalFinalizer_Error tests that a failing global finalizer fails the build.
+func TestRegisterGlobalFinalizer_Error(t *testing.T) {
+	errPolicy := errors.New("policy violated")
+	remove := httpresponse.RegisterGlobalFinalizer(func(*policyResponse) error { return errPolicy })
+
+	_, err := rpsutil.Build[policyResponse](httpresponse.HTTPResponse[int, policyData, map[string]interface{}, int]())
+	if !errors.Is(err, errPolicy) {
+		t.Errorf("Expected the policy error, got %v", err)
+	}
+
+	remove()
+	if _, err := rpsutil.Build[policyResponse](httpresponse.HTTPResponse[int, policyData, map[string]interface{}, int]()); err != nil {
+		t.Errorf("Expected no error after removal, got %v", err)
+	}
+}
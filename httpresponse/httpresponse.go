@@ -4,9 +4,21 @@
 package httpresponse
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
 )
 
+// ErrBareDataConflict is returned by MarshalJSON when bare data mode is enabled together with
+// fields that cannot be represented without the envelope: Extra fields, such as errors or pagination
+// links, or a non-zero Total.
+var ErrBareDataConflict = errors.New("httpresponse: bare data mode cannot represent Extra fields or Total")
+
 // HTTPResponseOptions represents the configuration of an HTTP response, with fields that
 // can be customized to suit various response needs, such as success status, messages, response
 // codes, data payloads, totals, and additional metadata.
@@ -22,17 +34,44 @@ type HTTPResponseOptions[
 	E map[string]any,
 	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
 ] struct {
-	Success bool   `json:"success"`         // Indicates if the response signifies a successful operation.
-	Message string `json:"message"`         // Descriptive message for the response, such as success or error info.
-	Code    C      `json:"code,omitempty"`  // Status code for the response (e.g., HTTP code or custom code); omitted if empty.
-	Data    D      `json:"data,omitempty"`  // Payload containing the main response data; omitted if empty.
-	Total   T      `json:"total,omitempty"` // Total count or amount, often used for pagination; omitted if empty.
-	Extra   E      `json:"-"`               // Additional metadata excluded from JSON by default.
+	Success bool   `json:"success"`            // Indicates if the response signifies a successful operation.
+	Message string `json:"message"`            // Descriptive message for the response, such as success or error info.
+	Code    C      `json:"code,omitempty"`     // Status code for the response (e.g., HTTP code or custom code); omitted if empty.
+	SubCode string `json:"sub_code,omitempty"` // Fine-grained code refining Code, such as "EMAIL_TAKEN" under "VALIDATION"; omitted if empty.
+	Data    D      `json:"data,omitempty"`     // Payload containing the main response data; omitted if empty.
+	Total   T      `json:"total,omitempty"`    // Total count or amount, often used for pagination; omitted if empty.
+	Extra   E      `json:"-"`                  // Additional metadata excluded from JSON by default.
+
+	ExtraKeyOrder []string `json:"-"` // Preferred emission order of Extra keys; unlisted keys follow alphabetically.
+	BareData      bool     `json:"-"` // Emits only the encoded Data value, without the envelope.
+	TotalAsString bool     `json:"-"` // Serializes a 64-bit Total as a JSON string to preserve precision.
+
+	AllowNegativeTotal bool   `json:"-"` // Accepts negative totals set with SetTotal.
+	MaxTotal           uint64 `json:"-"` // The largest total accepted by SetTotal; zero applies the default check.
+
+	CoreKeyCase  KeyCase `json:"-"` // Case of the encoded standard envelope keys.
+	ExtraKeyCase KeyCase `json:"-"` // Case of the encoded top-level Extra keys.
+	SuccessKey   string  `json:"-"` // Name of the encoded success key, regardless of CoreKeyCase; empty keeps the default.
+
+	Omit   map[Field]func(any) bool `json:"-"` // Predicates omitting standard fields from the encoded envelope.
+	NullAs any                      `json:"-"` // Representation of a nil or zero Data; nil keeps the default handling.
+
+	Unserializable UnserializablePolicy `json:"-"` // Handling of Extra values that JSON cannot represent, such as functions.
+
+	Deps *Deps `json:"-"` // Dependencies overriding the package-wide ones for this response; nil uses the package-wide ones.
+
+	Headers      http.Header  `json:"-"` // HTTP headers sent along with the response by the writers.
+	Cache        *CachePolicy `json:"-"` // Caching intent rendered into Cache-Control by the writers; nil sends no directive.
+	CacheExpires bool         `json:"-"` // Also renders the caching intent as an Expires header for HTTP/1.0 caches.
+	Vary         []string     `json:"-"` // Request headers the response varies by, merged into the Vary header by the writers.
 }
 
 // MarshalJSON customizes the JSON encoding for HTTPResponseOptions by merging the core
 // fields with any additional metadata provided in the Extra map.
 //
+// When BareData is set, only the encoded Data value is returned, and an ErrBareDataConflict
+// error is returned if Extra fields or a non-zero Total are also present.
+//
 // This method first marshals the standard fields of HTTPResponseOptions into JSON, then adds
 // any fields from the Extra map into the resulting JSON object before finalizing the output.
 //
@@ -41,31 +80,273 @@ type HTTPResponseOptions[
 //   - error: An error if the marshaling or merging process fails.
 func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) MarshalJSON() ([]byte, error) {
 
+	r, err := httpResponseOptions.marshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	recordMarshal(len(r))
+
+	return r, nil
+}
+
+// marshalJSON implements MarshalJSON without updating the statistics.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) marshalJSON() ([]byte, error) {
+
+	var buf bytes.Buffer
+	if err := httpResponseOptions.encodeJSON(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// bareDataConflict reports whether the response holds fields that bare data mode cannot represent.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) bareDataConflict() bool {
+
+	var zero T
+
+	return len(httpResponseOptions.Extra) > 0 || httpResponseOptions.Total != zero
+}
+
+// encodeJSON writes the encoding produced by MarshalJSON to w without retaining it.
+// On failure, w may hold a partial encoding.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) encodeJSON(w io.Writer) error {
+
+	// In bare data mode only Data is emitted; Success and Message are dropped silently
+	if httpResponseOptions.BareData {
+		if httpResponseOptions.bareDataConflict() {
+			return ErrBareDataConflict
+		}
+		if httpResponseOptions.NullAs != nil && isZero(httpResponseOptions.Data) {
+			return encodeValue(w, httpResponseOptions.NullAs)
+		}
+		if data, ok, err := marshalData(httpResponseOptions.Data); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			return encodeValue(w, data)
+		}
+		return encodeValue(w, httpResponseOptions.Data)
+	}
+
+	// Sweep Extra for values that JSON cannot represent, encoding a copy holding placeholders if need be
+	if extra, err := httpResponseOptions.serializableExtra(); err != nil || extra != nil {
+		if err != nil {
+			return err
+		}
+		swept := *httpResponseOptions
+		swept.Extra = extra
+		return swept.encodeJSON(w)
+	}
+
 	// Marshal the core fields into JSON
 	r, err := json.Marshal(HTTPResponseOptions[C, D, E, T]{
 		Success: httpResponseOptions.Success,
 		Message: httpResponseOptions.Message,
 		Code:    httpResponseOptions.Code,
+		SubCode: httpResponseOptions.SubCode,
 		Data:    httpResponseOptions.Data,
 		Total:   httpResponseOptions.Total,
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Unmarshal the core fields into a map for merging with Extra fields
+	// Unmarshal the core fields into a map for merging with Extra fields, keeping numbers
+	// as literals so that integers beyond 2^53 survive the round trip
 	var rm map[string]interface{}
-	if err := json.Unmarshal(r, &rm); err != nil {
-		return nil, err
+	decoder := json.NewDecoder(bytes.NewReader(r))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rm); err != nil {
+		return err
+	}
+
+	// Serialize 64-bit totals as strings when requested, so JavaScript clients keep full precision
+	if _, ok := rm[KeyTotal]; ok && httpResponseOptions.TotalAsString {
+		if total, ok := formatWideTotal(httpResponseOptions.Total); ok {
+			rm[KeyTotal] = total
+		}
 	}
 
-	// Integrate Extra fields into the map if they exist
-	if httpResponseOptions.Extra != nil {
+	// Substitute the configured representation for absent data, or apply a registered data marshaler
+	if httpResponseOptions.NullAs != nil && isZero(httpResponseOptions.Data) {
+		rm[KeyData] = httpResponseOptions.NullAs
+	} else if _, ok := rm[KeyData]; ok {
+		data, ok, err := marshalData(httpResponseOptions.Data)
+		if err != nil {
+			return err
+		}
+		if ok {
+			rm[KeyData] = data
+		}
+	}
+
+	// Drop the standard fields whose omission predicate matches
+	httpResponseOptions.applyOmissions(rm)
+
+	// Rename the standard fields to the configured key case and success key
+	if httpResponseOptions.CoreKeyCase != KeyCaseAsIs || httpResponseOptions.SuccessKey != "" {
+		renamed := make(map[string]interface{}, len(envelopeFields))
+		for field, name := range httpResponseOptions.Keys().byField() {
+			if v, ok := rm[string(field)]; ok {
+				delete(rm, string(field))
+				renamed[name] = v
+			}
+		}
+		for name, v := range renamed {
+			rm[name] = v
+		}
+	}
+
+	// Without an explicit order or key case, Extra fields are merged and the combined map is emitted
+	// with alphabetically sorted keys
+	if len(httpResponseOptions.ExtraKeyOrder) == 0 && httpResponseOptions.ExtraKeyCase == KeyCaseAsIs {
 		for k, v := range httpResponseOptions.Extra {
 			rm[k] = v
 		}
+		return encodeValue(w, rm)
+	}
+
+	// Integrate Extra fields in emission order, renamed to the configured key case
+	extraKeys := httpResponseOptions.extraKeys()
+	for i, k := range extraKeys {
+		extraKeys[i] = httpResponseOptions.ExtraKeyCase.apply(k)
+		rm[extraKeys[i]] = httpResponseOptions.Extra[k]
+	}
+	if len(httpResponseOptions.ExtraKeyOrder) == 0 {
+		return encodeValue(w, rm)
 	}
 
-	// Marshal the combined map (core fields + Extra fields) back to JSON
-	return json.Marshal(rm)
+	// Marshal the combined map (core fields + Extra fields) back to JSON honoring the Extra key order
+	return encodeOrdered(w, rm, dedupeKeys(extraKeys))
+}
+
+// newlineTrimmer drops the newline that json.Encoder appends after every value.
+type newlineTrimmer struct {
+	w io.Writer
+}
+
+// Write writes p to the underlying writer without its trailing newline.
+func (newlineTrimmer newlineTrimmer) Write(p []byte) (int, error) {
+
+	n, err := newlineTrimmer.w.Write(bytes.TrimSuffix(p, []byte("\n")))
+	if err != nil {
+		return n, err
+	}
+
+	return len(p), nil
+}
+
+// encodeValue writes the encoding of v produced by json.Marshal to w.
+func encodeValue(w io.Writer, v any) error {
+	return json.NewEncoder(newlineTrimmer{w: w}).Encode(v)
+}
+
+// formatWideTotal formats total in base 10 if its type is 64 bits wide, the only widths whose values
+// can exceed the 2^53 integer precision of JavaScript numbers.
+func formatWideTotal[T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64](total T) (string, bool) {
+
+	v := reflect.ValueOf(total)
+	if v.Type().Size() != 8 {
+		return "", false
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	default:
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+}
+
+// extraKeys returns the Extra keys in emission order: keys listed in ExtraKeyOrder come first,
+// in the listed order, followed by the remaining Extra keys sorted alphabetically.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) extraKeys() []string {
+
+	keys := make([]string, 0, len(httpResponseOptions.Extra))
+	seen := make(map[string]bool, len(httpResponseOptions.Extra))
+
+	for _, k := range httpResponseOptions.ExtraKeyOrder {
+		if _, ok := httpResponseOptions.Extra[k]; !ok || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+
+	rest := make([]string, 0, len(httpResponseOptions.Extra)-len(keys))
+	for k := range httpResponseOptions.Extra {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
+
+// dedupeKeys removes repeated keys, keeping the last occurrence of each.
+func dedupeKeys(keys []string) []string {
+
+	seen := make(map[string]bool, len(keys))
+	deduped := make([]string, 0, len(keys))
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		if !seen[keys[i]] {
+			seen[keys[i]] = true
+			deduped = append(deduped, keys[i])
+		}
+	}
+
+	for i, j := 0, len(deduped)-1; i < j; i, j = i+1, j-1 {
+		deduped[i], deduped[j] = deduped[j], deduped[i]
+	}
+
+	return deduped
+}
+
+// encodeOrdered writes rm to w as a JSON object. Keys that are not part of extraKeys (the core fields)
+// are written first in alphabetical order, followed by extraKeys in the given order.
+func encodeOrdered(w io.Writer, rm map[string]interface{}, extraKeys []string) error {
+
+	isExtra := make(map[string]bool, len(extraKeys))
+	for _, k := range extraKeys {
+		isExtra[k] = true
+	}
+
+	keys := make([]string, 0, len(rm))
+	for k := range rm {
+		if !isExtra[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	keys = append(keys, extraKeys...)
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	for i, k := range keys {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		if err := encodeValue(w, k); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ":"); err != nil {
+			return err
+		}
+		if err := encodeValue(w, rm[k]); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "}")
+
+	return err
 }
@@ -2,6 +2,7 @@ package httpresponse_test
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
@@ -176,7 +177,353 @@ func TestBuild_MultipleOptions(t *testing.T) {
 	}
 }
 
+// TestHTTPResponseOptions_MarshalJSON_ExtraKeyOrder tests that Extra keys listed in SetExtraKeyOrder are emitted first, in order.
+func TestHTTPResponseOptions_MarshalJSON_ExtraKeyOrder(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetMessage("ordered").
+		SetExtra(map[string]interface{}{
+			"links":   "/next",
+			"alpha":   1,
+			"zeta":    2,
+			"request": "abc",
+		}).
+		SetExtraKeyOrder([]string{"zeta", "request", "missing"})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"message":"ordered","success":true,"zeta":2,"request":"abc","alpha":1,"links":"/next"}`
+	if string(jsonData) != expected {
+		t.Errorf("Expected JSON to be %v, got %v", expected, string(jsonData))
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_BareDataArray tests that bare data mode emits a top-level JSON array.
+func TestHTTPResponseOptions_MarshalJSON_BareDataArray(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, []string, map[string]interface{}, int]().
+		SetMessage("dropped").
+		SetData([]string{"a", "b"}).
+		BareData(true)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(jsonData) != `["a","b"]` {
+		t.Errorf(`Expected JSON to be ["a","b"], got %v`, string(jsonData))
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_BareDataObject tests that bare data mode emits the Data object without the envelope.
+func TestHTTPResponseOptions_MarshalJSON_BareDataObject(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	response := &httpresponse.HTTPResponseOptions[int, item, map[string]interface{}, int]{
+		Success:  true,
+		Code:     200,
+		Data:     item{Name: "alice"},
+		BareData: true,
+	}
+
+	jsonData, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(jsonData) != `{"name":"alice"}` {
+		t.Errorf(`Expected JSON to be {"name":"alice"}, got %v`, string(jsonData))
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_BareDataConflict tests that bare data mode rejects Extra fields.
+func TestHTTPResponseOptions_MarshalJSON_BareDataConflict(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]{
+		Data:     []string{"a"},
+		Extra:    map[string]interface{}{"links": "/next"},
+		BareData: true,
+	}
+
+	if _, err := response.MarshalJSON(); !errors.Is(err, httpresponse.ErrBareDataConflict) {
+		t.Errorf("Expected ErrBareDataConflict, got %v", err)
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_BareDataTotal tests that bare data mode rejects a total, such as the
+// one set by pagination, rather than dropping it.
+func TestHTTPResponseOptions_MarshalJSON_BareDataTotal(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []string, map[string]interface{}, int]().
+			BareData(true).
+			SetData([]string{"a"}).
+			SetTotal(25),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := response.MarshalJSON(); !errors.Is(err, httpresponse.ErrBareDataConflict) {
+		t.Errorf("Expected ErrBareDataConflict, got %v", err)
+	}
+}
+
+// result is a data type that knows whether it represents success.
+type result struct {
+	OK bool `json:"ok"`
+}
+
+// IsSuccess reports the outcome carried by the result.
+func (r result) IsSuccess() bool {
+	return r.OK
+}
+
+// TestHTTPResponseBuilder_SetSuccessFromData tests that Success is derived from data implementing SuccessReporter.
+func TestHTTPResponseBuilder_SetSuccessFromData(t *testing.T) {
+	for _, ok := range []bool{true, false} {
+		builder := httpresponse.HTTPResponse[int, result, map[string]interface{}, int]().
+			SetSuccessFromData().
+			SetData(result{OK: ok})
+
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, result, map[string]interface{}, int]](builder)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if response.Success != ok {
+			t.Errorf("Expected Success to be %v, got %v", ok, response.Success)
+		}
+	}
+
+	// Data that does not report success leaves Success unchanged
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetSuccess(false).
+		SetData("plain").
+		SetSuccessFromData()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Success != false {
+		t.Errorf("Expected Success to remain false, got %v", response.Success)
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_LargeTotal tests that totals beyond 2^53 keep full precision.
+func TestHTTPResponseOptions_MarshalJSON_LargeTotal(t *testing.T) {
+	const total = uint64(1<<53 + 1)
+
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint64]{Total: total}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(jsonData), `"total":9007199254740993`) {
+		t.Errorf("Expected JSON to contain the exact total, got %v", string(jsonData))
+	}
+}
+
+// TestHTTPResponseBuilder_SetTotalAsString tests that 64-bit totals serialize as strings and narrower totals do not.
+func TestHTTPResponseBuilder_SetTotalAsString(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int64]().
+		SetTotal(1<<53 + 1).
+		SetTotalAsString()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int64]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(jsonData), `"total":"9007199254740993"`) {
+		t.Errorf("Expected JSON to contain the total as a string, got %v", string(jsonData))
+	}
+
+	narrow := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int32]{Total: 42, TotalAsString: true}
+
+	jsonData, err = narrow.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(jsonData), `"total":42`) {
+		t.Errorf("Expected JSON to contain a numeric 32-bit total, got %v", string(jsonData))
+	}
+}
+
+// TestHTTPResponseBuilder_SetNullAs tests substituting an empty object and a sentinel for absent data.
+func TestHTTPResponseBuilder_SetNullAs(t *testing.T) {
+	type profile struct {
+		Name string `json:"name"`
+	}
+
+	builder := httpresponse.HTTPResponse[int, *profile, map[string]interface{}, int]().
+		SetData(nil).
+		SetNullAs(struct{}{})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, *profile, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(jsonData), `"data":{}`) {
+		t.Errorf("Expected JSON to contain an empty data object, got %v", string(jsonData))
+	}
+
+	// Present data is not substituted
+	response.Data = &profile{Name: "alice"}
+	jsonData, _ = response.MarshalJSON()
+	if !contains(string(jsonData), `"data":{"name":"alice"}`) {
+		t.Errorf("Expected JSON to contain the data, got %v", string(jsonData))
+	}
+
+	// A sentinel replaces zero data, including in bare data mode
+	sentinel := &httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]{NullAs: "N/A", BareData: true}
+	jsonData, err = sentinel.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(jsonData) != `"N/A"` {
+		t.Errorf("Expected the sentinel, got %v", string(jsonData))
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSON_MapDataDeterministic tests that map Data, including nested maps,
+// marshals to byte-identical output across repeated marshals.
+func TestHTTPResponseOptions_MarshalJSON_MapDataDeterministic(t *testing.T) {
+	data := make(map[string]map[string]int)
+	for i := 0; i < 50; i++ {
+		inner := make(map[string]int)
+		for j := 0; j < 10; j++ {
+			inner[string(rune('a'+j))] = j
+		}
+		data[string(rune('A'+i))] = inner
+	}
+
+	response := &httpresponse.HTTPResponseOptions[int, map[string]map[string]int, map[string]interface{}, int]{
+		Success: true,
+		Data:    data,
+		Extra:   map[string]interface{}{"z": 1, "y": map[string]int{"b": 2, "a": 1}},
+	}
+
+	first, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		jsonData, err := response.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if string(jsonData) != string(first) {
+			t.Fatalf("Expected byte-identical output, got %s and %s", first, jsonData)
+		}
+	}
+
+	if !contains(string(first), `"A":{"a":0,"b":1,"c":2`) {
+		t.Errorf("Expected nested map keys to be sorted, got %.80s", first)
+	}
+}
+
 // Helper function to check if a substring is in a string
 func contains(str, substr string) bool {
 	return json.Valid([]byte(str)) && strings.Contains(str, substr)
 }
+
+// TestHTTPResponseBuilder_SetSubCode tests that the sub-code is emitted next to the code, omitted when empty,
+// renamed with the core key case and decoded back.
+func TestHTTPResponseBuilder_SetSubCode(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, any, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[string, any, map[string]interface{}, int]().
+			SetSuccess(false).
+			SetCode("VALIDATION").
+			SetSubCode("EMAIL_TAKEN"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := `{"code":"VALIDATION","message":"","sub_code":"EMAIL_TAKEN","success":false}`; string(jsonData) != want {
+		t.Errorf("Expected %s, got %s", want, jsonData)
+	}
+
+	builder, err := httpresponse.FromJSON[string, any, map[string]interface{}, int](jsonData)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, any, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.SubCode != "EMAIL_TAKEN" || len(decoded.Extra) != 0 {
+		t.Errorf("Expected the sub-code to be decoded, got %+v", decoded)
+	}
+
+	response.SubCode = ""
+	response.CoreKeyCase = httpresponse.KeyCaseCamel
+	if jsonData, _ = response.MarshalJSON(); contains(string(jsonData), "subCode") {
+		t.Errorf("Expected an empty sub-code to be omitted, got %s", jsonData)
+	}
+
+	response.SubCode = "EMAIL_TAKEN"
+	if jsonData, _ = response.MarshalJSON(); !contains(string(jsonData), `"subCode":"EMAIL_TAKEN"`) {
+		t.Errorf("Expected the camel-cased sub-code key, got %s", jsonData)
+	}
+}
+
+// TestHTTPResponseBuilder_ValueCopies tests that setters called on copies of a builder made by value do not
+// overwrite each other's options.
+func TestHTTPResponseBuilder_ValueCopies(t *testing.T) {
+	base := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(200)
+
+	builders := map[string]httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]{
+		"one": *base,
+		"two": *base,
+	}
+
+	one, two := builders["one"], builders["two"]
+	one.SetMessage("one").SetTotal(1)
+	two.SetMessage("two").SetTotal(-1)
+	base.SetMessage("base")
+
+	for name, builder := range map[string]*httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]{
+		"one":  &one,
+		"base": base,
+	} {
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", name, err)
+		}
+		if response.Message != name || response.Code != 200 {
+			t.Errorf("Expected message %q with code 200, got %q with code %d", name, response.Message, response.Code)
+		}
+	}
+
+	// The negative total of the second copy fails its own build only
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](&two); err == nil {
+		t.Error("Expected the negative total of the second copy to fail its build")
+	}
+}
@@ -0,0 +1,135 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// KeyJob is the Extra key under which the state and progress of a job are reported.
+const KeyJob = "job"
+
+// Job states.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// ErrInvalidJobStatus is returned when building a job status response with an unknown state or an
+// out-of-range progress.
+var ErrInvalidJobStatus = errors.New("httpresponse: invalid job status")
+
+// JobInfo is the state and progress of a job, reported under the "job" Extra key.
+type JobInfo struct {
+	State    string   `json:"state"`              // One of JobQueued, JobRunning, JobDone or JobFailed.
+	Progress *float64 `json:"progress,omitempty"` // The completion percentage, from 0 to 100; omitted if unknown.
+}
+
+// JobStatusBuilder builds the status envelope of an asynchronous job. The setters of HTTPResponseBuilder,
+// such as SetMessage, remain available. Create it with JobStatus.
+type JobStatusBuilder[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	*HTTPResponseBuilder[C, D, E, T]
+
+	state    string
+	progress *float64
+	result   *D
+}
+
+// JobStatus initializes a builder for the status envelope of a job, in the queued state. Success is only true
+// once the job is done, and the result is only included in that state; the state and progress are reported
+// under the "job" Extra key.
+//
+// Returns:
+//   - *JobStatusBuilder: A builder for the job status envelope.
+func JobStatus[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+]() *JobStatusBuilder[C, D, E, T] {
+
+	jobStatusBuilder := &JobStatusBuilder[C, D, E, T]{
+		HTTPResponseBuilder: HTTPResponse[C, D, E, T](),
+		state:               JobQueued,
+	}
+
+	jobStatusBuilder.addFinalizer(jobStatusBuilder.apply)
+
+	return jobStatusBuilder
+}
+
+// SetState sets the state of the job. Building fails with ErrInvalidJobStatus for states other than
+// JobQueued, JobRunning, JobDone and JobFailed.
+//
+// Parameters:
+//   - state: The state of the job.
+func (jobStatusBuilder *JobStatusBuilder[C, D, E, T]) SetState(state string) *JobStatusBuilder[C, D, E, T] {
+
+	jobStatusBuilder.state = state
+
+	return jobStatusBuilder
+}
+
+// SetProgress sets the completion percentage of the job. Building fails with ErrInvalidJobStatus for values
+// outside [0, 100].
+//
+// Parameters:
+//   - pct: The completion percentage.
+func (jobStatusBuilder *JobStatusBuilder[C, D, E, T]) SetProgress(pct float64) *JobStatusBuilder[C, D, E, T] {
+
+	jobStatusBuilder.progress = &pct
+
+	return jobStatusBuilder
+}
+
+// SetResult sets the result of the job, sent as Data once the job is done.
+//
+// Parameters:
+//   - data: The result of the job.
+func (jobStatusBuilder *JobStatusBuilder[C, D, E, T]) SetResult(data D) *JobStatusBuilder[C, D, E, T] {
+
+	jobStatusBuilder.result = &data
+
+	return jobStatusBuilder
+}
+
+// apply writes the job status into the response after all other options.
+func (jobStatusBuilder *JobStatusBuilder[C, D, E, T]) apply(args *HTTPResponseOptions[C, D, E, T]) error {
+
+	switch jobStatusBuilder.state {
+	case JobQueued, JobRunning, JobDone, JobFailed:
+	default:
+		return fmt.Errorf("%w: unknown state %q", ErrInvalidJobStatus, jobStatusBuilder.state)
+	}
+
+	var progress *float64
+	if jobStatusBuilder.progress != nil {
+		pct := *jobStatusBuilder.progress
+		if math.IsNaN(pct) || pct < 0 || pct > 100 {
+			return fmt.Errorf("%w: progress %v out of range", ErrInvalidJobStatus, pct)
+		}
+		progress = &pct
+	}
+
+	args.Success = jobStatusBuilder.state == JobDone
+
+	if args.Success && jobStatusBuilder.result != nil {
+		args.Data = *jobStatusBuilder.result
+	}
+
+	extra := make(E, len(args.Extra)+1)
+	for k, v := range args.Extra {
+		extra[k] = v
+	}
+	extra[KeyJob] = JobInfo{State: jobStatusBuilder.state, Progress: progress}
+	args.Extra = extra
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+type jobResponse = httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+
+// TestJobStatus_Running tests the envelope of a running job, which carries no result.
+func TestJobStatus_Running(t *testing.T) {
+	builder := httpresponse.JobStatus[int, string, map[string]interface{}, int]().
+		SetState(httpresponse.JobRunning).
+		SetProgress(42.5).
+		SetResult("early")
+
+	response, err := rpsutil.Build[jobResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success {
+		t.Error("Expected Success to be false before the job is done")
+	}
+	if response.Data != "" {
+		t.Errorf("Expected no result while running, got %q", response.Data)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(body), `"job":{"state":"running","progress":42.5}`) {
+		t.Errorf("Expected the job state and progress, got %s", body)
+	}
+}
+
+// TestJobStatus_Done tests the envelope of a completed job, which carries its result.
+func TestJobStatus_Done(t *testing.T) {
+	builder := httpresponse.JobStatus[int, string, map[string]interface{}, int]().
+		SetState(httpresponse.JobDone).
+		SetResult("report.csv")
+	builder.SetMessage("Export finished.")
+
+	response, err := rpsutil.Build[jobResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Data != "report.csv" || response.Message != "Export finished." {
+		t.Errorf("Expected a successful envelope with the result, got %+v", response)
+	}
+	if info := response.Extra[httpresponse.KeyJob].(httpresponse.JobInfo); info.State != httpresponse.JobDone || info.Progress != nil {
+		t.Errorf("Expected the done state without progress, got %+v", info)
+	}
+}
+
+// TestJobStatus_Failed tests that a failed job is not successful, even if SetSuccess was called.
+func TestJobStatus_Failed(t *testing.T) {
+	builder := httpresponse.JobStatus[int, string, map[string]interface{}, int]().SetState(httpresponse.JobFailed)
+	builder.SetSuccess(true)
+
+	response, err := rpsutil.Build[jobResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Success {
+		t.Error("Expected Success to be false for a failed job")
+	}
+}
+
+// TestJobStatus_Invalid tests that unknown states and out-of-range progress fail the build.
+func TestJobStatus_Invalid(t *testing.T) {
+	builders := []*httpresponse.JobStatusBuilder[int, string, map[string]interface{}, int]{
+		httpresponse.JobStatus[int, string, map[string]interface{}, int]().SetState("paused"),
+		httpresponse.JobStatus[int, string, map[string]interface{}, int]().SetProgress(101),
+	}
+
+	for _, builder := range builders {
+		if _, err := rpsutil.Build[jobResponse](builder); !errors.Is(err, httpresponse.ErrInvalidJobStatus) {
+			t.Errorf("Expected ErrInvalidJobStatus, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package httpresponse
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// KeyCase is a naming convention applied to encoded envelope keys.
+type KeyCase int
+
+const (
+	// KeyCaseAsIs emits keys as they are named.
+	KeyCaseAsIs KeyCase = iota
+
+	// KeyCaseCamel emits keys in camelCase, e.g. "retryAfter".
+	KeyCaseCamel
+
+	// KeyCaseSnake emits keys in snake_case, e.g. "retry_after".
+	KeyCaseSnake
+)
+
+// String returns the name of the key case.
+func (keyCase KeyCase) String() string {
+	switch keyCase {
+	case KeyCaseAsIs:
+		return "as-is"
+	case KeyCaseCamel:
+		return "camel"
+	case KeyCaseSnake:
+		return "snake"
+	default:
+		return fmt.Sprintf("KeyCase(%d)", int(keyCase))
+	}
+}
+
+// apply converts key to the key case.
+func (keyCase KeyCase) apply(key string) string {
+	switch keyCase {
+	case KeyCaseCamel:
+		return toCamelCase(key)
+	case KeyCaseSnake:
+		return toSnakeCase(key)
+	default:
+		return key
+	}
+}
+
+// toCamelCase converts a snake_case or kebab-case key to camelCase. Keys already in camelCase are unchanged.
+func toCamelCase(key string) string {
+
+	var b strings.Builder
+	b.Grow(len(key))
+
+	upper := false
+	for _, r := range key {
+		if r == '_' || r == '-' {
+			upper = b.Len() > 0
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// toSnakeCase converts a camelCase or PascalCase key to snake_case, keeping acronyms such as "ID" together.
+// Keys already in snake_case are unchanged.
+func toSnakeCase(key string) string {
+
+	runes := []rune(key)
+
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+
+	for i, r := range runes {
+		if r == '-' {
+			r = '_'
+		}
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && runes[i-1] != '-' &&
+				(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// SetCoreKeyCase sets the case of the standard envelope keys (success, message, code, data and total)
+// emitted by MarshalJSON, independently of the case of the Extra keys.
+//
+// Parameters:
+//   - keyCase: The key case of the standard keys.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetCoreKeyCase(keyCase KeyCase) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.CoreKeyCase = keyCase
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SetExtraKeyCase sets the case of the top-level Extra keys emitted by MarshalJSON, independently of the
+// case of the standard keys. Nested keys inside Extra values are left unchanged, and ExtraKeyOrder keeps
+// referring to the keys as they are named in Extra. If two Extra keys convert to the same key, the one
+// sorting last in emission order wins.
+//
+// Parameters:
+//   - keyCase: The key case of the Extra keys.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtraKeyCase(keyCase KeyCase) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.ExtraKeyCase = keyCase
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
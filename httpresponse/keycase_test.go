@@ -0,0 +1,65 @@
+package httpresponse_test
+
+import (
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetKeyCase tests every combination of standard and Extra key cases.
+func TestSetKeyCase(t *testing.T) {
+	tests := []struct {
+		core     httpresponse.KeyCase
+		extra    httpresponse.KeyCase
+		expected string
+	}{
+		{httpresponse.KeyCaseAsIs, httpresponse.KeyCaseAsIs, `{"code":200,"message":"","retry_after":1,"success":true,"tenantID":"acme"}`},
+		{httpresponse.KeyCaseCamel, httpresponse.KeyCaseCamel, `{"code":200,"message":"","retryAfter":1,"success":true,"tenantID":"acme"}`},
+		{httpresponse.KeyCaseCamel, httpresponse.KeyCaseSnake, `{"code":200,"message":"","retry_after":1,"success":true,"tenant_id":"acme"}`},
+		{httpresponse.KeyCaseSnake, httpresponse.KeyCaseCamel, `{"code":200,"message":"","retryAfter":1,"success":true,"tenantID":"acme"}`},
+		{httpresponse.KeyCaseSnake, httpresponse.KeyCaseSnake, `{"code":200,"message":"","retry_after":1,"success":true,"tenant_id":"acme"}`},
+	}
+
+	for _, tt := range tests {
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+			httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+				SetCode(200).
+				SetExtra(map[string]interface{}{"retry_after": 1, "tenantID": "acme"}).
+				SetCoreKeyCase(tt.core).
+				SetExtraKeyCase(tt.extra),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		body, err := response.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if string(body) != tt.expected {
+			t.Errorf("core %v, extra %v: Expected %s, got %s", tt.core, tt.extra, tt.expected, body)
+		}
+	}
+}
+
+// TestSetExtraKeyCase_Order tests that ExtraKeyOrder refers to the Extra keys before conversion.
+func TestSetExtraKeyCase_Order(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success:       true,
+		Extra:         map[string]interface{}{"a_key": 1, "z_key": 2},
+		ExtraKeyOrder: []string{"z_key"},
+		ExtraKeyCase:  httpresponse.KeyCaseCamel,
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"message":"","success":true,"zKey":2,"aKey":1}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
@@ -0,0 +1,95 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSuccessKeyCollision is returned when building a response whose success key collides with another
+// standard envelope key.
+var ErrSuccessKeyCollision = errors.New("httpresponse: success key collides with a reserved key")
+
+// Standard keys of the JSON envelope produced by MarshalJSON.
+const (
+	KeySuccess = "success"
+	KeyMessage = "message"
+	KeyCode    = "code"
+	KeySubCode = "sub_code"
+	KeyData    = "data"
+	KeyTotal   = "total"
+)
+
+// EnvelopeKeys holds the effective names of the standard envelope keys of a response.
+type EnvelopeKeys struct {
+	Success string
+	Message string
+	Code    string
+	SubCode string
+	Data    string
+	Total   string
+}
+
+// Keys returns the effective names of the standard envelope keys, as emitted by MarshalJSON for this response.
+//
+// Returns:
+//   - EnvelopeKeys: The key names.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) Keys() EnvelopeKeys {
+	keyCase := httpResponseOptions.CoreKeyCase
+
+	success := httpResponseOptions.SuccessKey
+	if success == "" {
+		success = keyCase.apply(KeySuccess)
+	}
+
+	return EnvelopeKeys{
+		Success: success,
+		Message: keyCase.apply(KeyMessage),
+		Code:    keyCase.apply(KeyCode),
+		SubCode: keyCase.apply(KeySubCode),
+		Data:    keyCase.apply(KeyData),
+		Total:   keyCase.apply(KeyTotal),
+	}
+}
+
+// byField returns the key names by standard field.
+func (envelopeKeys EnvelopeKeys) byField() map[Field]string {
+	return map[Field]string{
+		FieldSuccess: envelopeKeys.Success,
+		FieldMessage: envelopeKeys.Message,
+		FieldCode:    envelopeKeys.Code,
+		FieldSubCode: envelopeKeys.SubCode,
+		FieldData:    envelopeKeys.Data,
+		FieldTotal:   envelopeKeys.Total,
+	}
+}
+
+// SetSuccessKey renames the success key of the encoded envelope, such as to "ok" for contracts naming the
+// flag that way; the value stays a boolean. The name is used as is, regardless of SetCoreKeyCase. Building
+// fails with ErrSuccessKeyCollision if the name equals the effective name of another standard envelope key,
+// which is checked after all other options.
+//
+// Parameters:
+//   - name: The name of the success key; empty restores the default name.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSuccessKey(name string) *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.SuccessKey = name
+
+		return nil
+	})
+
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		keys := args.Keys()
+		for _, reserved := range []string{keys.Message, keys.Code, keys.SubCode, keys.Data, keys.Total} {
+			if keys.Success == reserved {
+				return fmt.Errorf("%w: %q", ErrSuccessKeyCollision, reserved)
+			}
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,99 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestHTTPResponseOptions_Keys tests that Keys reports the key names actually emitted by MarshalJSON.
+func TestHTTPResponseOptions_Keys(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Message: "ok",
+		Code:    200,
+		Data:    "payload",
+		Total:   1,
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(jsonData, &m); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	keys := response.Keys()
+	for _, key := range []string{keys.Success, keys.Message, keys.Code, keys.Data, keys.Total} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("Expected key %q in %v", key, string(jsonData))
+		}
+	}
+
+	expected := httpresponse.EnvelopeKeys{Success: "success", Message: "message", Code: "code", SubCode: "sub_code", Data: "data", Total: "total"}
+	if keys != expected {
+		t.Errorf("Expected default keys %+v, got %+v", expected, keys)
+	}
+}
+
+// TestDefaultMessages tests that presets use the exported default messages.
+func TestDefaultMessages(t *testing.T) {
+	cases := map[httpresponse.Operation]string{
+		httpresponse.OperationCreated: httpresponse.DefaultCreatedMessage,
+		httpresponse.OperationUpdated: httpresponse.DefaultUpdatedMessage,
+		httpresponse.OperationDeleted: httpresponse.DefaultDeletedMessage,
+	}
+	for operation, expected := range cases {
+		if message, _ := httpresponse.SuccessMessage(operation); message != expected {
+			t.Errorf("Expected %v message to be %q, got %q", operation, expected, message)
+		}
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](errUnmatched),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Message != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected FromError to use GenericErrorMessage, got %q", response.Message)
+	}
+}
+
+// TestSetSuccessKey tests that the success flag is emitted as "ok" and reported by Keys.
+func TestSetSuccessKey(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSuccessKey("ok").SetMessage("done"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := `{"message":"done","ok":true}`; string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+	if keys := response.Keys(); keys.Success != "ok" {
+		t.Errorf("Expected the success key ok, got %s", keys.Success)
+	}
+}
+
+// TestSetSuccessKey_Collision tests that a success key naming another standard key fails the build.
+func TestSetSuccessKey_Collision(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSuccessKey("message"),
+	)
+	if !errors.Is(err, httpresponse.ErrSuccessKeyCollision) {
+		t.Errorf("Expected ErrSuccessKeyCollision, got %v", err)
+	}
+}
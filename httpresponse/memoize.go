@@ -0,0 +1,263 @@
+package httpresponse
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// Memoized is a handle to a response that is built and marshaled once and then served from cache.
+// It implements Responder, so it can be passed to WriteJSON and ServeJSON directly, and is safe for
+// concurrent use. Create it with Memoize.
+type Memoized[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	builder     *HTTPResponseBuilder[C, D, E, T]
+	ttl         time.Duration
+	invalidate  <-chan struct{}
+	ttlByStatus map[int]time.Duration
+	cacheIf     func(*HTTPResponseOptions[C, D, E, T]) bool
+
+	mu       sync.Mutex
+	response *HTTPResponseOptions[C, D, E, T]
+	body     []byte
+	expires  time.Time
+}
+
+// Memoize returns a handle that lazily builds and marshals the response of builder on first use and caches
+// the result. The response is rebuilt on the next use after ttl has elapsed or a value has been received
+// from invalidate. Concurrent uses while the response is being rebuilt wait for that single rebuild instead
+// of building in parallel. Build and marshal errors are returned to the caller and not cached.
+//
+// Parameters:
+//   - builder: The builder of the memoized response; it is built anew on every rebuild.
+//   - ttl: How long a built response is served; zero or negative means until invalidated.
+//   - invalidate: A channel signalling that the response must be rebuilt; nil means never. Closing it
+//     invalidates the current response once and stops further signalling.
+//
+// Returns:
+//   - *Memoized: The handle serving the memoized response.
+func Memoize[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](builder *HTTPResponseBuilder[C, D, E, T], ttl time.Duration, invalidate <-chan struct{}) *Memoized[C, D, E, T] {
+	return &Memoized[C, D, E, T]{builder: builder, ttl: ttl, invalidate: invalidate}
+}
+
+// Status classes usable as keys of TTLByStatus, standing for every status code of the class without an entry
+// of its own.
+const (
+	StatusClass2xx = 2
+	StatusClass3xx = 3
+	StatusClass4xx = 4
+	StatusClass5xx = 5
+)
+
+// TTLByStatus sets how long responses are served depending on their HTTP status code, such as briefly for
+// 404 responses to absorb scrapers. The TTL of a status is looked up by the status code, then by its class
+// (StatusClass2xx to StatusClass5xx); statuses matching neither use the ttl given to Memoize, except 5xx
+// responses, which are then never cached. A zero TTL serves the response until invalidated and a negative
+// TTL never caches it, so it is rebuilt on every use. It must be called before the handle is used.
+//
+// Parameters:
+//   - ttls: The TTLs by status code or class.
+//
+// Returns:
+//   - *Memoized: The same handle, for chaining.
+func (memoized *Memoized[C, D, E, T]) TTLByStatus(ttls map[int]time.Duration) *Memoized[C, D, E, T] {
+
+	memoized.ttlByStatus = ttls
+
+	return memoized
+}
+
+// CacheIf sets a predicate vetoing caching per response: a built response for which cache returns false is
+// served once and rebuilt on the next use, such as responses built with NoStore. It must be called before
+// the handle is used.
+//
+// Parameters:
+//   - cache: Reports whether a built response may be cached; nil caches every response.
+//
+// Returns:
+//   - *Memoized: The same handle, for chaining.
+func (memoized *Memoized[C, D, E, T]) CacheIf(cache func(response *HTTPResponseOptions[C, D, E, T]) bool) *Memoized[C, D, E, T] {
+
+	memoized.cacheIf = cache
+
+	return memoized
+}
+
+// Bytes returns the JSON encoding of the memoized response, building and marshaling it if needed.
+// The returned slice is shared between callers and must not be modified.
+//
+// Returns:
+//   - []byte: The encoded response.
+//   - error: An error if building or marshaling the response fails.
+func (memoized *Memoized[C, D, E, T]) Bytes() ([]byte, error) {
+
+	_, body, err := memoized.load()
+
+	return body, err
+}
+
+// StatusCode returns the HTTP status code of the memoized response, or 500 if it cannot be built.
+//
+// Returns:
+//   - int: The HTTP status code to write.
+func (memoized *Memoized[C, D, E, T]) StatusCode() int {
+
+	response, _, err := memoized.load()
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+
+	return response.StatusCode()
+}
+
+// Body returns the JSON encoding of the memoized response.
+//
+// Returns:
+//   - string: The content type of the body, "application/json".
+//   - []byte: The encoded body, shared between callers.
+//   - error: An error if building or marshaling the response fails.
+func (memoized *Memoized[C, D, E, T]) Body() (string, []byte, error) {
+
+	body, err := memoized.Bytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return contentTypeJSON, body, nil
+}
+
+// Header returns the headers of the memoized response, rendered at the time of the call.
+//
+// Returns:
+//   - http.Header: The response headers; nil if none were set or the response cannot be built.
+func (memoized *Memoized[C, D, E, T]) Header() http.Header {
+
+	response, _, err := memoized.load()
+	if err != nil {
+		return nil
+	}
+
+	return response.Header()
+}
+
+// snapshot returns the current build of the memoized response with its encoding, so that WriteJSON writes
+// the status, headers and body of a single build even if it expires or is invalidated meanwhile.
+func (memoized *Memoized[C, D, E, T]) snapshot() (Responder, error) {
+
+	response, body, err := memoized.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoizedSnapshot[C, D, E, T]{HTTPResponseOptions: response, body: body}, nil
+}
+
+// memoizedSnapshot is one build of a memoized response, whose Body is the encoding cached with it.
+type memoizedSnapshot[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	*HTTPResponseOptions[C, D, E, T]
+	body []byte
+}
+
+// Body returns the encoding cached with the build.
+func (memoizedSnapshot *memoizedSnapshot[C, D, E, T]) Body() (string, []byte, error) {
+	return contentTypeJSON, memoizedSnapshot.body, nil
+}
+
+// load returns the cached response and body, rebuilding them if they are missing, expired or invalidated.
+func (memoized *Memoized[C, D, E, T]) load() (*HTTPResponseOptions[C, D, E, T], []byte, error) {
+
+	memoized.mu.Lock()
+	defer memoized.mu.Unlock()
+
+	if memoized.invalidated() {
+		memoized.response, memoized.body = nil, nil
+	}
+
+	if memoized.response != nil && (memoized.expires.IsZero() || memoized.response.deps().Clock().Before(memoized.expires)) {
+		return memoized.response, memoized.body, nil
+	}
+	memoized.response, memoized.body = nil, nil
+
+	response, err := rpsutil.Build[HTTPResponseOptions[C, D, E, T]](memoized.builder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ttl, cache := memoized.ttlFor(response)
+	if !cache {
+		return response, body, nil
+	}
+
+	memoized.response, memoized.body = response, body
+	memoized.expires = time.Time{}
+	if ttl > 0 {
+		memoized.expires = response.deps().Clock().Add(ttl)
+	}
+
+	return response, body, nil
+}
+
+// ttlFor returns how long response is served, zero meaning until invalidated, and whether it is cached at all.
+func (memoized *Memoized[C, D, E, T]) ttlFor(response *HTTPResponseOptions[C, D, E, T]) (time.Duration, bool) {
+
+	if memoized.cacheIf != nil && !memoized.cacheIf(response) {
+		return 0, false
+	}
+
+	if memoized.ttlByStatus == nil {
+		return memoized.ttl, true
+	}
+
+	status := response.StatusCode()
+
+	ttl, ok := memoized.ttlByStatus[status]
+	if !ok {
+		ttl, ok = memoized.ttlByStatus[status/100]
+	}
+	if !ok {
+		return memoized.ttl, status/100 != StatusClass5xx
+	}
+
+	return ttl, ttl >= 0
+}
+
+// invalidated drains the invalidation channel without blocking, reporting whether a signal was pending.
+func (memoized *Memoized[C, D, E, T]) invalidated() bool {
+
+	signalled := false
+
+	for memoized.invalidate != nil {
+		select {
+		case _, ok := <-memoized.invalidate:
+			signalled = true
+			if !ok {
+				memoized.invalidate = nil
+			}
+		default:
+			return signalled
+		}
+	}
+
+	return signalled
+}
@@ -0,0 +1,207 @@
+package httpresponse_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// countingBuilder returns a builder whose Data is the number of times it has been built.
+func countingBuilder(builds *int64, delay time.Duration) *httpresponse.HTTPResponseBuilder[int, int64, map[string]interface{}, int] {
+	builder := httpresponse.HTTPResponse[int, int64, map[string]interface{}, int]()
+	builder.Opts = append(builder.Opts, func(args *httpresponse.HTTPResponseOptions[int, int64, map[string]interface{}, int]) error {
+		time.Sleep(delay)
+		args.Data = atomic.AddInt64(builds, 1)
+		return nil
+	})
+	return builder
+}
+
+// TestMemoize_Caching tests that the response is built once and served from cache afterwards.
+func TestMemoize_Caching(t *testing.T) {
+	var builds int64
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 0), 0, nil)
+
+	first, err := memoized.Bytes()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		if err := httpresponse.WriteJSON(recorder, memoized); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if recorder.Code != http.StatusOK || recorder.Body.String() != string(first) {
+			t.Errorf("Expected the cached body, got %d %s", recorder.Code, recorder.Body.String())
+		}
+	}
+
+	if builds != 1 {
+		t.Errorf("Expected 1 build, got %d", builds)
+	}
+}
+
+// TestMemoize_TTL tests that the response is rebuilt once the TTL has elapsed.
+func TestMemoize_TTL(t *testing.T) {
+	var builds int64
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 0), 20*time.Millisecond, nil)
+
+	memoized.Bytes()
+	memoized.Bytes()
+	if builds != 1 {
+		t.Fatalf("Expected 1 build within the TTL, got %d", builds)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	body, _ := memoized.Bytes()
+	if builds != 2 {
+		t.Errorf("Expected a rebuild after the TTL, got %d builds", builds)
+	}
+	if !contains(string(body), `"data":2`) {
+		t.Errorf("Expected the rebuilt body, got %s", body)
+	}
+}
+
+// TestMemoize_Invalidate tests that a signal on the invalidation channel triggers exactly one rebuild.
+func TestMemoize_Invalidate(t *testing.T) {
+	var builds int64
+	invalidate := make(chan struct{}, 2)
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 0), 0, invalidate)
+
+	memoized.Bytes()
+
+	invalidate <- struct{}{}
+	invalidate <- struct{}{}
+
+	memoized.Bytes()
+	memoized.Bytes()
+
+	if builds != 2 {
+		t.Errorf("Expected 2 builds, got %d", builds)
+	}
+
+	close(invalidate)
+	memoized.Bytes()
+	memoized.Bytes()
+
+	if builds != 3 {
+		t.Errorf("Expected closing the channel to invalidate once, got %d builds", builds)
+	}
+}
+
+// TestMemoize_WriteSnapshot tests that WriteJSON writes a single build even when every read of the
+// memoized response finds it expired.
+func TestMemoize_WriteSnapshot(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	httpresponse.WithDeps(t, httpresponse.Deps{Clock: func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}})
+
+	var builds int64
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 0), time.Minute, nil)
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, memoized); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if builds != 1 || !contains(recorder.Body.String(), `"data":1`) {
+		t.Errorf("Expected a single build to be written, got %d builds and %s", builds, recorder.Body.String())
+	}
+}
+
+// TestMemoize_Stampede tests that concurrent first uses are coalesced into a single build.
+func TestMemoize_Stampede(t *testing.T) {
+	var builds int64
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 20*time.Millisecond), time.Minute, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := memoized.Bytes(); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if builds != 1 {
+		t.Errorf("Expected 1 build, got %d", builds)
+	}
+}
+
+// TestMemoize_Error tests that build errors are returned and not cached.
+func TestMemoize_Error(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().BareData(true).
+		SetExtra(map[string]interface{}{"k": "v"})
+	memoized := httpresponse.Memoize(builder, 0, nil)
+
+	if _, err := memoized.Bytes(); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if status := memoized.StatusCode(); status != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", status)
+	}
+}
+
+// statusBuilder returns a counting builder whose response has the given status code.
+func statusBuilder(builds *int64, status int) *httpresponse.HTTPResponseBuilder[int, int64, map[string]interface{}, int] {
+	return countingBuilder(builds, 0).SetCode(status).SetSuccess(status < 400)
+}
+
+// TestMemoized_TTLByStatus tests that a cached 404 expires faster than a 200 and that a 500 is never cached.
+func TestMemoized_TTLByStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	httpresponse.WithDeps(t, httpresponse.Deps{Clock: func() time.Time { return now }})
+
+	ttls := map[int]time.Duration{http.StatusNotFound: time.Second, httpresponse.StatusClass2xx: time.Minute}
+
+	var okBuilds, notFoundBuilds, failedBuilds int64
+	ok := httpresponse.Memoize(statusBuilder(&okBuilds, http.StatusOK), 0, nil).TTLByStatus(ttls)
+	notFound := httpresponse.Memoize(statusBuilder(&notFoundBuilds, http.StatusNotFound), 0, nil).TTLByStatus(ttls)
+	failed := httpresponse.Memoize(statusBuilder(&failedBuilds, http.StatusInternalServerError), 0, nil).TTLByStatus(ttls)
+
+	use := func() {
+		ok.Bytes()
+		notFound.Bytes()
+		failed.Bytes()
+	}
+
+	use()
+	use()
+	if okBuilds != 1 || notFoundBuilds != 1 || failedBuilds != 2 {
+		t.Fatalf("Expected 1, 1 and 2 builds, got %d, %d and %d", okBuilds, notFoundBuilds, failedBuilds)
+	}
+
+	now = now.Add(2 * time.Second)
+	use()
+	if okBuilds != 1 || notFoundBuilds != 2 {
+		t.Errorf("Expected the 404 to expire before the 200, got %d and %d builds", okBuilds, notFoundBuilds)
+	}
+}
+
+// TestMemoized_CacheIf tests that the predicate vetoes caching of a no-store response.
+func TestMemoized_CacheIf(t *testing.T) {
+	var builds int64
+	memoized := httpresponse.Memoize(countingBuilder(&builds, 0).NoStore(), 0, nil).
+		CacheIf(func(response *httpresponse.HTTPResponseOptions[int, int64, map[string]interface{}, int]) bool {
+			return response.Cache == nil || !response.Cache.NoStore
+		})
+
+	memoized.Bytes()
+	memoized.Bytes()
+
+	if builds != 2 {
+		t.Errorf("Expected a build per use, got %d builds", builds)
+	}
+}
@@ -0,0 +1,74 @@
+package httpresponse
+
+import (
+	"reflect"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// BuildMergeExtra builds a response from builders like rpsutil.Build, except that options replacing Extra,
+// such as SetExtra, merge the new map into the accumulated one key by key instead of replacing it. On key
+// collisions the later option, and hence the later builder, wins. Options setting Extra to nil leave the
+// accumulated map unchanged. The maps passed to the setters are never modified.
+//
+// Parameters:
+//   - builders: The builders whose options are applied, in order.
+//
+// Returns:
+//   - *HTTPResponseOptions: The built response.
+//   - error: An error if any validation or option fails.
+func BuildMergeExtra[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](builders ...*HTTPResponseBuilder[C, D, E, T]) (*HTTPResponseOptions[C, D, E, T], error) {
+
+	listers := make([]rpsutil.Lister[HTTPResponseOptions[C, D, E, T]], 0, len(builders))
+
+	for _, builder := range builders {
+		if builder == nil {
+			continue
+		}
+		listers = append(listers, rpsutil.Map[HTTPResponseOptions[C, D, E, T]](builder, mergeExtra[C, D, E, T]))
+	}
+
+	return rpsutil.Build(listers...)
+}
+
+// mergeExtra wraps next so that an Extra map it installs is merged into the previous one.
+func mergeExtra[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](next func(*HTTPResponseOptions[C, D, E, T]) error) func(*HTTPResponseOptions[C, D, E, T]) error {
+	return func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		previous := args.Extra
+
+		if err := next(args); err != nil {
+			return err
+		}
+
+		if len(previous) == 0 || sameMap(previous, args.Extra) {
+			return nil
+		}
+
+		merged := make(E, len(previous)+len(args.Extra))
+		for k, v := range previous {
+			merged[k] = v
+		}
+		for k, v := range args.Extra {
+			merged[k] = v
+		}
+		args.Extra = merged
+
+		return nil
+	}
+}
+
+// sameMap reports whether a and b are the same map instance.
+func sameMap[E map[string]any](a, b E) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
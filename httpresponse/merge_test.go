@@ -0,0 +1,33 @@
+package httpresponse_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestBuildMergeExtra tests that Extra maps contributed by several builders are merged key by key.
+func TestBuildMergeExtra(t *testing.T) {
+	first := map[string]interface{}{"trace_id": "abc", "region": "eu"}
+	second := map[string]interface{}{"gateway": "edge-1", "region": "us"}
+
+	response, err := httpresponse.BuildMergeExtra(
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("x").SetExtra(first),
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetExtra(second).SetExtra(nil),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]interface{}{"trace_id": "abc", "gateway": "edge-1", "region": "us"}
+	if !reflect.DeepEqual(response.Extra, want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra)
+	}
+	if response.Data != "x" {
+		t.Errorf("Expected other fields to apply normally, got %v", response.Data)
+	}
+	if len(first) != 2 || len(second) != 2 {
+		t.Errorf("Expected the setter maps not to be modified, got %v and %v", first, second)
+	}
+}
@@ -0,0 +1,66 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KeyMeta is the Extra key under which DescribeSelf injects the description of the envelope.
+const KeyMeta = "_meta"
+
+// WireFormatVersion is the version of the envelope wire format produced by this package.
+const WireFormatVersion = "1"
+
+// ErrMetaCollision is returned when building a self-describing response whose Extra already holds "_meta".
+var ErrMetaCollision = errors.New("httpresponse: Extra key collides with the self-description")
+
+// fieldDescriptions are the short descriptions of the standard envelope fields included in debug mode.
+var fieldDescriptions = map[Field]string{
+	FieldSuccess: "Whether the request succeeded.",
+	FieldMessage: "A human-readable description of the outcome.",
+	FieldCode:    "The status or application code of the outcome.",
+	FieldSubCode: "The fine-grained code refining the code of the outcome.",
+	FieldData:    "The payload of the response.",
+	FieldTotal:   "The total number of items, for paginated payloads.",
+}
+
+// DescribeSelf injects a "_meta" object into the response, holding schemaURL as "schema_url" and the
+// wire format version as "version" and, only in debug mode, short descriptions of the standard fields as
+// "fields", keyed by their names in the envelope, as renamed by SetCoreKeyCase or SetSuccessKey. Responses are not self-describing unless DescribeSelf is called.
+// It runs after all other options; building fails with ErrMetaCollision if Extra already holds "_meta".
+//
+// Parameters:
+//   - schemaURL: The URL of the schema describing the response.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) DescribeSelf(schemaURL string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if _, ok := args.Extra[KeyMeta]; ok {
+			return fmt.Errorf("%w: %q", ErrMetaCollision, KeyMeta)
+		}
+
+		meta := map[string]any{
+			"schema_url": schemaURL,
+			"version":    WireFormatVersion,
+		}
+
+		if Debug() {
+			keys := args.Keys().byField()
+			fields := make(map[string]string, len(fieldDescriptions))
+			for field, description := range fieldDescriptions {
+				fields[keys[field]] = description
+			}
+			meta["fields"] = fields
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyMeta] = meta
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,105 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestDescribeSelf tests the self-description outside debug mode.
+func TestDescribeSelf(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			DescribeSelf("https://api.example.com/schemas/user.json").
+			SetExtra(map[string]interface{}{"trace_id": "abc"}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Extra["trace_id"] != "abc" {
+		t.Errorf("Expected existing Extra to be kept, got %v", response.Extra)
+	}
+
+	meta, ok := response.Extra[httpresponse.KeyMeta].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected a _meta object, got %v", response.Extra)
+	}
+	if meta["schema_url"] != "https://api.example.com/schemas/user.json" || meta["version"] != httpresponse.WireFormatVersion {
+		t.Errorf("Expected schema URL and version, got %v", meta)
+	}
+	if _, ok := meta["fields"]; ok {
+		t.Errorf("Expected no field descriptions outside debug mode, got %v", meta["fields"])
+	}
+}
+
+// TestDescribeSelf_Debug tests that debug mode adds field descriptions.
+func TestDescribeSelf_Debug(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			DescribeSelf("https://api.example.com/schemas/user.json").
+			SetExtra(map[string]interface{}{"trace_id": "abc"}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields, ok := response.Extra[httpresponse.KeyMeta].(map[string]any)["fields"].(map[string]string)
+	if !ok || fields[httpresponse.KeyTotal] == "" || len(fields) != 6 {
+		t.Errorf("Expected descriptions of the six standard fields, got %v", fields)
+	}
+}
+
+// TestDescribeSelf_RenamedKeys tests that the field descriptions are keyed by the renamed envelope keys.
+func TestDescribeSelf_RenamedKeys(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetSuccessKey("ok").
+			SetCoreKeyCase(httpresponse.KeyCaseCamel).
+			DescribeSelf("https://api.example.com/schemas/user.json"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields, _ := response.Extra[httpresponse.KeyMeta].(map[string]any)["fields"].(map[string]string)
+	for _, key := range []string{"ok", "message", "code", "subCode", "data", "total"} {
+		if fields[key] == "" {
+			t.Errorf("Expected a description of %q, got %v", key, fields)
+		}
+	}
+	if len(fields) != 6 {
+		t.Errorf("Expected descriptions of the six standard fields, got %v", fields)
+	}
+}
+
+// TestDescribeSelf_Collision tests that a user Extra "_meta" key fails the build.
+func TestDescribeSelf_Collision(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{httpresponse.KeyMeta: "mine"}).
+			DescribeSelf("https://api.example.com/schemas/user.json"),
+	)
+	if !errors.Is(err, httpresponse.ErrMetaCollision) {
+		t.Errorf("Expected ErrMetaCollision, got %v", err)
+	}
+}
+
+// TestDescribeSelf_Default tests that responses are not self-describing by default.
+func TestDescribeSelf_Default(t *testing.T) {
+	body, err := (&httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{Success: true}).MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if contains(string(body), httpresponse.KeyMeta) {
+		t.Errorf("Expected no _meta by default, got %s", body)
+	}
+}
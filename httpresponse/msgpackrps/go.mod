@@ -0,0 +1,12 @@
+module github.com/zeroxsolutions/go-rps/httpresponse/msgpackrps
+
+go 1.18
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	github.com/zeroxsolutions/go-rps v0.0.0
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+
+replace github.com/zeroxsolutions/go-rps => ../../
@@ -0,0 +1,123 @@
+// Package msgpackrps encodes httpresponse envelopes in MessagePack, a compact binary alternative to JSON for
+// bandwidth-sensitive clients. It lives in its own module so that the MessagePack dependency stays optional
+// for users of the core packages.
+package msgpackrps
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// ContentType is the media type of the MessagePack encoding.
+const ContentType = "application/msgpack"
+
+// Codec encodes the merged representation of an envelope, a map of the standard fields and Extra fields.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+}
+
+// CodecFunc adapts a function to the Codec interface.
+type CodecFunc func(v any) ([]byte, error)
+
+// Marshal calls f(v).
+func (f CodecFunc) Marshal(v any) ([]byte, error) {
+	return f(v)
+}
+
+// defaultCodec encodes with github.com/vmihailenco/msgpack, sorting map keys for deterministic output.
+var defaultCodec = CodecFunc(func(v any) ([]byte, error) {
+
+	var buf bytes.Buffer
+
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetSortMapKeys(true)
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+})
+
+// Marshal encodes the response in MessagePack with github.com/vmihailenco/msgpack. The encoded map holds
+// exactly the fields of the JSON encoding produced by MarshalJSON, including the Extra fields, with keys
+// sorted alphabetically; ExtraKeyOrder is not honored. Integers are encoded as integers and other numbers
+// as floats.
+//
+// Parameters:
+//   - opts: The response to encode.
+//
+// Returns:
+//   - []byte: The MessagePack encoding of the response.
+//   - error: An error if encoding fails.
+func Marshal[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](opts *httpresponse.HTTPResponseOptions[C, D, E, T]) ([]byte, error) {
+	return MarshalWith(defaultCodec, opts)
+}
+
+// MarshalWith behaves like Marshal, but encodes the merged representation of the response with codec.
+//
+// Parameters:
+//   - codec: The codec encoding the merged representation.
+//   - opts: The response to encode.
+//
+// Returns:
+//   - []byte: The encoding of the response.
+//   - error: An error if encoding fails.
+func MarshalWith[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](codec Codec, opts *httpresponse.HTTPResponseOptions[C, D, E, T]) ([]byte, error) {
+
+	// Derive the merged representation from the JSON encoding, so that every envelope feature applies
+	body, err := opts.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var merged any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&merged); err != nil {
+		return nil, err
+	}
+
+	return codec.Marshal(convertNumbers(merged))
+}
+
+// convertNumbers replaces the json.Number values within v by integers where possible, and floats otherwise.
+func convertNumbers(v any) any {
+
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = convertNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = convertNumbers(e)
+		}
+	}
+
+	return v
+}
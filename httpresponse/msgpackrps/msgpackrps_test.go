@@ -0,0 +1,72 @@
+package msgpackrps_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/httpresponse/msgpackrps"
+)
+
+// TestMarshal tests that the MessagePack encoding round-trips to the merged representation.
+func TestMarshal(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, []string, map[string]any, uint64]{
+		Success: true,
+		Message: "ok",
+		Code:    200,
+		Data:    []string{"a", "b"},
+		Total:   1 << 60,
+		Extra:   map[string]any{"ratio": 0.5, "trace_id": "abc"},
+	}
+
+	body, err := msgpackrps.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Decode integers uniformly as 64-bit, regardless of the compact width they were encoded with
+	var decoded map[string]any
+	decoder := msgpack.NewDecoder(bytes.NewReader(body))
+	decoder.UseLooseInterfaceDecoding(true)
+	if err := decoder.Decode(&decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"success":  true,
+		"message":  "ok",
+		"code":     int64(200),
+		"data":     []any{"a", "b"},
+		"total":    int64(1 << 60),
+		"ratio":    0.5,
+		"trace_id": "abc",
+	}
+
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %v, got %v", expected, decoded)
+	}
+}
+
+// TestMarshalWith tests that the merged representation is passed to a custom codec.
+func TestMarshalWith(t *testing.T) {
+	var got any
+	codec := msgpackrps.CodecFunc(func(v any) ([]byte, error) {
+		got = v
+		return []byte("encoded"), nil
+	})
+
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]any, int]{Success: true, Extra: map[string]any{"n": 1}}
+
+	body, err := msgpackrps.MarshalWith(codec, response)
+	if err != nil || string(body) != "encoded" {
+		t.Fatalf("Expected the codec output, got %q, %v", body, err)
+	}
+
+	expected := map[string]any{"success": true, "message": "", "n": int64(1)}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,139 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrNamespaceClaimed is returned when claiming a namespace claimed before, or when writing a claimed
+	// namespace without its token.
+	ErrNamespaceClaimed = errors.New("httpresponse: namespace already claimed")
+
+	// ErrNamespaceCollision is returned when building a response in which a flat Extra key equals a namespace.
+	ErrNamespaceCollision = errors.New("httpresponse: Extra key collides with a namespace")
+)
+
+// Namespace holds the values written to one namespace, nested under its name in Extra.
+type Namespace map[string]any
+
+// NamespaceToken grants exclusive write access to a namespace. It is returned by ClaimNamespace.
+type NamespaceToken struct {
+	namespace string
+}
+
+// Namespace returns the name of the namespace the token grants access to.
+//
+// Returns:
+//   - string: The name of the namespace.
+func (namespaceToken *NamespaceToken) Namespace() string {
+	return namespaceToken.namespace
+}
+
+var (
+	claimedNamespacesMu sync.RWMutex
+	claimedNamespaces   = map[string]*NamespaceToken{}
+)
+
+// ClaimNamespace reserves a namespace for the caller, typically a plugin at start-up: afterwards, the
+// namespace can only be written with SetExtraClaimed and the returned token.
+//
+// Parameters:
+//   - ns: The name of the namespace, such as the name of the owning team or plugin.
+//
+// Returns:
+//   - *NamespaceToken: The token granting write access to the namespace.
+//   - error: ErrNamespaceClaimed if the namespace was claimed before.
+func ClaimNamespace(ns string) (*NamespaceToken, error) {
+
+	claimedNamespacesMu.Lock()
+	defer claimedNamespacesMu.Unlock()
+
+	if _, ok := claimedNamespaces[ns]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrNamespaceClaimed, ns)
+	}
+
+	token := &NamespaceToken{namespace: ns}
+	claimedNamespaces[ns] = token
+
+	return token, nil
+}
+
+// namespaceOwner returns the token of the claimed namespace ns, or nil if ns is unclaimed.
+func namespaceOwner(ns string) *NamespaceToken {
+
+	claimedNamespacesMu.RLock()
+	defer claimedNamespacesMu.RUnlock()
+
+	return claimedNamespaces[ns]
+}
+
+// SetExtraNamespaced sets key to value within the unclaimed namespace ns, nested under the ns Extra key,
+// keeping the other values of the namespace. Building fails with ErrNamespaceClaimed if ns is claimed, and
+// with ErrNamespaceCollision if a flat Extra key equals ns.
+//
+// Parameters:
+//   - ns: The name of the namespace.
+//   - key: The key within the namespace.
+//   - value: The value to set.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtraNamespaced(ns, key string, value any) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setNamespaced(ns, nil, key, value)
+}
+
+// SetExtraClaimed sets key to value within the namespace claimed with token, as SetExtraNamespaced does for
+// unclaimed namespaces.
+//
+// Parameters:
+//   - token: The token returned by ClaimNamespace; it must not be nil.
+//   - key: The key within the namespace.
+//   - value: The value to set.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetExtraClaimed(token *NamespaceToken, key string, value any) *HTTPResponseBuilder[C, D, E, T] {
+	return httpResponseBuilder.setNamespaced(token.Namespace(), token, key, value)
+}
+
+// setNamespaced queues the options writing key to the namespace ns with token, and checking for collisions
+// once all options are applied.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) setNamespaced(ns string, token *NamespaceToken, key string, value any) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if owner := namespaceOwner(ns); owner != token {
+			return fmt.Errorf("%w: %q", ErrNamespaceClaimed, ns)
+		}
+
+		current, ok := args.Extra[ns]
+		namespace, isNamespace := current.(Namespace)
+		if ok && !isNamespace {
+			return fmt.Errorf("%w: %q", ErrNamespaceCollision, ns)
+		}
+
+		updated := make(Namespace, len(namespace)+1)
+		for k, v := range namespace {
+			updated[k] = v
+		}
+		updated[key] = value
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[ns] = updated
+		args.Extra = extra
+
+		return nil
+	})
+
+	// A flat key set by a later option, such as SetExtra, would silently replace the namespace
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if current, ok := args.Extra[ns]; ok {
+			if _, isNamespace := current.(Namespace); !isNamespace {
+				return fmt.Errorf("%w: %q", ErrNamespaceCollision, ns)
+			}
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
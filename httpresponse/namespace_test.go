@@ -0,0 +1,94 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetExtraNamespaced tests that values are nested under their namespace.
+func TestSetExtraNamespaced(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtraNamespaced("search", "meta", 1).
+			SetExtraNamespaced("search", "info", "x").
+			SetExtraNamespaced("billing", "meta", 2),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"billing":{"meta":2},"message":"","search":{"info":"x","meta":1},"success":true}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+// claimRuns numbers the runs of TestClaimNamespace, whose claims outlive it, so that each run claims a fresh
+// namespace under -count.
+var claimRuns int
+
+// TestClaimNamespace tests that claimed namespaces can only be written with their token.
+func TestClaimNamespace(t *testing.T) {
+	claimRuns++
+	ns := fmt.Sprintf("payments-test-%d", claimRuns)
+
+	token, err := httpresponse.ClaimNamespace(ns)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := httpresponse.ClaimNamespace(ns); !errors.Is(err, httpresponse.ErrNamespaceClaimed) {
+		t.Errorf("Expected ErrNamespaceClaimed, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtraNamespaced(ns, "meta", 1),
+	)
+	if !errors.Is(err, httpresponse.ErrNamespaceClaimed) {
+		t.Errorf("Expected ErrNamespaceClaimed, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtraClaimed(token, "meta", 1),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if namespace, ok := response.Extra[ns].(httpresponse.Namespace); !ok || namespace["meta"] != 1 {
+		t.Errorf("Expected the claimed namespace, got %v", response.Extra)
+	}
+}
+
+// TestSetExtraNamespaced_Collision tests that flat keys equal to a namespace are rejected, whichever is set first.
+func TestSetExtraNamespaced_Collision(t *testing.T) {
+	flat := map[string]interface{}{"search": "flat"}
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(flat).
+			SetExtraNamespaced("search", "meta", 1),
+	)
+	if !errors.Is(err, httpresponse.ErrNamespaceCollision) {
+		t.Errorf("Expected ErrNamespaceCollision, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtraNamespaced("search", "meta", 1).
+			SetExtra(flat),
+	)
+	if !errors.Is(err, httpresponse.ErrNamespaceCollision) {
+		t.Errorf("Expected ErrNamespaceCollision, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package httpresponse
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// ndjsonFlushEvery is the number of lines written between flushes of a flushable writer.
+const ndjsonFlushEvery = 64
+
+// contentTypeNDJSON is the content type of NDJSON streams.
+const contentTypeNDJSON = "application/x-ndjson"
+
+// StreamNDJSON writes each item received from items to w as one line of newline-delimited JSON,
+// until items is closed. When wrap is true, every item is wrapped in an envelope built from the
+// builder's options, with the item as Data; otherwise items are encoded raw, like Data, with the marshalers
+// registered with RegisterDataMarshaler or else encoding/json. If w is an http.ResponseWriter, its Content-Type
+// is set to "application/x-ndjson".
+// If w can be flushed, it is flushed periodically and once more when the stream ends; an http.ResponseWriter
+// that cannot be flushed receives the stream when it ends, or once it exceeds StreamBufferBytes (see
+// ProbeStream).
+// If encoding an item or writing fails, the remaining items are drained in the background until items is
+// closed, so that the producer never blocks on an abandoned stream.
+//
+// Parameters:
+//   - w: The destination of the stream, such as an http.ResponseWriter.
+//   - items: The items to stream; closing the channel ends the stream, and the producer must close it even
+//     after a failure.
+//   - wrap: A boolean wrapping each item in the envelope (true) or writing it raw (false).
+//
+// Returns:
+//   - error: An error if building the envelope, encoding an item or writing fails.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) StreamNDJSON(w io.Writer, items <-chan D, wrap bool) (err error) {
+
+	var envelope *HTTPResponseOptions[C, D, E, T]
+	if wrap {
+		built, err := rpsutil.Build[HTTPResponseOptions[C, D, E, T]](httpResponseBuilder)
+		if err != nil {
+			return err
+		}
+		envelope = built
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok {
+		rw.Header().Set("Content-Type", contentTypeNDJSON)
+	}
+
+	stream := newStreamWriter(w, true)
+	defer func() {
+		if closeErr := stream.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	// On failure, unblock the producer of the items left unread
+	defer func() {
+		if err != nil {
+			go func() {
+				for range items {
+				}
+			}()
+		}
+	}()
+
+	lines := 0
+
+	for item := range items {
+
+		var (
+			line []byte
+			err  error
+		)
+
+		if envelope != nil {
+			itemEnvelope := *envelope
+			itemEnvelope.Data = item
+			line, err = itemEnvelope.MarshalJSON()
+		} else {
+			line, err = encodeData(item)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := stream.Write(append(line, '\n')); err != nil {
+			return err
+		}
+
+		lines++
+		if lines%ndjsonFlushEvery == 0 {
+			stream.Flush()
+		}
+	}
+
+	stream.Flush()
+
+	return nil
+}
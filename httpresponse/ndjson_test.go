@@ -0,0 +1,128 @@
+package httpresponse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// feed returns a closed channel holding n sequential integers.
+func feed(n int) <-chan int {
+	items := make(chan int, n)
+	for i := 0; i < n; i++ {
+		items <- i
+	}
+	close(items)
+
+	return items
+}
+
+// TestHTTPResponseBuilder_StreamNDJSON_Raw tests that raw items are written one per line.
+func TestHTTPResponseBuilder_StreamNDJSON_Raw(t *testing.T) {
+	var buf bytes.Buffer
+
+	builder := httpresponse.HTTPResponse[int, int, map[string]interface{}, int]()
+	if err := builder.StreamNDJSON(&buf, feed(3), false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if buf.String() != "0\n1\n2\n" {
+		t.Errorf("Expected raw lines, got %q", buf.String())
+	}
+}
+
+// TestHTTPResponseBuilder_StreamNDJSON_Wrapped tests that wrapped items each carry the envelope and the line count matches.
+func TestHTTPResponseBuilder_StreamNDJSON_Wrapped(t *testing.T) {
+	const n = 150
+
+	rec := httptest.NewRecorder()
+
+	builder := httpresponse.HTTPResponse[int, int, map[string]interface{}, int]().SetMessage("export")
+	if err := builder.StreamNDJSON(rec, feed(n), true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !rec.Flushed {
+		t.Error("Expected the recorder to be flushed")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	if len(lines) != n {
+		t.Fatalf("Expected %d lines, got %d", n, len(lines))
+	}
+
+	for i, line := range lines {
+		var envelope map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			t.Fatalf("Expected line %d to be valid JSON, got %v", i, err)
+		}
+		if envelope["success"] != true || envelope["message"] != "export" {
+			t.Errorf("Expected line %d to carry the envelope, got %v", i, line)
+		}
+		if i > 0 && envelope["data"] != float64(i) {
+			t.Errorf("Expected line %d to carry data %d, got %v", i, i, envelope["data"])
+		}
+	}
+}
+
+// TestHTTPResponseBuilder_StreamNDJSON_RawMarshaler tests that raw items are encoded with the registered data
+// marshalers and that an http.ResponseWriter gets the NDJSON content type.
+func TestHTTPResponseBuilder_StreamNDJSON_RawMarshaler(t *testing.T) {
+	items := make(chan money, 2)
+	items <- money{Cents: 1250}
+	items <- money{Cents: 7}
+	close(items)
+
+	rec := httptest.NewRecorder()
+
+	builder := httpresponse.HTTPResponse[int, money, map[string]interface{}, int]()
+	if err := builder.StreamNDJSON(rec, items, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Body.String() != "\"12.50\"\n\"0.07\"\n" {
+		t.Errorf("Expected registered encodings, got %q", rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %q", contentType)
+	}
+}
+
+// failingWriter is a writer whose every write fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// TestHTTPResponseBuilder_StreamNDJSON_DrainOnError tests that the items left unread after a write error are
+// drained, so that the producer does not block.
+func TestHTTPResponseBuilder_StreamNDJSON_DrainOnError(t *testing.T) {
+	items := make(chan int)
+	produced := make(chan struct{})
+
+	go func() {
+		defer close(produced)
+		defer close(items)
+		for i := 0; i < 100; i++ {
+			items <- i
+		}
+	}()
+
+	builder := httpresponse.HTTPResponse[int, int, map[string]interface{}, int]()
+	if err := builder.StreamNDJSON(failingWriter{}, items, false); err == nil {
+		t.Fatal("Expected a write error")
+	}
+
+	select {
+	case <-produced:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the producer not to block after the stream failed")
+	}
+}
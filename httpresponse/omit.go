@@ -0,0 +1,83 @@
+package httpresponse
+
+// Field identifies a standard field of the envelope.
+type Field string
+
+// Standard fields of the envelope, named after their JSON keys.
+const (
+	FieldSuccess Field = KeySuccess
+	FieldMessage Field = KeyMessage
+	FieldCode    Field = KeyCode
+	FieldSubCode Field = KeySubCode
+	FieldData    Field = KeyData
+	FieldTotal   Field = KeyTotal
+)
+
+// FieldExtra stands for all Extra fields in field orders (see SeqResponse.FieldOrder); it cannot be omitted.
+const FieldExtra Field = "extra"
+
+// envelopeFields lists the standard fields of the envelope.
+var envelopeFields = []Field{FieldSuccess, FieldMessage, FieldCode, FieldSubCode, FieldData, FieldTotal}
+
+// defaultStreamOrder is the order of the envelope fields of streamed responses, with Data last so that
+// clients see the metadata of the envelope before the array.
+var defaultStreamOrder = []Field{FieldSuccess, FieldMessage, FieldCode, FieldSubCode, FieldTotal, FieldExtra, FieldData}
+
+// OmitWhen omits field from the encoded envelope whenever pred reports true for its value, in addition
+// to the omitempty rules of the field. The predicate receives the Go value of the field (for example, the
+// Total as type T) and is consulted on every marshal. Calling OmitWhen several times for the same field
+// omits it when any of the predicates reports true. Predicates have no effect in bare data mode.
+//
+// Parameters:
+//   - field: The field to omit.
+//   - pred: Reports whether the field value should be omitted.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) OmitWhen(field Field, pred func(any) bool) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		omit := make(map[Field]func(any) bool, len(args.Omit)+1)
+		for k, v := range args.Omit {
+			omit[k] = v
+		}
+
+		if previous, ok := omit[field]; ok {
+			omit[field] = func(v any) bool { return previous(v) || pred(v) }
+		} else {
+			omit[field] = pred
+		}
+
+		args.Omit = omit
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// applyOmissions removes from rm the standard fields whose omission predicate reports true.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) applyOmissions(rm map[string]interface{}) {
+
+	for field, pred := range httpResponseOptions.Omit {
+
+		var value any
+		switch field {
+		case FieldSuccess:
+			value = httpResponseOptions.Success
+		case FieldMessage:
+			value = httpResponseOptions.Message
+		case FieldCode:
+			value = httpResponseOptions.Code
+		case FieldSubCode:
+			value = httpResponseOptions.SubCode
+		case FieldData:
+			value = httpResponseOptions.Data
+		case FieldTotal:
+			value = httpResponseOptions.Total
+		default:
+			continue
+		}
+
+		if pred(value) {
+			delete(rm, string(field))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// marshalFields builds and marshals builder, returning the decoded envelope.
+func marshalFields(t *testing.T, builder *httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]) map[string]interface{} {
+	t.Helper()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return fields
+}
+
+// TestOmitWhen_UnknownTotal tests omitting a total of -1, which stands for an unknown count.
+func TestOmitWhen_UnknownTotal(t *testing.T) {
+	unknown := func(v any) bool { return v == -1 }
+
+	fields := marshalFields(t, httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetData("x").
+		SetTotal(-1).
+		AllowNegativeTotal().
+		OmitWhen(httpresponse.FieldTotal, unknown))
+
+	if _, ok := fields["total"]; ok {
+		t.Errorf("Expected total to be omitted, got %v", fields)
+	}
+	if fields["data"] != "x" || fields["success"] != true {
+		t.Errorf("Expected unrelated fields untouched, got %v", fields)
+	}
+
+	// Explicitly set totals other than -1 are kept
+	fields = marshalFields(t, httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetTotal(3).
+		OmitWhen(httpresponse.FieldTotal, unknown))
+
+	if fields["total"] != float64(3) {
+		t.Errorf("Expected total 3, got %v", fields["total"])
+	}
+}
+
+// TestOmitWhen_Message tests a message predicate capturing the response status, composed with another predicate.
+func TestOmitWhen_Message(t *testing.T) {
+	status := 204
+
+	fields := marshalFields(t, httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetCode(status).
+		SetMessage("No content").
+		OmitWhen(httpresponse.FieldMessage, func(any) bool { return false }).
+		OmitWhen(httpresponse.FieldMessage, func(any) bool { return status == 204 }))
+
+	if _, ok := fields["message"]; ok {
+		t.Errorf("Expected message to be omitted, got %v", fields)
+	}
+	if fields["code"] != float64(204) || fields["success"] != true {
+		t.Errorf("Expected unrelated fields untouched, got %v", fields)
+	}
+}
+
+// TestOmitWhen_ExtraUntouched tests that predicates leave Extra and the other fields untouched.
+func TestOmitWhen_ExtraUntouched(t *testing.T) {
+	fields := marshalFields(t, httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetExtra(map[string]interface{}{"trace_id": "abc"}).
+		OmitWhen(httpresponse.FieldSuccess, func(any) bool { return true }))
+
+	if _, ok := fields["success"]; ok {
+		t.Errorf("Expected success to be omitted, got %v", fields)
+	}
+	if fields["trace_id"] != "abc" || fields["message"] != "" {
+		t.Errorf("Expected other fields untouched, got %v", fields)
+	}
+}
@@ -0,0 +1,216 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownOperation is returned by FromOpenAPIOperation when the document declares no operation with the
+// requested ID.
+var ErrUnknownOperation = errors.New("httpresponse: unknown OpenAPI operation")
+
+// ErrMissingHeader is returned when building a response preset from an OpenAPI operation without a value for
+// a header the operation declares as required.
+var ErrMissingHeader = errors.New("httpresponse: missing required header")
+
+// openAPIMethods are the keys of an OpenAPI path item that hold operations.
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+// openAPIDocument is the subset of an OpenAPI 3 document read by FromOpenAPIOperation.
+type openAPIDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Responses map[string]openAPIResponse `json:"responses"`
+		Headers   map[string]openAPIHeader   `json:"headers"`
+	} `json:"components"`
+}
+
+// openAPIOperation is the subset of an OpenAPI operation read by FromOpenAPIOperation.
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+// openAPIResponse is the subset of an OpenAPI response, or a reference to one, read by FromOpenAPIOperation.
+type openAPIResponse struct {
+	Ref         string                   `json:"$ref"`
+	Description string                   `json:"description"`
+	Headers     map[string]openAPIHeader `json:"headers"`
+}
+
+// openAPIHeader is the subset of an OpenAPI header, or a reference to one, read by FromOpenAPIOperation.
+type openAPIHeader struct {
+	Ref      string `json:"$ref"`
+	Required bool   `json:"required"`
+	Example  any    `json:"example"`
+	Schema   struct {
+		Default any `json:"default"`
+		Example any `json:"example"`
+	} `json:"schema"`
+}
+
+// value returns the value documented for the header: its example, else the default or example of its schema.
+func (openAPIHeader openAPIHeader) value() (string, bool) {
+
+	for _, v := range []any{openAPIHeader.Example, openAPIHeader.Schema.Default, openAPIHeader.Schema.Example} {
+		if v != nil {
+			return fmt.Sprint(v), true
+		}
+	}
+
+	return "", false
+}
+
+// FromOpenAPIOperation returns a builder per status code that the OpenAPI 3 document doc declares in the
+// responses of the operation whose operationId is operationID. Each builder is preset with the status as its
+// code, success below 400, and the documented description as its message. Declared headers with a documented
+// value (an example, or the default or example of their schema) are set to it, and building fails with
+// ErrMissingHeader while a header declared as required has no value, such as one set with SetHeader.
+// Setters called on a builder override these presets.
+//
+// Only JSON documents are read, and only the subset needed: paths, operation IDs, responses, descriptions
+// and headers, including references to components/responses and components/headers. Responses keyed by a
+// range such as "2XX" or by "default" are skipped, as are declared Content-Type headers, which OpenAPI ignores.
+//
+// Parameters:
+//   - doc: The OpenAPI 3 document, in JSON.
+//   - operationID: The ID of the operation.
+//
+// Returns:
+//   - map[int]*HTTPResponseBuilder: The preset builders, by status code.
+//   - error: ErrUnknownOperation if no operation has the ID, or an error if doc cannot be decoded or holds
+//     an unresolvable reference.
+func FromOpenAPIOperation[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](doc []byte, operationID string) (map[int]*HTTPResponseBuilder[C, D, E, T], error) {
+
+	var document openAPIDocument
+	if err := json.Unmarshal(doc, &document); err != nil {
+		return nil, fmt.Errorf("httpresponse: decode OpenAPI document: %w", err)
+	}
+
+	operation, err := document.operation(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make(map[int]*HTTPResponseBuilder[C, D, E, T], len(operation.Responses))
+
+	for key, response := range operation.Responses {
+
+		status, err := strconv.Atoi(key)
+		if err != nil || status < 100 || status > 599 {
+			continue
+		}
+
+		if response, err = document.resolveResponse(response); err != nil {
+			return nil, err
+		}
+
+		code, _ := parseCode[C](key)
+		httpResponseBuilder := HTTPResponse[C, D, E, T]().
+			setSuccess(status < http.StatusBadRequest).
+			SetCode(code).
+			SetMessage(response.Description)
+
+		for name, header := range response.Headers {
+
+			if strings.EqualFold(name, "Content-Type") {
+				continue
+			}
+
+			if header, err = document.resolveHeader(header); err != nil {
+				return nil, err
+			}
+
+			if value, ok := header.value(); ok {
+				name := name
+				httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+					args.setHeader(name, value)
+
+					return nil
+				})
+			}
+
+			if header.Required {
+				name := name
+				httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+					if args.Header().Get(name) == "" {
+						return fmt.Errorf("%w: %s", ErrMissingHeader, name)
+					}
+
+					return nil
+				})
+			}
+		}
+
+		builders[status] = httpResponseBuilder
+	}
+
+	return builders, nil
+}
+
+// operation returns the operation of the document with the given ID.
+func (openAPIDocument *openAPIDocument) operation(operationID string) (openAPIOperation, error) {
+
+	for path, item := range openAPIDocument.Paths {
+		for method, raw := range item {
+
+			if !openAPIMethods[method] {
+				continue
+			}
+
+			var operation openAPIOperation
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				return openAPIOperation{}, fmt.Errorf("httpresponse: decode OpenAPI operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			if operation.OperationID == operationID {
+				return operation, nil
+			}
+		}
+	}
+
+	return openAPIOperation{}, fmt.Errorf("%w: %q", ErrUnknownOperation, operationID)
+}
+
+// resolveResponse returns the response referenced by response, or response itself if it is no reference.
+func (openAPIDocument *openAPIDocument) resolveResponse(response openAPIResponse) (openAPIResponse, error) {
+
+	if response.Ref == "" {
+		return response, nil
+	}
+
+	resolved, ok := openAPIDocument.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	if !ok || resolved.Ref != "" {
+		return openAPIResponse{}, fmt.Errorf("httpresponse: unresolvable OpenAPI reference %q", response.Ref)
+	}
+
+	return resolved, nil
+}
+
+// resolveHeader returns the header referenced by header, or header itself if it is no reference.
+func (openAPIDocument *openAPIDocument) resolveHeader(header openAPIHeader) (openAPIHeader, error) {
+
+	if header.Ref == "" {
+		return header, nil
+	}
+
+	resolved, ok := openAPIDocument.Components.Headers[strings.TrimPrefix(header.Ref, "#/components/headers/")]
+	if !ok || resolved.Ref != "" {
+		return openAPIHeader{}, fmt.Errorf("httpresponse: unresolvable OpenAPI reference %q", header.Ref)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,131 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// openAPISpec is a small OpenAPI 3 document declaring two operations.
+const openAPISpec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Users", "version": "1.0.0"},
+	"paths": {
+		"/users": {
+			"summary": "Users",
+			"post": {
+				"operationId": "createUser",
+				"responses": {
+					"201": {
+						"description": "The user was created.",
+						"headers": {
+							"Location": {"required": true, "schema": {"type": "string"}},
+							"X-API-Version": {"$ref": "#/components/headers/APIVersion"},
+							"Content-Type": {"schema": {"type": "string", "example": "text/plain"}}
+						}
+					},
+					"409": {"$ref": "#/components/responses/Conflict"},
+					"default": {"description": "Unexpected error."}
+				}
+			}
+		},
+		"/users/{id}": {
+			"get": {
+				"operationId": "getUser",
+				"responses": {
+					"200": {"description": "The user."},
+					"404": {"description": "The user does not exist."}
+				}
+			}
+		}
+	},
+	"components": {
+		"responses": {
+			"Conflict": {"description": "The email address is already registered."}
+		},
+		"headers": {
+			"APIVersion": {"schema": {"type": "string", "default": "2024-01"}}
+		}
+	}
+}`
+
+// openAPIResponse is the response type built from the operations of openAPISpec.
+type openAPIResponse = httpresponse.HTTPResponseOptions[int, any, map[string]interface{}, int]
+
+// TestFromOpenAPIOperation tests that the builders carry the documented codes, messages and header values.
+func TestFromOpenAPIOperation(t *testing.T) {
+	builders, err := httpresponse.FromOpenAPIOperation[int, any, map[string]interface{}, int]([]byte(openAPISpec), "createUser")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(builders) != 2 || builders[201] == nil || builders[409] == nil {
+		t.Fatalf("Expected builders for 201 and 409, got %v", builders)
+	}
+
+	created, err := rpsutil.Build[openAPIResponse](builders[201].SetHeader("Location", "/users/42"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !created.Success || created.Code != 201 || created.Message != "The user was created." {
+		t.Errorf("Expected a 201 success with the documented message, got %+v", created)
+	}
+	if created.Header().Get("X-API-Version") != "2024-01" || created.Header().Get("Content-Type") != "" {
+		t.Errorf("Expected the documented X-API-Version header only, got %v", created.Header())
+	}
+
+	conflict, err := rpsutil.Build[openAPIResponse](builders[409])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if conflict.Success || conflict.Code != 409 || conflict.Message != "The email address is already registered." {
+		t.Errorf("Expected a 409 failure with the referenced description, got %+v", conflict)
+	}
+}
+
+// TestFromOpenAPIOperation_RequiredHeader tests that a required header without a value fails the build.
+func TestFromOpenAPIOperation_RequiredHeader(t *testing.T) {
+	builders, err := httpresponse.FromOpenAPIOperation[int, any, map[string]interface{}, int]([]byte(openAPISpec), "createUser")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := rpsutil.Build[openAPIResponse](builders[201]); !errors.Is(err, httpresponse.ErrMissingHeader) {
+		t.Errorf("Expected ErrMissingHeader, got %v", err)
+	}
+}
+
+// TestFromOpenAPIOperation_StringCodes tests a second operation with string codes and an overridden message.
+func TestFromOpenAPIOperation_StringCodes(t *testing.T) {
+	builders, err := httpresponse.FromOpenAPIOperation[string, any, map[string]interface{}, int]([]byte(openAPISpec), "getUser")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, any, map[string]interface{}, int]](builders[200])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !found.Success || found.Code != "200" || found.Message != "The user." {
+		t.Errorf("Expected a 200 success with the documented message, got %+v", found)
+	}
+
+	missing, err := rpsutil.Build[httpresponse.HTTPResponseOptions[string, any, map[string]interface{}, int]](
+		builders[404].SetMessage("User 42 does not exist."),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if missing.Success || missing.Code != "404" || missing.Message != "User 42 does not exist." {
+		t.Errorf("Expected a 404 failure with the overridden message, got %+v", missing)
+	}
+}
+
+// TestFromOpenAPIOperation_Unknown tests that an unknown operation ID is reported.
+func TestFromOpenAPIOperation_Unknown(t *testing.T) {
+	_, err := httpresponse.FromOpenAPIOperation[int, any, map[string]interface{}, int]([]byte(openAPISpec), "deleteUser")
+	if !errors.Is(err, httpresponse.ErrUnknownOperation) {
+		t.Errorf("Expected ErrUnknownOperation, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package httpresponse
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Operation identifies a kind of operation whose successful outcome has a standard message.
+type Operation int
+
+const (
+	OperationCreated Operation = iota + 1 // A resource was created.
+	OperationUpdated                      // A resource was updated.
+	OperationDeleted                      // A resource was deleted.
+)
+
+// String returns the lowercase name of the operation.
+func (operation Operation) String() string {
+	switch operation {
+	case OperationCreated:
+		return "created"
+	case OperationUpdated:
+		return "updated"
+	case OperationDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(operation))
+	}
+}
+
+// Default catalog messages of the standard operations.
+const (
+	DefaultCreatedMessage = "Resource created successfully."
+	DefaultUpdatedMessage = "Resource updated successfully."
+	DefaultDeletedMessage = "Resource deleted successfully."
+)
+
+var (
+	successMessagesMu sync.RWMutex
+	successMessages   = map[Operation]string{
+		OperationCreated: DefaultCreatedMessage,
+		OperationUpdated: DefaultUpdatedMessage,
+		OperationDeleted: DefaultDeletedMessage,
+	}
+)
+
+// RegisterSuccessMessage overrides the catalog message for an operation, or adds a message for a new one.
+// It can be used to localize the catalog or to adapt its wording to a specific API.
+//
+// Parameters:
+//   - operation: The operation whose message is registered.
+//   - message: The message used by SetSuccessMessage for the operation.
+func RegisterSuccessMessage(operation Operation, message string) {
+
+	successMessagesMu.Lock()
+	defer successMessagesMu.Unlock()
+
+	successMessages[operation] = message
+}
+
+// SuccessMessage looks up the catalog message for an operation.
+//
+// Returns:
+//   - string: The registered message.
+//   - bool: False if no message is registered for the operation.
+func SuccessMessage(operation Operation) (string, bool) {
+
+	successMessagesMu.RLock()
+	defer successMessagesMu.RUnlock()
+
+	message, ok := successMessages[operation]
+
+	return message, ok
+}
@@ -0,0 +1,75 @@
+package httpresponse_test
+
+import (
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestHTTPResponseBuilder_SetSuccessMessage_DefaultCatalog tests the default catalog messages for each operation.
+func TestHTTPResponseBuilder_SetSuccessMessage_DefaultCatalog(t *testing.T) {
+	cases := map[httpresponse.Operation]string{
+		httpresponse.OperationCreated: "Resource created successfully.",
+		httpresponse.OperationUpdated: "Resource updated successfully.",
+		httpresponse.OperationDeleted: "Resource deleted successfully.",
+	}
+
+	for operation, expected := range cases {
+		builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSuccessMessage(operation)
+
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got %v", operation, err)
+		}
+		if response.Message != expected {
+			t.Errorf("Expected Message for %v to be '%v', got %v", operation, expected, response.Message)
+		}
+		if response.Success != true {
+			t.Errorf("Expected Success for %v to be true, got %v", operation, response.Success)
+		}
+	}
+}
+
+// TestHTTPResponseBuilder_SetSuccessMessage_Override tests that registered catalog messages take effect.
+func TestHTTPResponseBuilder_SetSuccessMessage_Override(t *testing.T) {
+	const archived httpresponse.Operation = 100
+
+	httpresponse.RegisterSuccessMessage(archived, "Ressource archivée.")
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSuccessMessage(archived)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Message != "Ressource archivée." {
+		t.Errorf("Expected Message to be 'Ressource archivée.', got %v", response.Message)
+	}
+}
+
+// TestHTTPResponseBuilder_SetSuccessMessage_Unknown tests that an operation without a message fails the build.
+func TestHTTPResponseBuilder_SetSuccessMessage_Unknown(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSuccessMessage(httpresponse.Operation(-1))
+
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+// TestHTTPResponseBuilder_SetSuccessMessage_Label tests that build errors name the failing setter when labels are enabled.
+func TestHTTPResponseBuilder_SetSuccessMessage_Label(t *testing.T) {
+	rpsutil.EnableOptionLabels(true)
+	defer rpsutil.EnableOptionLabels(false)
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetMessage("first").
+		SetSuccessMessage(httpresponse.Operation(-1))
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+
+	expected := "building httpresponse.HTTPResponseOptions[int,string,map[string]interface {},int]: option #2 (SetSuccessMessage): httpresponse: no success message registered for operation Operation(-1)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+}
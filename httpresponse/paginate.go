@@ -0,0 +1,169 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// KeyLinks is the Extra key under which Paginate emits the links to the neighbouring pages.
+const KeyLinks = "_links"
+
+// ErrInvalidPagination is returned when building a paginated response with an invalid page size, base URL,
+// offset or non-slice data.
+var ErrInvalidPagination = errors.New("httpresponse: invalid pagination")
+
+// Paginate trims the slice Data to the page starting at the offset given by the "offset" query parameter of
+// baseURL (0 if absent) and emits the URLs of the neighbouring pages under the "_links" Extra key as "next"
+// and "prev". The URLs are baseURL with the "offset" query parameter replaced. The first page has no "prev"
+// link and the last page has no "next" link. Pagination runs after all other options, so it applies to the
+// final Data; building fails with ErrInvalidPagination if Data is not a slice.
+//
+// Parameters:
+//   - pageSize: The maximum number of elements per page; it must be positive.
+//   - baseURL: The URL of the requested page, such as the request URL.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) Paginate(pageSize int, baseURL string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if pageSize <= 0 {
+			return fmt.Errorf("%w: page size %d", ErrInvalidPagination, pageSize)
+		}
+
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPagination, err)
+		}
+
+		offset := 0
+		if raw := base.Query().Get("offset"); raw != "" {
+			if offset, err = strconv.Atoi(raw); err != nil || offset < 0 {
+				return fmt.Errorf("%w: offset %q", ErrInvalidPagination, raw)
+			}
+		}
+
+		return args.paginate(pageSize, base, offset)
+	})
+
+	return httpResponseBuilder
+}
+
+// PaginateParams paginates like Paginate, taking the offset and the page size from params, as returned by
+// ApplyListParams, rather than from baseURL. ApplyListParams has already replaced invalid offsets, so
+// malformed or negative client-supplied offsets yield the first page instead of failing the build.
+//
+// Parameters:
+//   - params: The pagination values of the request, as returned by ApplyListParams.
+//   - baseURL: The URL of the requested page, such as the request URL, from which the links are derived.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) PaginateParams(params ListParams, baseURL string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if params.PerPage <= 0 {
+			return fmt.Errorf("%w: page size %d", ErrInvalidPagination, params.PerPage)
+		}
+		if params.Offset < 0 {
+			return fmt.Errorf("%w: offset %d", ErrInvalidPagination, params.Offset)
+		}
+
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPagination, err)
+		}
+
+		return args.paginate(params.PerPage, base, params.Offset)
+	})
+
+	return httpResponseBuilder
+}
+
+// paginate trims the slice Data to the page of pageSize elements starting at offset and emits the links to
+// the neighbouring pages, derived from base.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) paginate(pageSize int, base *url.URL, offset int) error {
+
+	data := reflect.ValueOf(&httpResponseOptions.Data).Elem()
+	for data.Kind() == reflect.Interface && !data.IsNil() {
+		data = data.Elem()
+	}
+	if data.Kind() != reflect.Slice {
+		return fmt.Errorf("%w: data of type %T is not a slice", ErrInvalidPagination, httpResponseOptions.Data)
+	}
+
+	// Clamp before adding, so that a huge client-supplied offset cannot overflow
+	length := data.Len()
+	if offset > length {
+		offset = length
+	}
+	end := length
+	if length-offset > pageSize {
+		end = offset + pageSize
+	}
+
+	reflect.ValueOf(&httpResponseOptions.Data).Elem().Set(data.Slice(offset, end))
+
+	links := make(map[string]string, 2)
+	if end < length {
+		links["next"] = pageURL(base, end)
+	}
+	if offset > 0 {
+		prev := offset - pageSize
+		if prev < 0 {
+			prev = 0
+		}
+		links["prev"] = pageURL(base, prev)
+	}
+
+	if len(links) == 0 {
+		return nil
+	}
+
+	extra := make(E, len(httpResponseOptions.Extra)+1)
+	for k, v := range httpResponseOptions.Extra {
+		extra[k] = v
+	}
+	extra[KeyLinks] = links
+	httpResponseOptions.Extra = extra
+
+	return nil
+}
+
+// SetPageETag sets the ETag header of a paginated response to the ETag of the built response, which covers
+// the data of the page and its pagination: the "_links" emitted by Paginate and Total. Pages of the same
+// dataset therefore have distinct tags, even when their data is the same, and clients can revalidate each
+// page on its own with ServeJSON. The tag is derived after all other options and finalizers, whatever the
+// order of the setters, so that it sees the page rather than the whole data.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetPageETag() *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder.pageETag = true
+
+	return httpResponseBuilder
+}
+
+// setPageETag sets the ETag header of args to its ETag, for SetPageETag.
+func setPageETag[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](args *HTTPResponseOptions[C, D, E, T]) error {
+
+	etag, err := args.ETag()
+	if err != nil {
+		return err
+	}
+	args.setHeader("ETag", etag)
+
+	return nil
+}
+
+// pageURL returns base with the "offset" query parameter set to offset.
+func pageURL(base *url.URL, offset int) string {
+
+	query := base.Query()
+	query.Set("offset", strconv.Itoa(offset))
+
+	u := *base
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
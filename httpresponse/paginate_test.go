@@ -0,0 +1,197 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestPaginate_FirstPage tests that the first page links to the next page only.
+func TestPaginate_FirstPage(t *testing.T) {
+	data := make([]int, 25)
+	for i := range data {
+		data[i] = i
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			Paginate(10, "https://api.example.com/items?sort=name").
+			SetData(data),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(response.Data, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("Expected the first 10 items, got %v", response.Data)
+	}
+
+	want := map[string]string{"next": "https://api.example.com/items?offset=10&sort=name"}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyLinks], want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra[httpresponse.KeyLinks])
+	}
+}
+
+// TestPaginate_MiddlePage tests that a middle page links to both neighbouring pages.
+func TestPaginate_MiddlePage(t *testing.T) {
+	data := make([]int, 25)
+	for i := range data {
+		data[i] = i
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			Paginate(10, "/items?offset=5").
+			SetData(data),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(response.Data, []int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14}) {
+		t.Errorf("Expected items 5 to 14, got %v", response.Data)
+	}
+
+	want := map[string]string{"next": "/items?offset=15", "prev": "/items?offset=0"}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyLinks], want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra[httpresponse.KeyLinks])
+	}
+}
+
+// TestPaginate_LastPage tests that the last page links to the previous page only.
+func TestPaginate_LastPage(t *testing.T) {
+	data := make([]int, 25)
+	for i := range data {
+		data[i] = i
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			Paginate(10, "/items?offset=20").
+			SetData(data),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(response.Data, []int{20, 21, 22, 23, 24}) {
+		t.Errorf("Expected the last 5 items, got %v", response.Data)
+	}
+
+	want := map[string]string{"prev": "/items?offset=10"}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyLinks], want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra[httpresponse.KeyLinks])
+	}
+}
+
+// TestPaginate_Invalid tests that invalid pagination fails the build.
+func TestPaginate_Invalid(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("x").Paginate(10, "/items"),
+	)
+	if !errors.Is(err, httpresponse.ErrInvalidPagination) {
+		t.Errorf("Expected ErrInvalidPagination for non-slice data, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().Paginate(10, "/items?offset=-1"),
+	)
+	if !errors.Is(err, httpresponse.ErrInvalidPagination) {
+		t.Errorf("Expected ErrInvalidPagination for a negative offset, got %v", err)
+	}
+}
+
+// TestSetPageETag tests that pages of the same dataset get distinct, stable ETags.
+func TestSetPageETag(t *testing.T) {
+	data := make([]int, 25)
+
+	etag := func(baseURL string) string {
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+			httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+				SetData(data).
+				Paginate(10, baseURL).
+				SetPageETag(),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return response.Header().Get("ETag")
+	}
+
+	// The pages hold the same zero values, so only the pagination tells them apart
+	first, second, third := etag("/items"), etag("/items?offset=10"), etag("/items?offset=20")
+	if first == "" || first == second || second == third || first == third {
+		t.Errorf("Expected distinct ETags, got %s, %s and %s", first, second, third)
+	}
+	if again := etag("/items?offset=10"); again != second {
+		t.Errorf("Expected the same ETag for the same page, got %s and %s", second, again)
+	}
+}
+
+// TestSetPageETag_Order tests that the ETag covers the page whether SetPageETag is called before or after
+// Paginate, and that it is the ETag of the built response.
+func TestSetPageETag_Order(t *testing.T) {
+	data := make([]int, 25)
+
+	before, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			SetPageETag().
+			SetData(data).
+			Paginate(10, "/items?offset=10"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	after, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			SetData(data).
+			Paginate(10, "/items?offset=10").
+			SetPageETag(),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if before.Header().Get("ETag") != after.Header().Get("ETag") {
+		t.Errorf("Expected the same ETag in both orders, got %s and %s", before.Header().Get("ETag"), after.Header().Get("ETag"))
+	}
+
+	etag, err := after.ETag()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if after.Header().Get("ETag") != etag {
+		t.Errorf("Expected the ETag of the page %s, got %s", etag, after.Header().Get("ETag"))
+	}
+}
+
+// TestPaginate_HugeOffset tests that an offset near the maximum int yields an empty last page instead of
+// overflowing.
+func TestPaginate_HugeOffset(t *testing.T) {
+	data := make([]int, 25)
+	for i := range data {
+		data[i] = i
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+			Paginate(10, "/items?offset=9223372036854775807").
+			SetData(data),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Data) != 0 {
+		t.Errorf("Expected an empty page, got %v", response.Data)
+	}
+
+	want := map[string]string{"prev": "/items?offset=15"}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyLinks], want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra[httpresponse.KeyLinks])
+	}
+}
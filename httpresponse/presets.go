@@ -0,0 +1,137 @@
+package httpresponse
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TooManyRequests initializes a builder for a rate-limited response: it fails with code 429, sends the
+// Retry-After header and carries the "retry" action with the delay, in whole seconds, as "retry_after".
+//
+// Parameters:
+//   - retryAfter: How long the client should wait before retrying; rounded up to whole seconds.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the rate-limited response.
+func TooManyRequests[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](retryAfter time.Duration) *HTTPResponseBuilder[C, D, E, T] {
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	httpResponseBuilder := errorPreset[C, D, E, T](http.StatusTooManyRequests).
+		SetAction(ActionRetry, map[string]any{"retry_after": seconds})
+
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.setHeader("Retry-After", strconv.Itoa(seconds))
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// Unauthorized initializes a builder for a response to an unauthenticated request: it fails with code 401
+// and carries the "reauthenticate" action.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the unauthorized response.
+func Unauthorized[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+]() *HTTPResponseBuilder[C, D, E, T] {
+	return errorPreset[C, D, E, T](http.StatusUnauthorized).SetAction(ActionReauthenticate, nil)
+}
+
+// KeyConflict is the Extra key under which Conflict stores the details of the conflicting resource.
+const KeyConflict = "conflict"
+
+// Conflict initializes a builder for a response to a request conflicting with the current state of a
+// resource, such as a failed optimistic-locking check or a duplicate: it fails with code 409 and message,
+// and stores the conflicting resource or details under the "conflict" Extra key.
+//
+// Parameters:
+//   - message: The message describing the conflict.
+//   - conflicting: The conflicting resource or details, such as its current version; nil stores nothing.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the conflict response.
+func Conflict[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](message string, conflicting any) *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder := errorPreset[C, D, E, T](http.StatusConflict).SetMessage(message)
+
+	if conflicting != nil {
+		httpResponseBuilder = httpResponseBuilder.SetExtra(E{KeyConflict: conflicting})
+	}
+
+	return httpResponseBuilder
+}
+
+// errorPreset initializes a builder for a failed response with the given status as its code and the
+// status text as its message. With string codes, Code is the decimal form of status.
+func errorPreset[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](status int) *HTTPResponseBuilder[C, D, E, T] {
+
+	code, _ := parseCode[C](strconv.Itoa(status))
+
+	return HTTPResponse[C, D, E, T]().
+		setSuccess(false).
+		SetCode(code).
+		SetMessage(http.StatusText(status))
+}
+
+// DefaultUnprocessableEntityMessage is the message of the responses initialized by UnprocessableEntity.
+const DefaultUnprocessableEntityMessage = "The request contains invalid fields."
+
+// FieldError describes why the value of one input field failed validation.
+type FieldError struct {
+	Field   string // The name or path of the invalid field, such as "address.zip".
+	Code    string // The code of the validation failure, such as "required"; optional.
+	Message string // The message describing the validation failure.
+}
+
+// UnprocessableEntity initializes a builder for the canonical validation-failure response: it fails with
+// code 422 and DefaultUnprocessableEntityMessage, and lists the field errors under the "errors" Extra key as ErrorDetail
+// values, in the given order.
+//
+// Parameters:
+//   - errs: The validation failures of the request.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder seeded with the validation-failure response.
+func UnprocessableEntity[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](errs []FieldError) *HTTPResponseBuilder[C, D, E, T] {
+
+	details := make([]ErrorDetail, len(errs))
+	for i, err := range errs {
+		details[i] = ErrorDetail{Code: err.Code, Message: err.Message, Field: err.Field}
+	}
+
+	return errorPreset[C, D, E, T](http.StatusUnprocessableEntity).
+		SetMessage(DefaultUnprocessableEntityMessage).
+		SetExtra(E{KeyErrors: details})
+}
@@ -0,0 +1,63 @@
+package httpresponse_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestConflict tests that the preset sets code 409, the message and the conflict detail.
+func TestConflict(t *testing.T) {
+	type version struct {
+		ID      int `json:"id"`
+		Version int `json:"version"`
+	}
+
+	builder := httpresponse.Conflict[int, string, map[string]interface{}, int]("The order was modified concurrently.", version{ID: 7, Version: 3})
+
+	response, err := rpsutil.Build[actionResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success || response.Code != http.StatusConflict || response.Message != "The order was modified concurrently." {
+		t.Errorf("Expected a failed 409 with the message, got %+v", response)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(body), `"conflict":{"id":7,"version":3}`) {
+		t.Errorf("Expected the conflict detail, got %s", body)
+	}
+}
+
+// TestUnprocessableEntity tests that the preset sets code 422 and lists the field errors.
+func TestUnprocessableEntity(t *testing.T) {
+	builder := httpresponse.UnprocessableEntity[int, string, map[string]interface{}, int]([]httpresponse.FieldError{
+		{Field: "email", Code: "invalid_format", Message: "The email is not valid."},
+		{Field: "age", Message: "The age must be positive."},
+	})
+
+	response, err := rpsutil.Build[actionResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success || response.Code != http.StatusUnprocessableEntity || response.Message != httpresponse.DefaultUnprocessableEntityMessage {
+		t.Errorf("Expected a failed 422 with a message, got %+v", response)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `"errors":[{"code":"invalid_format","message":"The email is not valid.","field":"email"},{"message":"The age must be positive.","field":"age"}]`
+	if !contains(string(body), expected) {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
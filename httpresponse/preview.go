@@ -0,0 +1,96 @@
+package httpresponse
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// previewEllipsis marks a preview that was cut short to respect the requested size cap.
+const previewEllipsis = "..."
+
+// Preview returns a best-effort textual summary of the response options that never exceeds maxBytes.
+// Core fields (success, message, code, total) are rendered verbatim, the Data payload is replaced by its
+// type and, where applicable, its length, and only the keys of the Extra map are listed.
+//
+// Preview is safe to call on a nil receiver and never fails, which makes it suitable for logging
+// envelopes that could not be validated or encoded.
+//
+// Parameters:
+//   - maxBytes: The maximum size of the returned string; values less than or equal to zero disable the cap.
+//
+// Returns:
+//   - string: The summary, truncated and suffixed with "..." if it exceeds maxBytes.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) Preview(maxBytes int) string {
+
+	if httpResponseOptions == nil {
+		return truncatePreview("<nil>", maxBytes)
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "success=%t message=%q code=%v total=%v data=%s",
+		httpResponseOptions.Success,
+		httpResponseOptions.Message,
+		httpResponseOptions.Code,
+		httpResponseOptions.Total,
+		describeData(httpResponseOptions.Data),
+	)
+
+	// List Extra keys only, in a stable order, since values may be large or sensitive
+	keys := make([]string, 0, len(httpResponseOptions.Extra))
+	for k := range httpResponseOptions.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(&sb, " extra=[%s]", strings.Join(keys, " "))
+
+	return truncatePreview(sb.String(), maxBytes)
+}
+
+// describeData summarizes a data payload by its dynamic type and, for sized kinds, its length.
+func describeData(data any) string {
+
+	v := reflect.ValueOf(data)
+	if !v.IsValid() {
+		return "<nil>"
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String, reflect.Chan:
+		return fmt.Sprintf("%s(len=%d)", v.Type(), v.Len())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return fmt.Sprintf("%s(nil)", v.Type())
+		}
+	}
+
+	return v.Type().String()
+}
+
+// truncatePreview caps s to maxBytes, replacing the tail with an ellipsis when it does not fit.
+// The cut is moved back to a rune boundary so the result stays valid UTF-8.
+func truncatePreview(s string, maxBytes int) string {
+
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+
+	if maxBytes <= len(previewEllipsis) {
+		return previewEllipsis[:maxBytes]
+	}
+
+	cut := maxBytes - len(previewEllipsis)
+	for cut > 0 && !isRuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + previewEllipsis
+}
+
+// isRuneStart reports whether b can begin a UTF-8 encoded rune.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,61 @@
+package httpresponse_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestHTTPResponseOptions_Preview_Summary tests that Preview renders core fields verbatim and summarizes Data and Extra.
+func TestHTTPResponseOptions_Preview_Summary(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]{
+		Success: false,
+		Message: "encode failed",
+		Code:    500,
+		Data:    make([]int, 1000000),
+		Total:   1000000,
+		Extra: map[string]interface{}{
+			"trace_id": "abc",
+			"blob":     strings.Repeat("x", 1<<20),
+		},
+	}
+
+	preview := response.Preview(0)
+
+	expected := `success=false message="encode failed" code=500 total=1000000 data=[]int(len=1000000) extra=[blob trace_id]`
+	if preview != expected {
+		t.Errorf("Expected preview to be %v, got %v", expected, preview)
+	}
+}
+
+// TestHTTPResponseOptions_Preview_Cap tests that Preview never exceeds the requested size.
+func TestHTTPResponseOptions_Preview_Cap(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Message: strings.Repeat("é", 100),
+	}
+
+	for _, maxBytes := range []int{1, 3, 10, 25, 64} {
+		preview := response.Preview(maxBytes)
+		if len(preview) > maxBytes {
+			t.Errorf("Expected preview to be at most %d bytes, got %d (%v)", maxBytes, len(preview), preview)
+		}
+	}
+
+	preview := response.Preview(40)
+	if !strings.HasSuffix(preview, "...") {
+		t.Errorf("Expected truncated preview to end with '...', got %v", preview)
+	}
+	if !strings.HasPrefix(preview, `success=false message="é`) {
+		t.Errorf("Expected truncated preview to keep its prefix, got %v", preview)
+	}
+}
+
+// TestHTTPResponseOptions_Preview_NilReceiver tests that Preview is safe to call on a nil receiver.
+func TestHTTPResponseOptions_Preview_NilReceiver(t *testing.T) {
+	var response *httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+
+	if preview := response.Preview(100); preview != "<nil>" {
+		t.Errorf("Expected preview to be '<nil>', got %v", preview)
+	}
+}
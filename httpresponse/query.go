@@ -0,0 +1,143 @@
+package httpresponse
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// KeyQuery is the Extra key under which SetAppliedQuery emits the applied query.
+const KeyQuery = "query"
+
+// SortField is one key of the sort order applied by a list endpoint.
+type SortField struct {
+	Field string `json:"field"`          // The name of the sorted field.
+	Desc  bool   `json:"desc,omitempty"` // Whether the field is sorted in descending order.
+}
+
+// AppliedQuery describes the query a list endpoint actually applied.
+type AppliedQuery struct {
+	Filters map[string]string `json:"filters,omitempty"` // The applied filters by name.
+	Sort    []SortField       `json:"sort,omitempty"`    // The applied sort order, most significant key first.
+	Clamped []string          `json:"clamped,omitempty"` // The parameters that were clamped or dropped, such as "per_page" or "sort:name".
+}
+
+// ListParams holds the pagination values of a list request, ready to feed PaginateParams.
+type ListParams struct {
+	Offset  int // The offset of the first element of the page.
+	PerPage int // The maximum number of elements per page.
+}
+
+// SetAppliedQuery includes the applied query under the "query" Extra key.
+//
+// Parameters:
+//   - query: The query applied by the endpoint, as returned by ApplyListParams.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetAppliedQuery(query AppliedQuery) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyQuery] = query
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// ApplyListParams parses the list parameters of r: the filters named in allowedFilters, the comma-separated
+// "sort" parameter, where a leading "-" sorts a field in descending order, and the "offset" and "per_page"
+// parameters. Invalid values never fail the request; they are clamped or dropped and listed in Clamped:
+//   - "per_page" is clamped to 1..maxPerPage and defaults to maxPerPage;
+//   - "offset" defaults to 0 when missing, negative or malformed;
+//   - sort fields not in allowedSorts are dropped as "sort:<field>".
+//
+// Parameters:
+//   - r: The list request.
+//   - allowedFilters: The names of the query parameters accepted as filters; other parameters are ignored.
+//   - allowedSorts: The names of the fields accepted in the sort order.
+//   - maxPerPage: The largest accepted page size; it must be positive.
+//
+// Returns:
+//   - AppliedQuery: The query to echo with SetAppliedQuery.
+//   - ListParams: The pagination values to feed PaginateParams.
+//
+// Example usage:
+//
+//	query, params := httpresponse.ApplyListParams(r, []string{"status"}, []string{"name"}, 100)
+//	builder.SetData(items).SetAppliedQuery(query).PaginateParams(params, r.URL.String())
+func ApplyListParams(r *http.Request, allowedFilters, allowedSorts []string, maxPerPage int) (AppliedQuery, ListParams) {
+
+	values := r.URL.Query()
+
+	var query AppliedQuery
+
+	for _, name := range allowedFilters {
+		if value := values.Get(name); value != "" {
+			if query.Filters == nil {
+				query.Filters = make(map[string]string)
+			}
+			query.Filters[name] = value
+		}
+	}
+
+	for _, field := range strings.Split(values.Get("sort"), ",") {
+
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(field, "-")
+		name := strings.TrimPrefix(field, "-")
+
+		if !contains(allowedSorts, name) {
+			query.Clamped = append(query.Clamped, "sort:"+name)
+			continue
+		}
+
+		query.Sort = append(query.Sort, SortField{Field: name, Desc: desc})
+	}
+
+	params := ListParams{PerPage: maxPerPage}
+
+	if raw := values.Get("offset"); raw != "" {
+		if offset, err := strconv.Atoi(raw); err == nil && offset >= 0 {
+			params.Offset = offset
+		} else {
+			query.Clamped = append(query.Clamped, "offset")
+		}
+	}
+
+	if raw := values.Get("per_page"); raw != "" {
+		perPage, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			query.Clamped = append(query.Clamped, "per_page")
+		case perPage < 1:
+			params.PerPage = 1
+			query.Clamped = append(query.Clamped, "per_page")
+		case perPage > maxPerPage:
+			query.Clamped = append(query.Clamped, "per_page")
+		default:
+			params.PerPage = perPage
+		}
+	}
+
+	return query, params
+}
+
+// contains reports whether values contains value.
+func contains(values []string, value string) bool {
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package httpresponse_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestApplyListParams tests the parsing of filters, sort order and pagination.
+func TestApplyListParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?status=active&owner=me&sort=-created,name&offset=20&per_page=10", nil)
+
+	query, params := httpresponse.ApplyListParams(r, []string{"status"}, []string{"name", "created"}, 50)
+
+	if !reflect.DeepEqual(query.Filters, map[string]string{"status": "active"}) {
+		t.Errorf("Expected the status filter, got %v", query.Filters)
+	}
+	expectedSort := []httpresponse.SortField{{Field: "created", Desc: true}, {Field: "name"}}
+	if !reflect.DeepEqual(query.Sort, expectedSort) {
+		t.Errorf("Expected sort %v, got %v", expectedSort, query.Sort)
+	}
+	if len(query.Clamped) != 0 {
+		t.Errorf("Expected nothing clamped, got %v", query.Clamped)
+	}
+	if params != (httpresponse.ListParams{Offset: 20, PerPage: 10}) {
+		t.Errorf("Expected offset 20 and 10 per page, got %+v", params)
+	}
+}
+
+// TestApplyListParams_Clamping tests that invalid page sizes and offsets are clamped and unknown sort fields dropped.
+func TestApplyListParams_Clamping(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected httpresponse.ListParams
+		clamped  []string
+	}{
+		{"/items", httpresponse.ListParams{PerPage: 50}, nil},
+		{"/items?per_page=500", httpresponse.ListParams{PerPage: 50}, []string{"per_page"}},
+		{"/items?per_page=0", httpresponse.ListParams{PerPage: 1}, []string{"per_page"}},
+		{"/items?per_page=many&offset=-5", httpresponse.ListParams{PerPage: 50}, []string{"offset", "per_page"}},
+		{"/items?sort=name,-secret", httpresponse.ListParams{PerPage: 50}, []string{"sort:secret"}},
+	}
+
+	for _, tt := range tests {
+		query, params := httpresponse.ApplyListParams(httptest.NewRequest("GET", tt.target, nil), nil, []string{"name"}, 50)
+
+		if params != tt.expected {
+			t.Errorf("%s: Expected %+v, got %+v", tt.target, tt.expected, params)
+		}
+		if !reflect.DeepEqual(query.Clamped, tt.clamped) {
+			t.Errorf("%s: Expected clamped %v, got %v", tt.target, tt.clamped, query.Clamped)
+		}
+	}
+}
+
+// TestSetAppliedQuery tests the JSON shape of the applied query.
+func TestSetAppliedQuery(t *testing.T) {
+	query := httpresponse.AppliedQuery{
+		Filters: map[string]string{"status": "active"},
+		Sort:    []httpresponse.SortField{{Field: "created", Desc: true}, {Field: "name"}},
+		Clamped: []string{"per_page"},
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []string, map[string]interface{}, int]().SetAppliedQuery(query),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"message":"","query":{"filters":{"status":"active"},"sort":[{"field":"created","desc":true},{"field":"name"}],"clamped":["per_page"]},"success":true}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+// TestPaginateParams tests that the offsets clamped by ApplyListParams paginate instead of failing the build.
+func TestPaginateParams(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected []int
+	}{
+		{"/items?offset=abc", []int{0, 1}},
+		{"/items?offset=-1", []int{0, 1}},
+		{"/items?offset=3&per_page=2", []int{3, 4}},
+		{"/items?offset=9223372036854775807", []int{}},
+	}
+
+	for _, tt := range tests {
+		_, params := httpresponse.ApplyListParams(httptest.NewRequest("GET", tt.target, nil), nil, nil, 2)
+
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](
+			httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().
+				SetData([]int{0, 1, 2, 3, 4}).
+				PaginateParams(params, tt.target),
+		)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", tt.target, err)
+		}
+		if !reflect.DeepEqual(response.Data, tt.expected) {
+			t.Errorf("%s: Expected %v, got %v", tt.target, tt.expected, response.Data)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidUsage is returned by the option of SetUsage when the usage or quota is negative.
+var ErrInvalidUsage = errors.New("httpresponse: invalid usage")
+
+const (
+	// KeyQuotaUsed is the Extra key carrying the consumed part of the quota.
+	KeyQuotaUsed = "quota_used"
+
+	// KeyQuotaLimit is the Extra key carrying the quota limit.
+	KeyQuotaLimit = "quota_limit"
+
+	// KeyUsage is the Extra key under which SetUsage stores the Usage block.
+	KeyUsage = "usage"
+
+	// HeaderQuotaUsed is the header carrying the consumed part of the quota.
+	HeaderQuotaUsed = "X-Quota-Used"
+
+	// HeaderQuotaLimit is the header carrying the quota limit.
+	HeaderQuotaLimit = "X-Quota-Limit"
+
+	// HeaderQuotaRemaining is the header carrying the unconsumed part of the quota.
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+
+	// HeaderQuotaReset is the header carrying the time the quota resets, in Unix seconds.
+	HeaderQuotaReset = "X-Quota-Reset"
+)
+
+// Usage is the usage block of a metered API, stored under the "usage" Extra key by SetUsage.
+type Usage struct {
+	Used      int64     `json:"used"`                // The number of calls consumed.
+	Quota     int64     `json:"quota,omitempty"`     // The number of calls allowed; omitted if unlimited.
+	Remaining *int64    `json:"remaining,omitempty"` // The number of calls left, at least zero; omitted if unlimited.
+	Reset     time.Time `json:"reset"`               // The time the quota resets.
+	Overage   bool      `json:"overage"`             // Whether more calls were consumed than allowed.
+}
+
+// SetQuota reports the quota usage of the client under the "quota_used" and "quota_limit" Extra keys and
+// in the X-Quota-Used and X-Quota-Limit headers sent by the writers. A zero limit stands for an unlimited
+// quota: the limit key and header are omitted, removing any limit set before.
+//
+// Parameters:
+//   - used: The consumed part of the quota.
+//   - limit: The quota limit; zero if the quota is unlimited.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetQuota(used, limit int64) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		extra := make(E, len(args.Extra)+2)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyQuotaUsed] = used
+		args.setHeader(HeaderQuotaUsed, strconv.FormatInt(used, 10))
+
+		if limit != 0 {
+			extra[KeyQuotaLimit] = limit
+			args.setHeader(HeaderQuotaLimit, strconv.FormatInt(limit, 10))
+		} else {
+			delete(extra, KeyQuotaLimit)
+			args.Headers.Del(HeaderQuotaLimit)
+		}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SetUsage reports the usage of a metered API as a Usage block under the "usage" Extra key, with the
+// remaining calls and the overage flag computed from used and quota, and in the X-Quota-Used,
+// X-Quota-Limit, X-Quota-Remaining and X-Quota-Reset headers sent by the writers. A zero quota stands for
+// an unlimited quota: the quota and remaining fields and headers are omitted and there is never an overage.
+// The build fails with ErrInvalidUsage if used or quota is negative.
+//
+// Parameters:
+//   - used: The number of calls consumed.
+//   - quota: The number of calls allowed; zero if the quota is unlimited.
+//   - reset: The time the quota resets.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetUsage(used, quota int64, reset time.Time) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if used < 0 || quota < 0 {
+			return fmt.Errorf("%w: used %d, quota %d", ErrInvalidUsage, used, quota)
+		}
+
+		usage := Usage{Used: used, Reset: reset}
+
+		args.setHeader(HeaderQuotaUsed, strconv.FormatInt(used, 10))
+		args.setHeader(HeaderQuotaReset, strconv.FormatInt(reset.Unix(), 10))
+
+		if quota != 0 {
+			remaining := quota - used
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			usage.Quota, usage.Remaining, usage.Overage = quota, &remaining, used > quota
+
+			args.setHeader(HeaderQuotaLimit, strconv.FormatInt(quota, 10))
+			args.setHeader(HeaderQuotaRemaining, strconv.FormatInt(remaining, 10))
+		} else {
+			args.Headers.Del(HeaderQuotaLimit)
+			args.Headers.Del(HeaderQuotaRemaining)
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyUsage] = usage
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,151 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetQuota tests that the quota usage is written to the body and the headers of a metered response.
+func TestSetQuota(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetQuota(420, 1000),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if body := recorder.Body.String(); !contains(body, `"quota_limit":1000`) || !contains(body, `"quota_used":420`) {
+		t.Errorf("Expected the quota in the body, got %s", body)
+	}
+	if used, limit := recorder.Header().Get("X-Quota-Used"), recorder.Header().Get("X-Quota-Limit"); used != "420" || limit != "1000" {
+		t.Errorf("Expected headers 420 and 1000, got %q and %q", used, limit)
+	}
+}
+
+// TestSetQuota_Unlimited tests that a zero limit omits the limit fields, including those set before.
+func TestSetQuota_Unlimited(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetQuota(1, 10).SetQuota(42, 0),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := response.Extra[httpresponse.KeyQuotaLimit]; ok || response.Extra[httpresponse.KeyQuotaUsed] != int64(42) {
+		t.Errorf("Expected only the used quota, got %v", response.Extra)
+	}
+	if response.Header().Get("X-Quota-Limit") != "" || response.Header().Get("X-Quota-Used") != "42" {
+		t.Errorf("Expected only the used quota header, got %v", response.Header())
+	}
+}
+
+// TestSetUsage tests the usage block of a response within its quota.
+func TestSetUsage(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetUsage(420, 1000, time.Unix(1700000000, 0).UTC()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(response.Extra[httpresponse.KeyUsage])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"used":420,"quota":1000,"remaining":580,"reset":"2023-11-14T22:13:20Z","overage":false}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+}
+
+// TestSetUsage_Overage tests that usage beyond the quota is flagged, with no calls remaining.
+func TestSetUsage_Overage(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetUsage(1200, 1000, time.Unix(1700000000, 0).UTC()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(response.Extra[httpresponse.KeyUsage])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"used":1200,"quota":1000,"remaining":0,"reset":"2023-11-14T22:13:20Z","overage":true}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+}
+
+// TestSetUsage_Unlimited tests that an unlimited quota omits the quota and remaining fields and headers.
+func TestSetUsage_Unlimited(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetUsage(42, 0, time.Unix(1700000000, 0).UTC()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := json.Marshal(response.Extra[httpresponse.KeyUsage])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"used":42,"reset":"2023-11-14T22:13:20Z","overage":false}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+	if response.Header().Get("X-Quota-Limit") != "" || response.Header().Get("X-Quota-Remaining") != "" {
+		t.Errorf("Expected no limit headers, got %v", response.Header())
+	}
+}
+
+// TestSetUsage_Headers tests that the quota headers are written.
+func TestSetUsage_Headers(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetUsage(420, 1000, time.Unix(1700000000, 0).UTC()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]string{
+		"X-Quota-Used":      "420",
+		"X-Quota-Limit":     "1000",
+		"X-Quota-Remaining": "580",
+		"X-Quota-Reset":     "1700000000",
+	}
+	for header, value := range want {
+		if got := recorder.Header().Get(header); got != value {
+			t.Errorf("Expected %s %s, got %q", header, value, got)
+		}
+	}
+}
+
+// TestSetUsage_Negative tests that negative usage fails the build.
+func TestSetUsage_Negative(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetUsage(-1, 10, time.Now()),
+	)
+	if !errors.Is(err, httpresponse.ErrInvalidUsage) {
+		t.Errorf("Expected ErrInvalidUsage, got %v", err)
+	}
+}
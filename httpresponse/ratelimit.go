@@ -0,0 +1,66 @@
+package httpresponse
+
+import (
+	"strconv"
+	"time"
+)
+
+// KeyRateLimit is the Extra key under which MirrorRateLimit copies the rate-limit state.
+const KeyRateLimit = "rate_limit"
+
+// SetRateLimit reports the state of the rate limiter in the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers sent by the writers. The reset time is sent in Unix seconds.
+//
+// Parameters:
+//   - limit: The number of requests allowed in the current window.
+//   - remaining: The number of requests left in the current window; negative values are sent as 0.
+//   - reset: The time at which the current window resets.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetRateLimit(limit, remaining int, reset time.Time) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		args.setHeader("X-RateLimit-Limit", strconv.Itoa(limit))
+		args.setHeader("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		args.setHeader("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// MirrorRateLimit also reports the rate-limit state set by SetRateLimit in the body, under the "rate_limit"
+// Extra key as an object with "limit", "remaining" and "reset" (Unix seconds) entries, for clients that cannot
+// read response headers. It runs after all other options, so it mirrors the last SetRateLimit call regardless
+// of the order of the setters, and does nothing if no rate limit was set.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) MirrorRateLimit() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		state := make(map[string]int64, 3)
+		for key, header := range map[string]string{
+			"limit":     "X-RateLimit-Limit",
+			"remaining": "X-RateLimit-Remaining",
+			"reset":     "X-RateLimit-Reset",
+		} {
+			value, err := strconv.ParseInt(args.Headers.Get(header), 10, 64)
+			if err != nil {
+				return nil
+			}
+			state[key] = value
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyRateLimit] = state
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,59 @@
+package httpresponse_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetRateLimit tests that the three rate-limit headers are written.
+func TestSetRateLimit(t *testing.T) {
+	reset := time.Unix(1700000000, 0)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetRateLimit(100, 42, reset),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]string{
+		"X-RateLimit-Limit":     "100",
+		"X-RateLimit-Remaining": "42",
+		"X-RateLimit-Reset":     "1700000000",
+	}
+	for header, value := range want {
+		if got := recorder.Header().Get(header); got != value {
+			t.Errorf("Expected %s %s, got %q", header, value, got)
+		}
+	}
+	if response.Extra != nil {
+		t.Errorf("Expected no Extra without mirroring, got %v", response.Extra)
+	}
+}
+
+// TestMirrorRateLimit tests that the rate-limit state is mirrored in Extra, regardless of setter order.
+func TestMirrorRateLimit(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			MirrorRateLimit().
+			SetRateLimit(100, -1, time.Unix(1700000000, 0)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]int64{"limit": 100, "remaining": 0, "reset": 1700000000}
+	if !reflect.DeepEqual(response.Extra[httpresponse.KeyRateLimit], want) {
+		t.Errorf("Expected %v, got %v", want, response.Extra[httpresponse.KeyRateLimit])
+	}
+}
@@ -0,0 +1,147 @@
+package httpresponse
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// RedactedValue replaces the Extra values of the envelopes kept by a ResponseRecorder with default redaction.
+const RedactedValue = "[REDACTED]"
+
+// ResponseRecorder is a bounded ring buffer of the most recently built envelopes of one type. Envelopes are
+// recorded after every successful rpsutil.Build of *HTTPResponseOptions[C, D, E, T], redacted copies only.
+// It is safe for concurrent use.
+type ResponseRecorder[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	redact func(*HTTPResponseOptions[C, D, E, T])
+	closed int32
+
+	mu        sync.Mutex
+	responses []*HTTPResponseOptions[C, D, E, T]
+	next      int
+	full      bool
+}
+
+// NewResponseRecorder starts recording the last capacity envelopes of type HTTPResponseOptions[C, D, E, T].
+// Nothing is recorded unless a recorder is created. Each recorder registers a build interceptor that cannot
+// be removed, so recorders are meant to be created once, typically at start-up when a debug flag is set,
+// and stopped with Close.
+//
+// Each envelope is copied and passed to redact before it is kept; redact may modify the copy freely.
+// A nil redact applies the default redaction, which drops Data and the headers and replaces every Extra
+// value with "[REDACTED]", keeping the status, message, code, total and Extra keys.
+//
+// Parameters:
+//   - capacity: The maximum number of envelopes kept; values below 1 are treated as 1.
+//   - redact: The function redacting each kept envelope.
+//
+// Returns:
+//   - *ResponseRecorder: The recorder.
+func NewResponseRecorder[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](capacity int, redact func(*HTTPResponseOptions[C, D, E, T])) *ResponseRecorder[C, D, E, T] {
+
+	if capacity < 1 {
+		capacity = 1
+	}
+	if redact == nil {
+		redact = defaultRedact[C, D, E, T]
+	}
+
+	responseRecorder := &ResponseRecorder[C, D, E, T]{
+		redact:    redact,
+		responses: make([]*HTTPResponseOptions[C, D, E, T], capacity),
+	}
+
+	rpsutil.RegisterInterceptor(responseRecorder.intercept)
+
+	return responseRecorder
+}
+
+// intercept records the envelopes of successful builds of the recorded type.
+func (responseRecorder *ResponseRecorder[C, D, E, T]) intercept(info rpsutil.BuildInfo) {
+
+	if info.Err != nil || atomic.LoadInt32(&responseRecorder.closed) != 0 {
+		return
+	}
+
+	if response, ok := info.Value.(*HTTPResponseOptions[C, D, E, T]); ok {
+		responseRecorder.Record(response)
+	}
+}
+
+// Record keeps a redacted copy of response, evicting the oldest envelope if the recorder is full.
+// Built envelopes are recorded automatically; Record is useful for envelopes assembled without Build.
+//
+// Parameters:
+//   - response: The envelope to record; nil is ignored.
+func (responseRecorder *ResponseRecorder[C, D, E, T]) Record(response *HTTPResponseOptions[C, D, E, T]) {
+
+	if response == nil {
+		return
+	}
+
+	// Redact outside the lock, so that slow redaction does not serialize concurrent builds
+	redacted := response.clone()
+	responseRecorder.redact(redacted)
+
+	responseRecorder.mu.Lock()
+	defer responseRecorder.mu.Unlock()
+
+	responseRecorder.responses[responseRecorder.next] = redacted
+	responseRecorder.next = (responseRecorder.next + 1) % len(responseRecorder.responses)
+	if responseRecorder.next == 0 {
+		responseRecorder.full = true
+	}
+}
+
+// Recent returns the recorded envelopes, oldest first. The envelopes are shared with the recorder and
+// must not be modified.
+//
+// Returns:
+//   - []*HTTPResponseOptions[C, D, E, T]: Up to capacity of the most recently recorded envelopes.
+func (responseRecorder *ResponseRecorder[C, D, E, T]) Recent() []*HTTPResponseOptions[C, D, E, T] {
+
+	responseRecorder.mu.Lock()
+	defer responseRecorder.mu.Unlock()
+
+	if !responseRecorder.full {
+		return append([]*HTTPResponseOptions[C, D, E, T](nil), responseRecorder.responses[:responseRecorder.next]...)
+	}
+
+	recent := make([]*HTTPResponseOptions[C, D, E, T], 0, len(responseRecorder.responses))
+	recent = append(recent, responseRecorder.responses[responseRecorder.next:]...)
+
+	return append(recent, responseRecorder.responses[:responseRecorder.next]...)
+}
+
+// Close stops recording built envelopes. The envelopes recorded so far remain available through Recent.
+func (responseRecorder *ResponseRecorder[C, D, E, T]) Close() {
+	atomic.StoreInt32(&responseRecorder.closed, 1)
+}
+
+// defaultRedact drops the data and headers of response and replaces its Extra values.
+func defaultRedact[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](response *HTTPResponseOptions[C, D, E, T]) {
+
+	var data D
+	response.Data = data
+	response.Headers = nil
+
+	for k := range response.Extra {
+		response.Extra[k] = RedactedValue
+	}
+}
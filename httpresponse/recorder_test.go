@@ -0,0 +1,96 @@
+package httpresponse_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// recordedResponse is only built by the recorder tests, so other tests do not disturb the recorded envelopes.
+type recordedResponse = httpresponse.HTTPResponseOptions[string, int, map[string]interface{}, int8]
+
+// TestResponseRecorder tests that the recorder keeps the last envelopes up to its capacity, redacted.
+func TestResponseRecorder(t *testing.T) {
+	recorder := httpresponse.NewResponseRecorder[string, int, map[string]interface{}, int8](3, nil)
+	defer recorder.Close()
+
+	for i := int8(1); i <= 5; i++ {
+		_, err := rpsutil.Build[recordedResponse](
+			httpresponse.HTTPResponse[string, int, map[string]interface{}, int8]().
+				SetTotal(i).
+				SetData(42).
+				SetExtra(map[string]interface{}{"token": "secret"}),
+		)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+
+	recent := recorder.Recent()
+	if len(recent) != 3 {
+		t.Fatalf("Expected 3 envelopes, got %d", len(recent))
+	}
+
+	for i, response := range recent {
+		if response.Total != int8(i+3) {
+			t.Errorf("Expected total %d at %d, got %d", i+3, i, response.Total)
+		}
+		if response.Data != 0 || response.Extra["token"] != httpresponse.RedactedValue {
+			t.Errorf("Expected a redacted envelope, got %+v", response)
+		}
+	}
+
+	recorder.Close()
+	_, err := rpsutil.Build[recordedResponse](
+		httpresponse.HTTPResponse[string, int, map[string]interface{}, int8]().
+			SetTotal(6).
+			SetData(42).
+			SetExtra(map[string]interface{}{"token": "secret"}),
+	)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if recent := recorder.Recent(); recent[len(recent)-1].Total != 5 {
+		t.Errorf("Expected no recording after Close, got total %d", recent[len(recent)-1].Total)
+	}
+}
+
+// TestResponseRecorder_Concurrent tests that concurrent builds are recorded safely within the capacity.
+func TestResponseRecorder_Concurrent(t *testing.T) {
+	recorder := httpresponse.NewResponseRecorder[string, int, map[string]interface{}, int8](8, func(*recordedResponse) {})
+	defer recorder.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int8) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := rpsutil.Build[recordedResponse](
+					httpresponse.HTTPResponse[string, int, map[string]interface{}, int8]().
+						SetTotal(i).
+						SetData(42).
+						SetExtra(map[string]interface{}{"token": "secret"}),
+				)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				_ = recorder.Recent()
+			}
+		}(int8(i))
+	}
+	wg.Wait()
+
+	recent := recorder.Recent()
+	if len(recent) != 8 {
+		t.Fatalf("Expected 8 envelopes, got %d", len(recent))
+	}
+	for _, response := range recent {
+		if response == nil || response.Extra["token"] != "secret" {
+			t.Errorf("Expected an unredacted envelope, got %+v", response)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package httpresponse
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Result is the minimal view of a response: whether it succeeded, its HTTP status and, if it failed,
+// an error describing why. HTTPResponseOptions implements it.
+type Result interface {
+	// OK reports whether the response succeeded.
+	OK() bool
+
+	// StatusCode returns the HTTP status code of the response.
+	StatusCode() int
+
+	// Err returns nil if the response succeeded and a *ResponseError otherwise.
+	Err() error
+}
+
+// ResponseError describes a failed response as an error.
+type ResponseError struct {
+	Status  int    // The HTTP status code of the response.
+	Code    string // The code of the response, formatted as a string; empty if unset.
+	Message string // The message of the response.
+}
+
+// Error returns a description of the failed response, falling back to the status text when there is no message.
+func (responseError *ResponseError) Error() string {
+
+	message := responseError.Message
+	if message == "" {
+		message = http.StatusText(responseError.Status)
+	}
+
+	if responseError.Code == "" {
+		return fmt.Sprintf("httpresponse: status %d: %s", responseError.Status, message)
+	}
+
+	return fmt.Sprintf("httpresponse: status %d, code %s: %s", responseError.Status, responseError.Code, message)
+}
+
+// OK reports whether the response succeeded.
+//
+// Returns:
+//   - bool: The Success field of the response.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) OK() bool {
+	return httpResponseOptions.Success
+}
+
+// Err returns the response as an error if it did not succeed.
+//
+// Returns:
+//   - error: nil if the response succeeded; otherwise, a *ResponseError with its status, code and message.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) Err() error {
+
+	if httpResponseOptions.Success {
+		return nil
+	}
+
+	var code string
+	if !isZero(httpResponseOptions.Code) {
+		code = fmt.Sprint(httpResponseOptions.Code)
+	}
+
+	return &ResponseError{
+		Status:  httpResponseOptions.StatusCode(),
+		Code:    code,
+		Message: httpResponseOptions.Message,
+	}
+}
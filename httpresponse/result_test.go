@@ -0,0 +1,62 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestResult_OK tests the Result view of a successful response.
+func TestResult_OK(t *testing.T) {
+	var result httpresponse.Result = &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Code:    http.StatusOK,
+	}
+
+	if !result.OK() {
+		t.Error("Expected OK to be true")
+	}
+	if result.StatusCode() != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", result.StatusCode())
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// TestResult_Err tests the ResponseError returned by Err for failed responses.
+func TestResult_Err(t *testing.T) {
+	var result httpresponse.Result = &httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]{
+		Success: false,
+		Code:    "USER_NOT_FOUND",
+		Message: "user not found",
+	}
+
+	if result.OK() {
+		t.Error("Expected OK to be false")
+	}
+
+	var responseErr *httpresponse.ResponseError
+	if !errors.As(result.Err(), &responseErr) {
+		t.Fatalf("Expected a *ResponseError, got %T", result.Err())
+	}
+
+	want := httpresponse.ResponseError{Status: http.StatusInternalServerError, Code: "USER_NOT_FOUND", Message: "user not found"}
+	if *responseErr != want {
+		t.Errorf("Expected %+v, got %+v", want, *responseErr)
+	}
+	if got := responseErr.Error(); got != "httpresponse: status 500, code USER_NOT_FOUND: user not found" {
+		t.Errorf("Expected formatted error, got %q", got)
+	}
+}
+
+// TestResponseError_NoMessage tests that ResponseError falls back to the status text.
+func TestResponseError_NoMessage(t *testing.T) {
+	err := (&httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{Code: http.StatusNotFound}).Err()
+
+	if got := err.Error(); got != "httpresponse: status 404, code 404: Not Found" {
+		t.Errorf("Expected status text fallback, got %q", got)
+	}
+}
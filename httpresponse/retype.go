@@ -0,0 +1,62 @@
+package httpresponse
+
+import "reflect"
+
+// RetypeData returns a builder for data of type NewD carrying over every setting of httpResponseBuilder
+// except Data, such as the success status, message, code, total, Extra and headers. The options queued on
+// httpResponseBuilder so far are applied, in order and including its validations, when the returned builder
+// is built; their errors fail that build. Data set by those options is dropped, so options deriving other
+// fields from Data observe the untyped data of the base builder rather than the data set afterwards.
+//
+// Parameters:
+//   - httpResponseBuilder: The base builder with untyped data.
+//
+// Returns:
+//   - *HTTPResponseBuilder: A builder for data of type NewD.
+//
+// Example usage:
+//
+//	base := httpresponse.HTTPResponse[int, any, map[string]any, int]().SetCode(200).SetMessage("ok")
+//	builder := httpresponse.RetypeData[Product](base).SetData(product)
+func RetypeData[
+	NewD any,
+	C int | string,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](httpResponseBuilder *HTTPResponseBuilder[C, any, E, T]) *HTTPResponseBuilder[C, NewD, E, T] {
+
+	// Snapshot the options, so that later changes to the base builder do not leak into the retyped one
+	opts := httpResponseBuilder.List()
+	opts = append(make([]func(*HTTPResponseOptions[C, any, E, T]) error, 0, len(opts)), opts...)
+
+	retyped := HTTPResponse[C, NewD, E, T]()
+	retyped.addOpt(func(args *HTTPResponseOptions[C, NewD, E, T]) error {
+
+		base := new(HTTPResponseOptions[C, any, E, T])
+		for _, opt := range opts {
+			if opt == nil {
+				continue
+			}
+			if err := opt(base); err != nil {
+				return err
+			}
+		}
+
+		copyExceptData(reflect.ValueOf(args).Elem(), reflect.ValueOf(base).Elem())
+
+		return nil
+	})
+
+	return retyped
+}
+
+// copyExceptData copies every field but Data between two instantiations of HTTPResponseOptions that
+// differ only in their data type.
+func copyExceptData(dst, src reflect.Value) {
+
+	for i := 0; i < src.NumField(); i++ {
+		if name := src.Type().Field(i).Name; name != "Data" {
+			dst.FieldByName(name).Set(src.Field(i))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// product is the typed data of the retyping tests.
+type product struct {
+	Name string `json:"name"`
+}
+
+// TestRetypeData tests that retyping keeps the non-data settings and accepts typed data.
+func TestRetypeData(t *testing.T) {
+	base := httpresponse.HTTPResponse[int, any, map[string]any, int]().
+		SetCode(201).
+		SetMessage("created").
+		SetData("untyped").
+		SetTotal(1).
+		SetExtra(map[string]any{"request_id": "abc"})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, product, map[string]any, int]](
+		httpresponse.RetypeData[product](base).SetData(product{Name: "widget"}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Code != 201 || response.Message != "created" || response.Total != 1 {
+		t.Errorf("Expected the base settings, got %+v", response)
+	}
+	if response.Data.Name != "widget" {
+		t.Errorf("Expected data widget, got %+v", response.Data)
+	}
+	if response.Extra["request_id"] != "abc" {
+		t.Errorf("Expected request_id abc, got %v", response.Extra)
+	}
+}
+
+// TestRetypeData_DropsData tests that data set on the base builder is dropped.
+func TestRetypeData_DropsData(t *testing.T) {
+	base := httpresponse.HTTPResponse[int, any, map[string]any, int]().SetData("untyped")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, product, map[string]any, int]](
+		httpresponse.RetypeData[product](base),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Data != (product{}) {
+		t.Errorf("Expected no data, got %+v", response.Data)
+	}
+}
+
+// TestRetypeData_Error tests that errors of the base options fail the retyped build.
+func TestRetypeData_Error(t *testing.T) {
+	base := httpresponse.HTTPResponse[int, any, map[string]any, int]().SetTotal(-1)
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, product, map[string]any, int]](
+		httpresponse.RetypeData[product](base),
+	)
+	if !errors.Is(err, httpresponse.ErrNegativeTotal) {
+		t.Errorf("Expected ErrNegativeTotal, got %v", err)
+	}
+}
@@ -0,0 +1,460 @@
+//go:build go1.23
+
+package httpresponse
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// KeyStreamError is the envelope key reporting an error that interrupted a stream after part of it was sent.
+const KeyStreamError = "stream_error"
+
+// ErrInvalidFieldOrder is returned when encoding a streamed response whose field order lists an unknown
+// field or a field more than once.
+var ErrInvalidFieldOrder = errors.New("httpresponse: invalid field order")
+
+// seqBufferSize is the size of the buffer in front of the destination of a streamed list response.
+const seqBufferSize = 32 << 10
+
+// SeqResponse is a list response whose Data elements are produced by an iterator and streamed as they are
+// encoded. Elements are encoded like those of a slice Data, including by the marshalers registered with
+// RegisterDataMarshaler. Create it with SetDataSeq or SetDataSeq2 from a list-oriented builder, whose Data is
+// a slice.
+type SeqResponse[
+	C int | string,
+	V any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+] struct {
+	builder     *HTTPResponseBuilder[C, []V, E, T]
+	seq         iter.Seq2[V, error]
+	order       []Field
+	maxBuffered int
+}
+
+// SetDataSeq returns a response streaming the elements of seq as the Data array of the envelope built by
+// builder. Any Data set on builder is ignored.
+//
+// Parameters:
+//   - httpResponseBuilder: The builder of the envelope.
+//   - seq: The elements of Data; it is iterated once per encoding.
+//
+// Returns:
+//   - *SeqResponse: The streamed response.
+func SetDataSeq[
+	C int | string,
+	V any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](httpResponseBuilder *HTTPResponseBuilder[C, []V, E, T], seq iter.Seq[V]) *SeqResponse[C, V, E, T] {
+	return SetDataSeq2(httpResponseBuilder, func(yield func(V, error) bool) {
+		for v := range seq {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	})
+}
+
+// SetDataSeq2 is like SetDataSeq for iterators that can fail: a non-nil error yielded by seq stops the stream.
+// If nothing has been sent yet, an error envelope built with FromError is sent instead; otherwise the array is
+// terminated and the public message of the error is reported under the "stream_error" envelope key.
+//
+// Parameters:
+//   - httpResponseBuilder: The builder of the envelope.
+//   - seq: The elements of Data, each paired with an error.
+//
+// Returns:
+//   - *SeqResponse: The streamed response.
+func SetDataSeq2[
+	C int | string,
+	V any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](httpResponseBuilder *HTTPResponseBuilder[C, []V, E, T], seq iter.Seq2[V, error]) *SeqResponse[C, V, E, T] {
+	return &SeqResponse[C, V, E, T]{builder: httpResponseBuilder, seq: seq}
+}
+
+// FieldOrder sets the order in which the envelope fields are streamed. FieldExtra stands for all Extra
+// fields, which keep their own order (see SetExtraKeyOrder). Fields not listed follow in the default order,
+// which is success, message, code, total, the Extra fields and, last, data, so that clients see the metadata
+// of the envelope without waiting for the array; list FieldData first for parsers wanting data first.
+// Encoding fails with ErrInvalidFieldOrder if order lists an unknown field or a field more than once.
+//
+// Parameters:
+//   - order: The order of the envelope fields.
+//
+// Returns:
+//   - *SeqResponse: The same response, for chaining.
+func (seqResponse *SeqResponse[C, V, E, T]) FieldOrder(order []Field) *SeqResponse[C, V, E, T] {
+
+	seqResponse.order = order
+
+	return seqResponse
+}
+
+// ForceBuffered makes WriteJSON hold the stream until it ends as long as it fits in maxBytes, so that small
+// responses are sent at once with a Content-Length header, as some proxies require. A stream growing beyond
+// maxBytes is sent as it is encoded from then on, without Content-Length. Holding the stream also lets an
+// error yielded before the limit is reached replace the whole response with an error envelope.
+//
+// Parameters:
+//   - maxBytes: The largest stream sent with Content-Length; zero or negative streams from the start.
+//
+// Returns:
+//   - *SeqResponse: The same response, for chaining.
+func (seqResponse *SeqResponse[C, V, E, T]) ForceBuffered(maxBytes int) *SeqResponse[C, V, E, T] {
+
+	seqResponse.maxBuffered = maxBytes
+
+	return seqResponse
+}
+
+// fieldOrder returns the complete order of the envelope fields: the configured order followed by the
+// unlisted fields in the default order.
+func (seqResponse *SeqResponse[C, V, E, T]) fieldOrder() ([]Field, error) {
+
+	listed := make(map[Field]bool, len(defaultStreamOrder))
+	for _, field := range seqResponse.order {
+
+		known := false
+		for _, f := range defaultStreamOrder {
+			known = known || f == field
+		}
+
+		if !known || listed[field] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidFieldOrder, field)
+		}
+		listed[field] = true
+	}
+
+	order := append(make([]Field, 0, len(defaultStreamOrder)), seqResponse.order...)
+	for _, field := range defaultStreamOrder {
+		if !listed[field] {
+			order = append(order, field)
+		}
+	}
+
+	return order, nil
+}
+
+// EncodeJSON streams the JSON encoding of the response to w, with the envelope fields in the order set by
+// FieldOrder. Output is buffered, so short responses are written to w at once.
+//
+// Parameters:
+//   - w: The destination of the encoding.
+//
+// Returns:
+//   - error: An error if building the envelope, iterating, encoding an element or writing fails.
+func (seqResponse *SeqResponse[C, V, E, T]) EncodeJSON(w io.Writer) error {
+
+	response, err := rpsutil.Build[HTTPResponseOptions[C, []V, E, T]](seqResponse.builder)
+	if err != nil {
+		return err
+	}
+
+	return seqResponse.stream(&seqWriter{w: w}, response)
+}
+
+// WriteJSON streams the response to w like WriteJSON does for other responses, but without Content-Length
+// unless the stream is held with ForceBuffered. The status and headers are written when the first bytes are
+// sent, so an error yielded before then still produces an error envelope with its own status.
+//
+// Parameters:
+//   - w: The destination http.ResponseWriter.
+//
+// Returns:
+//   - error: An error if building the envelope, iterating, encoding an element or writing fails.
+func (seqResponse *SeqResponse[C, V, E, T]) WriteJSON(w http.ResponseWriter) error {
+
+	response, err := rpsutil.Build[HTTPResponseOptions[C, []V, E, T]](seqResponse.builder)
+	if err != nil {
+		return err
+	}
+
+	markEnvelope(w, response)
+
+	writer := &seqWriter{w: w, rw: w, header: response.Header(), status: response.StatusCode()}
+	if seqResponse.maxBuffered > 0 {
+		writer.holding, writer.maxHeld = true, seqResponse.maxBuffered
+	}
+
+	if err := seqResponse.stream(writer, response); err != nil {
+		return err
+	}
+
+	recordWrite()
+
+	return nil
+}
+
+// stream encodes response with the elements of the iterator as Data into writer.
+func (seqResponse *SeqResponse[C, V, E, T]) stream(writer *seqWriter, response *HTTPResponseOptions[C, []V, E, T]) error {
+
+	order, err := seqResponse.fieldOrder()
+	if err != nil {
+		return err
+	}
+
+	prefix, rest, suffix, err := seqEnvelope(response, order)
+	if err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriterSize(writer, seqBufferSize)
+	buf.Write(prefix)
+
+	first := true
+	for v, iterErr := range seqResponse.seq {
+
+		if iterErr != nil {
+			return seqResponse.interrupt(writer, buf, response, rest, iterErr)
+		}
+
+		element, err := encodeData(v)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		if _, err := buf.Write(element); err != nil {
+			return err
+		}
+	}
+
+	buf.Write(suffix)
+
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	return writer.finish()
+}
+
+// interrupt ends a stream stopped by err: with an error envelope if nothing was sent yet, or else by
+// terminating the array, writing the fields following it (rest) and reporting the public message of err.
+// With BareData, a started stream is only terminated, as there is no envelope to report err in. It returns err.
+func (seqResponse *SeqResponse[C, V, E, T]) interrupt(writer *seqWriter, buf *bufio.Writer, response *HTTPResponseOptions[C, []V, E, T], rest []byte, err error) error {
+
+	if !writer.started {
+		response.deps().logf("httpresponse: stream interrupted [%s]: %v", response.Preview(logPreviewBytes), err)
+
+		failed, buildErr := rpsutil.Build[HTTPResponseOptions[C, []V, E, T]](FromError[C, []V, E, T](err))
+		if buildErr != nil {
+			return buildErr
+		}
+
+		body, marshalErr := failed.MarshalJSON()
+		if marshalErr != nil {
+			return marshalErr
+		}
+
+		// The error envelope replaces the response, including its headers, status and held bytes
+		writer.header, writer.status = failed.Header(), failed.StatusCode()
+		writer.held = writer.held[:0]
+
+		buf.Reset(writer)
+		buf.Write(body)
+		buf.Flush()
+		writer.finish()
+
+		return err
+	}
+
+	response.deps().logf("httpresponse: stream interrupted [%s]: %v", response.Preview(logPreviewBytes), err)
+
+	// A bare array has no envelope to carry the error, so it is only terminated, leaving the caller to act on err
+	if response.BareData {
+		buf.WriteByte(']')
+		buf.Flush()
+		writer.finish()
+
+		return err
+	}
+
+	message, _ := json.Marshal(translateError(err).message)
+
+	buf.WriteByte(']')
+	buf.Write(rest)
+	buf.WriteString(`,"` + KeyStreamError + `":`)
+	buf.Write(message)
+	buf.WriteByte('}')
+	buf.Flush()
+	writer.finish()
+
+	return err
+}
+
+// seqEnvelope returns the encoding of response around its Data array, with the envelope fields in order:
+// the bytes up to and including the opening bracket, the fields following the array, each prefixed with a
+// comma, and the bytes from the closing bracket on.
+func seqEnvelope[
+	C int | string,
+	V any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](response *HTTPResponseOptions[C, []V, E, T], order []Field) ([]byte, []byte, []byte, error) {
+
+	if response.BareData {
+		if response.bareDataConflict() {
+			return nil, nil, nil, ErrBareDataConflict
+		}
+		return []byte("["), nil, []byte("]"), nil
+	}
+
+	envelope := *response
+	envelope.Data = nil
+	envelope.NullAs = nil
+
+	body, err := envelope.MarshalJSON()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, nil, nil, err
+	}
+
+	keys := response.Keys()
+	coreKeys := keys.byField()
+
+	var prefix, rest []byte
+	prefix = append(prefix, '{')
+	afterData := false
+
+	// Encode each present field after a comma into rest, moving rest into the prefix until Data is reached
+	for _, field := range order {
+
+		if field == FieldData {
+			afterData = true
+			continue
+		}
+
+		var names []string
+		if field == FieldExtra {
+			for _, k := range response.extraKeys() {
+				names = append(names, response.ExtraKeyCase.apply(k))
+			}
+			names = dedupeKeys(names)
+		} else {
+			names = []string{coreKeys[field]}
+		}
+
+		for _, name := range names {
+			value, ok := fields[name]
+			if !ok {
+				continue
+			}
+			delete(fields, name)
+
+			key, _ := json.Marshal(name)
+			rest = append(append(append(append(rest, ','), key...), ':'), value...)
+		}
+
+		if !afterData {
+			prefix = append(prefix, rest...)
+			rest = rest[:0]
+		}
+	}
+
+	// The first field written to the prefix needs no comma
+	if len(prefix) > 1 {
+		prefix = append(prefix[:1], prefix[2:]...)
+		prefix = append(prefix, ',')
+	}
+	dataKey, _ := json.Marshal(keys.Data)
+	prefix = append(append(prefix, dataKey...), ":["...)
+
+	suffix := append(append([]byte("]"), rest...), '}')
+
+	return prefix, rest, suffix, nil
+}
+
+// seqWriter forwards writes to w. When rw is set, header and status are written to it before the first write.
+// While holding, writes are held until they exceed maxHeld bytes or finish is called.
+type seqWriter struct {
+	w       io.Writer
+	rw      http.ResponseWriter
+	header  http.Header
+	status  int
+	started bool
+
+	holding bool
+	maxHeld int
+	held    []byte
+}
+
+// Write implements io.Writer.
+func (seqWriter *seqWriter) Write(p []byte) (int, error) {
+
+	if seqWriter.holding {
+		if len(seqWriter.held)+len(p) <= seqWriter.maxHeld {
+			seqWriter.held = append(seqWriter.held, p...)
+			return len(p), nil
+		}
+
+		// The stream outgrew the limit: send what was held and stream the rest
+		seqWriter.holding = false
+		if len(seqWriter.held) > 0 {
+			if _, err := seqWriter.send(seqWriter.held, -1); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return seqWriter.send(p, -1)
+}
+
+// finish sends the held bytes, if any, with their length as Content-Length.
+func (seqWriter *seqWriter) finish() error {
+
+	if !seqWriter.holding {
+		return nil
+	}
+	seqWriter.holding = false
+
+	_, err := seqWriter.send(seqWriter.held, len(seqWriter.held))
+
+	return err
+}
+
+// send writes p to w, writing the header and status first if nothing was sent yet. The Content-Length header
+// is set to contentLength, or removed if it is negative.
+func (seqWriter *seqWriter) send(p []byte, contentLength int) (int, error) {
+
+	if !seqWriter.started {
+		seqWriter.started = true
+
+		if seqWriter.rw != nil {
+			for k, values := range seqWriter.header {
+				for _, v := range values {
+					seqWriter.rw.Header().Add(k, v)
+				}
+			}
+			normalizeVary(seqWriter.rw.Header())
+			seqWriter.rw.Header().Set("Content-Type", contentTypeJSON)
+			if contentLength >= 0 {
+				seqWriter.rw.Header().Set("Content-Length", strconv.Itoa(contentLength))
+			} else {
+				seqWriter.rw.Header().Del("Content-Length")
+			}
+			seqWriter.rw.WriteHeader(seqWriter.status)
+		}
+	}
+
+	return seqWriter.w.Write(p)
+}
@@ -0,0 +1,340 @@
+//go:build go1.23
+
+package httpresponse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// countSeq yields the integers [0, n), failing with err after fail elements if err is non-nil.
+func countSeq(n, fail int, err error) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := 0; i < n; i++ {
+			if err != nil && i == fail {
+				yield(0, err)
+				return
+			}
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+}
+
+// listBuilder returns a list-oriented builder with a message and total.
+func listBuilder() *httpresponse.HTTPResponseBuilder[int, []int, map[string]interface{}, int] {
+	return httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().SetMessage("ok").SetTotal(3)
+}
+
+// TestSetDataSeq tests that a streamed list decodes like the equivalent slice response.
+func TestSetDataSeq(t *testing.T) {
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	var streamed bytes.Buffer
+	if err := httpresponse.SetDataSeq(listBuilder(), seq).EncodeJSON(&streamed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](listBuilder().SetData([]int{0, 1, 2}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	materialized, _ := response.MarshalJSON()
+
+	var got, want map[string]any
+	if err := json.Unmarshal(streamed.Bytes(), &got); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, streamed.String())
+	}
+	json.Unmarshal(materialized, &want)
+
+	if !jsonEqual(got, want) {
+		t.Errorf("Expected %s, got %s", materialized, streamed.String())
+	}
+}
+
+// TestSetDataSeq_Empty tests that an empty iterator yields an empty array.
+func TestSetDataSeq_Empty(t *testing.T) {
+	var out bytes.Buffer
+	builder := httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]()
+	if err := httpresponse.SetDataSeq2(builder, countSeq(0, 0, nil)).EncodeJSON(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := `{"success":true,"message":"","data":[]}`; out.String() != want {
+		t.Errorf("Expected %s, got %s", want, out.String())
+	}
+}
+
+// TestSetDataSeq_DataMarshaler tests that streamed elements are encoded with the registered data marshalers,
+// like the elements of a slice Data.
+func TestSetDataSeq_DataMarshaler(t *testing.T) {
+	seq := func(yield func(money) bool) {
+		_ = yield(money{Cents: 1250}) && yield(money{Cents: 7})
+	}
+
+	var out bytes.Buffer
+	builder := httpresponse.HTTPResponse[int, []money, map[string]interface{}, int]()
+	if err := httpresponse.SetDataSeq(builder, seq).EncodeJSON(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := `{"success":true,"message":"","data":["12.50","0.07"]}`; out.String() != want {
+		t.Errorf("Expected %s, got %s", want, out.String())
+	}
+}
+
+// TestSetDataSeq2_EarlyError tests that an error before anything was sent yields an error envelope.
+func TestSetDataSeq2_EarlyError(t *testing.T) {
+	errBroken := errors.New("cursor broken")
+
+	recorder := httptest.NewRecorder()
+	err := httpresponse.SetDataSeq2(listBuilder(), countSeq(10, 2, errBroken)).WriteJSON(recorder)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the iterator error, got %v", err)
+	}
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", recorder.Code)
+	}
+
+	var envelope map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Expected a single JSON envelope, got %v: %s", err, recorder.Body.String())
+	}
+	if envelope["success"] != false || envelope["message"] != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected an error envelope, got %v", envelope)
+	}
+	if _, ok := envelope["data"]; ok {
+		t.Errorf("Expected no partial data, got %v", envelope)
+	}
+}
+
+// TestSetDataSeq2_LateError tests that an error after data was sent terminates the array with a marker.
+func TestSetDataSeq2_LateError(t *testing.T) {
+	errBroken := errors.New("cursor broken")
+
+	// Enough elements to exceed the stream buffer before the error
+	recorder := httptest.NewRecorder()
+	err := httpresponse.SetDataSeq2(listBuilder(), countSeq(100000, 50000, errBroken)).WriteJSON(recorder)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the iterator error, got %v", err)
+	}
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected the status sent with the first bytes, got %d", recorder.Code)
+	}
+
+	var envelope struct {
+		Data        []int  `json:"data"`
+		StreamError string `json:"stream_error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Expected a terminated JSON envelope, got %v", err)
+	}
+	if len(envelope.Data) != 50000 || envelope.StreamError != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected 50000 elements and the stream error, got %d and %q", len(envelope.Data), envelope.StreamError)
+	}
+}
+
+// TestSetDataSeq2_LateError_BareData tests that an error after a bare array was sent only terminates the
+// array, keeping the body valid JSON.
+func TestSetDataSeq2_LateError_BareData(t *testing.T) {
+	errBroken := errors.New("cursor broken")
+
+	recorder := httptest.NewRecorder()
+	builder := httpresponse.HTTPResponse[int, []int, map[string]interface{}, int]().BareData(true)
+	err := httpresponse.SetDataSeq2(builder, countSeq(100000, 50000, errBroken)).WriteJSON(recorder)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the iterator error, got %v", err)
+	}
+
+	var data []int
+	if err := json.Unmarshal(recorder.Body.Bytes(), &data); err != nil {
+		t.Fatalf("Expected a terminated JSON array, got %v", err)
+	}
+	if len(data) != 50000 {
+		t.Errorf("Expected 50000 elements, got %d", len(data))
+	}
+}
+
+// TestSeqResponse_DefaultFieldOrder tests that streams emit the metadata first, then Extra, then data.
+func TestSeqResponse_DefaultFieldOrder(t *testing.T) {
+	var out bytes.Buffer
+	builder := listBuilder().SetCode(200).SetExtra(map[string]interface{}{"cursor": "abc"})
+	if err := httpresponse.SetDataSeq2(builder, countSeq(2, 0, nil)).EncodeJSON(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"success":true,"message":"ok","code":200,"total":3,"cursor":"abc","data":[0,1]}`
+	if out.String() != want {
+		t.Errorf("Expected %s, got %s", want, out.String())
+	}
+}
+
+// TestSeqResponse_FieldOrder tests that a custom order is followed, with unlisted fields in the default order.
+func TestSeqResponse_FieldOrder(t *testing.T) {
+	var out bytes.Buffer
+	builder := listBuilder().SetExtra(map[string]interface{}{"cursor": "abc"})
+	response := httpresponse.SetDataSeq2(builder, countSeq(2, 0, nil)).
+		FieldOrder([]httpresponse.Field{httpresponse.FieldTotal, httpresponse.FieldData, httpresponse.FieldExtra})
+	if err := response.EncodeJSON(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"total":3,"data":[0,1],"cursor":"abc","success":true,"message":"ok"}`
+	if out.String() != want {
+		t.Errorf("Expected %s, got %s", want, out.String())
+	}
+}
+
+// TestSeqResponse_FieldOrder_DataFirst tests that a late error keeps the fields following data.
+func TestSeqResponse_FieldOrder_DataFirst(t *testing.T) {
+	errBroken := errors.New("cursor broken")
+
+	var out bytes.Buffer
+	response := httpresponse.SetDataSeq2(listBuilder(), countSeq(100000, 50000, errBroken)).
+		FieldOrder([]httpresponse.Field{httpresponse.FieldData})
+	if err := response.EncodeJSON(&out); !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the iterator error, got %v", err)
+	}
+
+	if !bytes.HasPrefix(out.Bytes(), []byte(`{"data":[0,1,`)) {
+		t.Errorf("Expected data first, got %.40s", out.String())
+	}
+
+	var envelope map[string]any
+	if err := json.Unmarshal(out.Bytes(), &envelope); err != nil {
+		t.Fatalf("Expected a terminated JSON envelope, got %v", err)
+	}
+	if envelope["message"] != "ok" || envelope["stream_error"] != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected the message and the stream error, got %v and %v", envelope["message"], envelope["stream_error"])
+	}
+}
+
+// TestSeqResponse_FieldOrder_Invalid tests that duplicate and unknown fields are rejected.
+func TestSeqResponse_FieldOrder_Invalid(t *testing.T) {
+	for _, order := range [][]httpresponse.Field{
+		{httpresponse.FieldData, httpresponse.FieldData},
+		{"unknown"},
+	} {
+		var out bytes.Buffer
+		err := httpresponse.SetDataSeq2(listBuilder(), countSeq(1, 0, nil)).FieldOrder(order).EncodeJSON(&out)
+		if !errors.Is(err, httpresponse.ErrInvalidFieldOrder) {
+			t.Errorf("Expected ErrInvalidFieldOrder for %v, got %v", order, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("Expected nothing written for %v, got %s", order, out.String())
+		}
+	}
+}
+
+// TestSeqResponse_ContentLength tests that streams are sent without Content-Length unless held within the limit.
+func TestSeqResponse_ContentLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBuffered int
+		elements    int
+		sized       bool
+	}{
+		{"streamed", 0, 10, false},
+		{"held", 1 << 10, 10, true},
+		{"outgrown", 1 << 10, 1000, false},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		recorder.Header().Set("Content-Length", "1")
+
+		response := httpresponse.SetDataSeq2(listBuilder(), countSeq(tt.elements, 0, nil)).ForceBuffered(tt.maxBuffered)
+		if err := response.WriteJSON(recorder); err != nil {
+			t.Fatalf("%s: Expected no error, got %v", tt.name, err)
+		}
+
+		got := recorder.Result().Header.Get("Content-Length")
+		if tt.sized && got != strconv.Itoa(recorder.Body.Len()) {
+			t.Errorf("%s: Expected Content-Length %d, got %q", tt.name, recorder.Body.Len(), got)
+		}
+		if !tt.sized && got != "" {
+			t.Errorf("%s: Expected no Content-Length, got %q", tt.name, got)
+		}
+		if !json.Valid(recorder.Body.Bytes()) {
+			t.Errorf("%s: Expected a valid envelope, got %s", tt.name, recorder.Body.String())
+		}
+	}
+}
+
+// TestSeqResponse_ForceBuffered_Error tests that an error within the held bytes replaces the whole response.
+func TestSeqResponse_ForceBuffered_Error(t *testing.T) {
+	errBroken := errors.New("cursor broken")
+
+	recorder := httptest.NewRecorder()
+	err := httpresponse.SetDataSeq2(listBuilder(), countSeq(100, 50, errBroken)).ForceBuffered(1 << 10).WriteJSON(recorder)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected the iterator error, got %v", err)
+	}
+
+	if recorder.Code != http.StatusInternalServerError || contains(recorder.Body.String(), `"data":[`) {
+		t.Errorf("Expected a 500 error envelope without data, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Header().Get("Content-Length"); got != strconv.Itoa(recorder.Body.Len()) {
+		t.Errorf("Expected Content-Length %d, got %q", recorder.Body.Len(), got)
+	}
+}
+
+// jsonEqual reports whether two decoded JSON values are equal.
+func jsonEqual(a, b any) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return bytes.Equal(ab, bb)
+}
+
+// BenchmarkSetDataSeq streams 1M elements; compare its B/op with BenchmarkSetDataSlice.
+func BenchmarkSetDataSeq(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := httpresponse.SetDataSeq2(listBuilder(), countSeq(1_000_000, 0, nil)).EncodeJSON(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSetDataSlice materializes and marshals a 1M-element slice.
+func BenchmarkSetDataSlice(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		data := make([]int, 1_000_000)
+		for j := range data {
+			data[j] = j
+		}
+
+		response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []int, map[string]interface{}, int]](listBuilder().SetData(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		body, err := response.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+		io.Discard.Write(body)
+	}
+}
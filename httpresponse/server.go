@@ -0,0 +1,40 @@
+package httpresponse
+
+// KeyServer is the Extra key under which SetServerInfo emits the server build metadata.
+const KeyServer = "server"
+
+// ServerInfo describes the server build that produced a response.
+type ServerInfo struct {
+	Name    string `json:"name,omitempty"`    // The name of the server or service.
+	Version string `json:"version,omitempty"` // The version of the build.
+	Commit  string `json:"commit,omitempty"`  // The source revision of the build.
+}
+
+// SetServerInfo emits the build metadata of the server under the "server" Extra key, as an object holding
+// the non-empty values among "name", "version" and "commit", only while debug mode is enabled (see SetDebug).
+// Debug mode is checked when the options are applied. The values typically come from variables injected
+// at build time, such as with -ldflags "-X main.commit=...".
+//
+// Parameters:
+//   - name: The name of the server or service.
+//   - version: The version of the build.
+//   - commit: The source revision of the build.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetServerInfo(name, version, commit string) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if !Debug() {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyServer] = ServerInfo{Name: name, Version: version, Commit: commit}
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
@@ -0,0 +1,55 @@
+package httpresponse_test
+
+import (
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetServerInfo tests that the server object appears in debug mode, without empty values.
+func TestSetServerInfo(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetCode(200).
+			SetServerInfo("orders", "1.4.2", ""),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"code":200,"message":"","server":{"name":"orders","version":"1.4.2"},"success":true}`
+	if string(jsonData) != want {
+		t.Errorf("Expected %s, got %s", want, jsonData)
+	}
+}
+
+// TestSetServerInfo_NotDebug tests that the server object is omitted outside debug mode.
+func TestSetServerInfo_NotDebug(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetCode(200).
+			SetServerInfo("orders", "1.4.2", ""),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{"code":200,"message":"","success":true}`
+	if string(jsonData) != want {
+		t.Errorf("Expected %s, got %s", want, jsonData)
+	}
+}
@@ -0,0 +1,81 @@
+package httpresponse
+
+import (
+	"fmt"
+	"sync"
+)
+
+// sinkTransform describes a transform applied to selected Extra keys when marshaling for a sink.
+type sinkTransform struct {
+	keys []string
+	fn   func(any) (any, error)
+}
+
+var (
+	sinkTransformsMu sync.RWMutex
+	sinkTransforms   = map[string][]sinkTransform{}
+)
+
+// RegisterSinkTransform registers a transform applied to the given Extra keys whenever a response is
+// marshaled for the named sink with MarshalJSONForSink. Transforms for a sink run in registration order.
+// The HTTP writers and MarshalJSON never apply sink transforms, so clients always receive plain values.
+//
+// Parameters:
+//   - sink: The name of the destination (e.g. "audit", "kafka", "sql").
+//   - keys: The Extra keys whose values are transformed; keys absent from Extra are skipped.
+//   - fn: The transform, such as encrypting a PII token; an error aborts marshaling.
+func RegisterSinkTransform(sink string, keys []string, fn func(any) (any, error)) {
+
+	sinkTransformsMu.Lock()
+	defer sinkTransformsMu.Unlock()
+
+	sinkTransforms[sink] = append(sinkTransforms[sink], sinkTransform{keys: keys, fn: fn})
+}
+
+// MarshalJSONForSink encodes the response like MarshalJSON after applying the transforms registered
+// for the named sink to a copy of the Extra map. The response itself is left untouched.
+//
+// Parameters:
+//   - sink: The name of the destination the encoding is intended for.
+//
+// Returns:
+//   - []byte: The JSON encoding with sink transforms applied.
+//   - error: An error if a transform or the marshaling fails.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) MarshalJSONForSink(sink string) ([]byte, error) {
+
+	sinkTransformsMu.RLock()
+	transforms := sinkTransforms[sink]
+	sinkTransformsMu.RUnlock()
+
+	if len(transforms) == 0 || len(httpResponseOptions.Extra) == 0 {
+		return httpResponseOptions.MarshalJSON()
+	}
+
+	// Transform a copy of Extra so the HTTP representation of the same response stays unchanged
+	extra := make(E, len(httpResponseOptions.Extra))
+	for k, v := range httpResponseOptions.Extra {
+		extra[k] = v
+	}
+
+	for _, transform := range transforms {
+		for _, key := range transform.keys {
+
+			v, ok := extra[key]
+			if !ok {
+				continue
+			}
+
+			tv, err := transform.fn(v)
+			if err != nil {
+				return nil, fmt.Errorf("httpresponse: sink %q transform of %q: %w", sink, key, err)
+			}
+
+			extra[key] = tv
+		}
+	}
+
+	sinkResponseOptions := *httpResponseOptions
+	sinkResponseOptions.Extra = extra
+
+	return sinkResponseOptions.MarshalJSON()
+}
@@ -0,0 +1,82 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+func init() {
+	// Sink transforms are registered once per process, like an application would at startup
+	httpresponse.RegisterSinkTransform("audit-test", []string{"pii_token", "absent"}, reverse)
+	httpresponse.RegisterSinkTransform("kafka-test", []string{"pii_token"}, reverse)
+}
+
+// reverse is a reversible fake transform standing in for encryption.
+func reverse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New("expected string")
+	}
+
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r), nil
+}
+
+// TestHTTPResponseOptions_MarshalJSONForSink tests that sink transforms apply only to the sink encoding.
+func TestHTTPResponseOptions_MarshalJSONForSink(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Message: "ok",
+		Extra: map[string]interface{}{
+			"pii_token": "secret",
+			"trace_id":  "abc",
+		},
+	}
+
+	httpData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !contains(string(httpData), `"pii_token":"secret"`) {
+		t.Errorf("Expected HTTP output to keep the plain token, got %v", string(httpData))
+	}
+
+	auditData, err := response.MarshalJSONForSink("audit-test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var audit map[string]interface{}
+	if err := json.Unmarshal(auditData, &audit); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if audit["pii_token"] != "terces" {
+		t.Errorf("Expected audit output to contain the transformed token, got %v", audit["pii_token"])
+	}
+	if audit["trace_id"] != "abc" {
+		t.Errorf("Expected audit output to keep untransformed keys, got %v", audit["trace_id"])
+	}
+
+	// The transform is reversible and the response itself was not modified
+	if plain, _ := reverse(audit["pii_token"]); plain != response.Extra["pii_token"] {
+		t.Errorf("Expected reversed token to equal %v, got %v", response.Extra["pii_token"], plain)
+	}
+}
+
+// TestHTTPResponseOptions_MarshalJSONForSink_Error tests that a failing transform aborts sink marshaling.
+func TestHTTPResponseOptions_MarshalJSONForSink_Error(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Extra: map[string]interface{}{"pii_token": 42},
+	}
+
+	if _, err := response.MarshalJSONForSink("kafka-test"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
@@ -0,0 +1,30 @@
+package httpresponse
+
+// countingWriter is an io.Writer that discards its input, counting the bytes written.
+type countingWriter struct {
+	n int
+}
+
+// Write counts and discards p.
+func (countingWriter *countingWriter) Write(p []byte) (int, error) {
+
+	countingWriter.n += len(p)
+
+	return len(p), nil
+}
+
+// EstimateSize returns the byte length of the encoding produced by MarshalJSON. The encoding is
+// streamed into a counting writer, so the encoded body is never retained as a whole.
+//
+// Returns:
+//   - int: The length of the encoded response in bytes.
+//   - error: An error if encoding fails.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) EstimateSize() (int, error) {
+
+	var w countingWriter
+	if err := httpResponseOptions.encodeJSON(&w); err != nil {
+		return 0, err
+	}
+
+	return w.n, nil
+}
@@ -0,0 +1,42 @@
+package httpresponse_test
+
+import (
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestEstimateSize tests that the estimated size matches the length of the MarshalJSON output.
+func TestEstimateSize(t *testing.T) {
+	responses := map[string]*httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{
+		"envelope": {Success: true, Message: "ok", Code: 200, Data: []string{"a", "<b>"}, Total: 2},
+		"extra":    {Success: true, Data: "x", Extra: map[string]any{"z": 1, "a": "&"}},
+		"ordered":  {Extra: map[string]any{"z": 1, "a": 2}, ExtraKeyOrder: []string{"z"}},
+		"bare":     {BareData: true, Data: map[string]int{"n": 1}},
+	}
+
+	for name, response := range responses {
+		body, err := response.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+
+		size, err := response.EstimateSize()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+
+		if size != len(body) {
+			t.Errorf("%s: Expected size %d, got %d", name, len(body), size)
+		}
+	}
+}
+
+// TestEstimateSize_Error tests that encoding errors are reported.
+func TestEstimateSize_Error(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, any, map[string]any, int]{Data: make(chan int)}
+
+	if _, err := response.EstimateSize(); err == nil {
+		t.Errorf("Expected an error, got nil")
+	}
+}
@@ -0,0 +1,123 @@
+package httpresponse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sseHeartbeat is a comment-only SSE line; clients ignore it, but it keeps intermediaries from timing out.
+const sseHeartbeat = ": heartbeat\n\n"
+
+// Heartbeat writes a comment-only SSE line to w every interval until ctx is cancelled.
+// If w can be flushed, it is flushed after each heartbeat (see ProbeStream).
+//
+// Heartbeat must not run concurrently with other writes to w; StreamSSE interleaves heartbeats
+// with events itself and should be preferred when streaming data.
+//
+// Parameters:
+//   - ctx: Controls how long heartbeats are written.
+//   - w: The destination of the heartbeats, typically an http.ResponseWriter.
+//   - interval: The time between heartbeats; it must be positive.
+//
+// Returns:
+//   - error: The context error once ctx is done, or the first write error.
+func Heartbeat(ctx context.Context, w io.Writer, interval time.Duration) error {
+
+	stream := newStreamWriter(w, false)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := writeSSE(stream, sseHeartbeat); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamSSE streams each item received from events to w as an SSE "data" event encoded as JSON,
+// until events is closed or ctx is cancelled. When heartbeat is positive, a comment-only line is
+// written whenever the stream has been idle for that long, keeping the connection alive. If w cannot be
+// flushed, the stream is sent when it ends, or once it exceeds StreamBufferBytes, without heartbeats (see
+// ProbeStream).
+//
+// Parameters:
+//   - ctx: Cancels the stream.
+//   - w: The destination http.ResponseWriter; it is flushed after each write if it can be flushed.
+//   - events: The items to stream; closing the channel ends the stream.
+//   - heartbeat: The idle interval after which a heartbeat is written; zero or negative disables heartbeats.
+//
+// Returns:
+//   - error: nil when events is closed, the context error on cancellation, or the first encoding or write error.
+func StreamSSE[D any](ctx context.Context, w http.ResponseWriter, events <-chan D, heartbeat time.Duration) (err error) {
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+
+	stream := newStreamWriter(w, true)
+	defer func() {
+		if closeErr := stream.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	// A nil channel never fires, which disables heartbeats
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if heartbeat > 0 && !stream.buffered() {
+		ticker = time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+			if err := writeSSE(stream, sseHeartbeat); err != nil {
+				return err
+			}
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			if err := writeSSE(stream, "data: "+string(data)+"\n\n"); err != nil {
+				return err
+			}
+
+			// The stream is no longer idle, so the next heartbeat is due a full interval from now
+			if ticker != nil {
+				ticker.Reset(heartbeat)
+			}
+		}
+	}
+}
+
+// writeSSE writes s to stream and flushes it.
+func writeSSE(stream *streamWriter, s string) error {
+
+	if _, err := io.WriteString(stream, s); err != nil {
+		return err
+	}
+
+	stream.Flush()
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package httpresponse_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use, so the test can inspect it while Heartbeat writes.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// TestHeartbeat_Interval tests that heartbeats are emitted at the configured interval until the context is cancelled.
+func TestHeartbeat_Interval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 105*time.Millisecond)
+	defer cancel()
+
+	var buf lockedBuffer
+	if err := httpresponse.Heartbeat(ctx, &buf, 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+
+	// Five ticks fit in the window; allow for scheduling jitter on slow machines
+	count := strings.Count(buf.String(), ": heartbeat\n\n")
+	if count < 3 || count > 5 {
+		t.Errorf("Expected about 5 heartbeats, got %d", count)
+	}
+	if strings.Contains(buf.String(), "data:") {
+		t.Errorf("Expected only comment lines, got %v", buf.String())
+	}
+}
+
+// TestStreamSSE_EventsAndHeartbeats tests that StreamSSE writes events and heartbeats while idle.
+func TestStreamSSE_EventsAndHeartbeats(t *testing.T) {
+	events := make(chan map[string]int)
+	rec := httptest.NewRecorder()
+
+	go func() {
+		events <- map[string]int{"n": 1}
+		time.Sleep(60 * time.Millisecond)
+		events <- map[string]int{"n": 2}
+		close(events)
+	}()
+
+	if err := httpresponse.StreamSSE(context.Background(), rec, events, 10*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body := rec.Body.String()
+	if rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected Content-Type text/event-stream, got %v", rec.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(body, "data: {\"n\":1}\n\n") || !strings.HasSuffix(body, "data: {\"n\":2}\n\n") {
+		t.Errorf("Expected both events in order, got %q", body)
+	}
+	if strings.Count(body, ": heartbeat\n\n") < 2 {
+		t.Errorf("Expected heartbeats while idle, got %q", body)
+	}
+}
+
+// TestStreamSSE_FrequentEvents tests that heartbeats are suppressed while events keep the stream busy.
+func TestStreamSSE_FrequentEvents(t *testing.T) {
+	events := make(chan int)
+	rec := httptest.NewRecorder()
+
+	go func() {
+		for i := 0; i < 20; i++ {
+			events <- i
+			time.Sleep(10 * time.Millisecond)
+		}
+		close(events)
+	}()
+
+	if err := httpresponse.StreamSSE(context.Background(), rec, events, 100*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body := rec.Body.String()
+	if count := strings.Count(body, "data: "); count != 20 {
+		t.Errorf("Expected 20 events, got %d", count)
+	}
+	if strings.Contains(body, ": heartbeat\n\n") {
+		t.Errorf("Expected no heartbeats between frequent events, got %q", body)
+	}
+}
+
+// TestStreamSSE_NoHeartbeat tests that a zero interval disables heartbeats.
+func TestStreamSSE_NoHeartbeat(t *testing.T) {
+	events := make(chan string, 1)
+	events <- "only"
+	close(events)
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.StreamSSE(context.Background(), rec, events, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Body.String() != "data: \"only\"\n\n" {
+		t.Errorf("Expected a single event, got %q", rec.Body.String())
+	}
+}
+
+// TestStreamSSE_NoContentLength tests that a Content-Length set by earlier handlers is not sent with a stream.
+func TestStreamSSE_NoContentLength(t *testing.T) {
+	events := make(chan int)
+	close(events)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Length", "10")
+
+	if err := httpresponse.StreamSSE(context.Background(), rec, events, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := rec.Result().Header.Get("Content-Length"); got != "" {
+		t.Errorf("Expected no Content-Length, got %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+package httpresponse
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// KeyStack is the Extra key under which SetStackTrace records the stack trace of an error.
+const KeyStack = "stack"
+
+// maxStackDepth is the maximum number of frames captured by WithStack.
+const maxStackDepth = 32
+
+// StackFrame is one frame of a stack trace.
+type StackFrame struct {
+	Function string `json:"function"` // The fully qualified name of the function.
+	File     string `json:"file"`     // The path of the source file.
+	Line     int    `json:"line"`     // The line in the source file.
+}
+
+// stackError is an error annotated with the stack trace of its creation by WithStack.
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// WithStack annotates err with the stack trace of the caller, so that SetStackTrace can record it.
+//
+// Parameters:
+//   - err: The error to annotate; nil yields nil.
+//
+// Returns:
+//   - error: An error wrapping err, with the same message, carrying the stack trace.
+func WithStack(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+
+	return &stackError{err: err, pcs: pcs[:n]}
+}
+
+// Error returns the message of the wrapped error.
+func (stackError *stackError) Error() string {
+	return stackError.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (stackError *stackError) Unwrap() error {
+	return stackError.err
+}
+
+// Callers returns the program counters of the stack trace.
+func (stackError *stackError) Callers() []uintptr {
+	return stackError.pcs
+}
+
+// SetStackTrace records the stack trace carried by err under the "stack" Extra key as a list of
+// StackFrame, innermost first, but only in debug mode (see SetDebug), so that stack traces never leak
+// in production. Stack traces are found along the Unwrap chain of err, and the deepest one, closest to
+// the origin of the error, is recorded. Errors annotated by WithStack carry a stack trace, as do errors
+// implementing Callers() []uintptr or a StackTrace method returning a slice of program counters, such as
+// those of github.com/pkg/errors. Errors carrying no stack trace record nothing.
+//
+// Parameters:
+//   - err: The error whose stack trace is recorded.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetStackTrace(err error) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if !Debug() {
+			return nil
+		}
+
+		frames := stackFrames(err)
+		if len(frames) == 0 {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyStack] = frames
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// stackFrames returns the frames of the deepest stack trace carried along the Unwrap chain of err.
+func stackFrames(err error) []StackFrame {
+
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if found := stackCallers(err); len(found) > 0 {
+			pcs = found
+		}
+	}
+
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := make([]StackFrame, 0, len(pcs))
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// stackCallers returns the program counters of the stack trace carried by err itself, if any.
+func stackCallers(err error) []uintptr {
+
+	if caller, ok := err.(interface{ Callers() []uintptr }); ok {
+		return caller.Callers()
+	}
+
+	// Match StackTrace methods returning slices of program counters of any named type, such as
+	// the errors.StackTrace of github.com/pkg/errors, without depending on their packages
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+
+	return pcs
+}
@@ -0,0 +1,101 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// frame mirrors the Frame type of github.com/pkg/errors.
+type frame uintptr
+
+// stackTracer mirrors the errors carrying a stack trace of github.com/pkg/errors.
+type stackTracer struct {
+	pcs []frame
+}
+
+func (stackTracer *stackTracer) Error() string { return "pkg error" }
+
+func (stackTracer *stackTracer) StackTrace() []frame { return stackTracer.pcs }
+
+// newStackTracer returns a stackTracer with the stack trace of the caller.
+func newStackTracer() error {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(2, pcs)
+
+	frames := make([]frame, n)
+	for i := range frames {
+		frames[i] = frame(pcs[i])
+	}
+
+	return &stackTracer{pcs: frames}
+}
+
+// TestSetStackTrace tests that the stack trace is recorded only in debug mode.
+func TestSetStackTrace(t *testing.T) {
+	cause := fmt.Errorf("loading order: %w", httpresponse.WithStack(errors.New("connection reset")))
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](cause).SetStackTrace(cause),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := response.Extra[httpresponse.KeyStack]; ok {
+		t.Errorf("Expected no stack trace outside debug mode")
+	}
+
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](cause).SetStackTrace(cause),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frames, ok := response.Extra[httpresponse.KeyStack].([]httpresponse.StackFrame)
+	if !ok || len(frames) == 0 {
+		t.Fatalf("Expected a stack trace in debug mode")
+	}
+	if !strings.HasSuffix(frames[0].Function, "TestSetStackTrace") || !strings.HasSuffix(frames[0].File, "stack_test.go") {
+		t.Errorf("Expected the innermost frame in TestSetStackTrace, got %+v", frames[0])
+	}
+}
+
+// TestSetStackTrace_StackTracer tests that stack traces of StackTrace methods are recorded, and that errors
+// without a stack trace record nothing.
+func TestSetStackTrace_StackTracer(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	cause := newStackTracer()
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](cause).SetStackTrace(cause),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frames, ok := response.Extra[httpresponse.KeyStack].([]httpresponse.StackFrame)
+	if !ok || !strings.HasSuffix(frames[0].Function, "TestSetStackTrace_StackTracer") {
+		t.Errorf("Expected the innermost frame in TestSetStackTrace_StackTracer, got %v", frames)
+	}
+
+	cause = errors.New("plain")
+
+	response, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](cause).SetStackTrace(cause),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := response.Extra[httpresponse.KeyStack]; ok {
+		t.Errorf("Expected no stack trace for an error without one")
+	}
+}
@@ -0,0 +1,107 @@
+package httpresponse
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// StatsSnapshot is a point-in-time copy of the response statistics of the process.
+type StatsSnapshot struct {
+	Built              uint64  // Envelopes successfully built with rpsutil.Build.
+	BuildFailures      uint64  // Builds that failed, including build-time validation failures.
+	Options            uint64  // Configuration functions applied across all builds.
+	AvgOptionsPerBuild float64 // Options divided by the number of builds, successful or not.
+	Marshals           uint64  // Successful MarshalJSON calls.
+	MarshalBytes       uint64  // Total size of the MarshalJSON output.
+	Writes             uint64  // Responses written by the writers.
+}
+
+// stats holds the live counters behind Stats.
+// The fields are only accessed through sync/atomic and are kept first and contiguous for 64-bit alignment.
+var stats struct {
+	built         uint64
+	buildFailures uint64
+	options       uint64
+	marshals      uint64
+	marshalBytes  uint64
+	writes        uint64
+}
+
+// statsRecorder is implemented by every instantiation of HTTPResponseOptions, which lets the build
+// interceptor recognize envelope builds regardless of type parameters.
+type statsRecorder interface {
+	recordsStats()
+}
+
+// recordsStats marks HTTPResponseOptions as counted by the build statistics.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) recordsStats() {}
+
+var statsRecorderType = reflect.TypeOf((*statsRecorder)(nil)).Elem()
+
+func init() {
+	rpsutil.RegisterInterceptor(recordBuild)
+}
+
+// recordBuild updates the build counters for envelope builds.
+func recordBuild(info rpsutil.BuildInfo) {
+
+	if !reflect.PointerTo(info.Type).Implements(statsRecorderType) {
+		return
+	}
+
+	atomic.AddUint64(&stats.options, uint64(info.Options))
+
+	if info.Err != nil {
+		atomic.AddUint64(&stats.buildFailures, 1)
+		return
+	}
+
+	atomic.AddUint64(&stats.built, 1)
+}
+
+// Stats returns a snapshot of the response statistics collected since start-up or the last ResetStats.
+// Statistics are always collected, with atomic counters only.
+// Counters are read individually, so a snapshot taken during concurrent activity may mix adjacent states.
+//
+// Returns:
+//   - StatsSnapshot: The current counter values.
+func Stats() StatsSnapshot {
+
+	snapshot := StatsSnapshot{
+		Built:         atomic.LoadUint64(&stats.built),
+		BuildFailures: atomic.LoadUint64(&stats.buildFailures),
+		Options:       atomic.LoadUint64(&stats.options),
+		Marshals:      atomic.LoadUint64(&stats.marshals),
+		MarshalBytes:  atomic.LoadUint64(&stats.marshalBytes),
+		Writes:        atomic.LoadUint64(&stats.writes),
+	}
+
+	if builds := snapshot.Built + snapshot.BuildFailures; builds > 0 {
+		snapshot.AvgOptionsPerBuild = float64(snapshot.Options) / float64(builds)
+	}
+
+	return snapshot
+}
+
+// ResetStats sets all response statistics back to zero.
+func ResetStats() {
+	atomic.StoreUint64(&stats.built, 0)
+	atomic.StoreUint64(&stats.buildFailures, 0)
+	atomic.StoreUint64(&stats.options, 0)
+	atomic.StoreUint64(&stats.marshals, 0)
+	atomic.StoreUint64(&stats.marshalBytes, 0)
+	atomic.StoreUint64(&stats.writes, 0)
+}
+
+// recordMarshal counts a successful marshal of n bytes.
+func recordMarshal(n int) {
+	atomic.AddUint64(&stats.marshals, 1)
+	atomic.AddUint64(&stats.marshalBytes, uint64(n))
+}
+
+// recordWrite counts a response written by the writers.
+func recordWrite() {
+	atomic.AddUint64(&stats.writes, 1)
+}
@@ -0,0 +1,98 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestStats_Counters tests that build, marshal and write counters move as expected.
+func TestStats_Counters(t *testing.T) {
+	httpresponse.ResetStats()
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetMessage("ok").SetData("x")
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	failing := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetMessage("bad \xff").
+		ValidateUTF8(httpresponse.UTF8Reject)
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](failing); !errors.Is(err, httpresponse.ErrInvalidUTF8) {
+		t.Fatalf("Expected ErrInvalidUTF8, got %v", err)
+	}
+
+	// Builds of other types are not counted
+	type other struct{}
+	if _, err := rpsutil.Build[other](); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := httpresponse.WriteJSON(httptest.NewRecorder(), response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	snapshot := httpresponse.Stats()
+
+	expected := httpresponse.StatsSnapshot{
+		Built:              1,
+		BuildFailures:      1,
+		Options:            6,
+		AvgOptionsPerBuild: 3,
+		Marshals:           2,
+		MarshalBytes:       uint64(2 * len(body)),
+		Writes:             1,
+	}
+	if snapshot != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, snapshot)
+	}
+
+	httpresponse.ResetStats()
+	if snapshot := httpresponse.Stats(); snapshot != (httpresponse.StatsSnapshot{}) {
+		t.Errorf("Expected zero stats after reset, got %+v", snapshot)
+	}
+}
+
+// TestStats_Concurrent tests that counters are safe and exact under concurrent use; run with -race.
+func TestStats_Concurrent(t *testing.T) {
+	httpresponse.ResetStats()
+
+	const workers, iterations = 8, 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("x")
+				response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+				if _, err := response.MarshalJSON(); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+				_ = httpresponse.Stats()
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot := httpresponse.Stats()
+	if snapshot.Built != workers*iterations || snapshot.Marshals != workers*iterations {
+		t.Errorf("Expected %d builds and marshals, got %+v", workers*iterations, snapshot)
+	}
+}
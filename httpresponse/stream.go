@@ -0,0 +1,153 @@
+package httpresponse
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// StreamBufferBytes is the size up to which the streaming helpers buffer the stream of a Buffered destination.
+// Beyond it, the buffered bytes are sent and the rest of the stream is written through, so that a long stream
+// does not grow the buffer without limit.
+const StreamBufferBytes = 1 << 20
+
+// StreamCapabilities reports what a streaming destination supports, as probed by ProbeStream.
+type StreamCapabilities struct {
+	Flush    bool // Whether the destination, or a writer it wraps, implements http.Flusher.
+	Hijack   bool // Whether the destination, or a writer it wraps, implements http.Hijacker.
+	Buffered bool // Whether the streaming helpers buffer the stream, up to StreamBufferBytes, until it ends.
+}
+
+// ProbeStream reports the capabilities of w as the streaming helpers (StreamNDJSON, StreamSSE and Heartbeat)
+// see them. Like http.NewResponseController, it looks through middleware wrappers exposing the
+// http.ResponseWriter they wrap with an Unwrap method. An http.ResponseWriter that cannot be flushed is
+// Buffered: the helpers send its stream when it ends, or once it exceeds StreamBufferBytes, instead of
+// trickling it into the server's buffer, and StreamSSE sends no heartbeats, as they could not keep the
+// connection alive.
+//
+// Parameters:
+//   - w: The streaming destination.
+//
+// Returns:
+//   - StreamCapabilities: The capabilities of w.
+func ProbeStream(w io.Writer) StreamCapabilities {
+
+	var capabilities StreamCapabilities
+
+	for _, writer := range unwrapWriters(w) {
+		if _, ok := writer.(http.Flusher); ok {
+			capabilities.Flush = true
+		}
+		if _, ok := writer.(http.Hijacker); ok {
+			capabilities.Hijack = true
+		}
+	}
+
+	_, isResponseWriter := w.(http.ResponseWriter)
+	capabilities.Buffered = isResponseWriter && !capabilities.Flush
+
+	return capabilities
+}
+
+// unwrapWriters returns w followed by the writers it wraps, outermost first, following the Unwrap methods
+// of middleware wrappers.
+func unwrapWriters(w io.Writer) []io.Writer {
+
+	var writers []io.Writer
+
+	for current := w; current != nil; {
+
+		writers = append(writers, current)
+
+		unwrapper, ok := current.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+
+		// Stop at a nil writer, and at cyclic wrappers
+		next := unwrapper.Unwrap()
+		if next == nil || len(writers) > 64 {
+			break
+		}
+		current = next
+	}
+
+	return writers
+}
+
+// streamWriter writes a stream to w, flushing through the first http.Flusher found by unwrapping w, or
+// buffering the stream until Close, or until it exceeds StreamBufferBytes, when w is Buffered.
+type streamWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	buf     *bytes.Buffer
+}
+
+// newStreamWriter returns a streamWriter for w. When buffer is false, a Buffered w is written through.
+func newStreamWriter(w io.Writer, buffer bool) *streamWriter {
+
+	streamWriter := &streamWriter{w: w}
+
+	for _, writer := range unwrapWriters(w) {
+		if flusher, ok := writer.(http.Flusher); ok {
+			streamWriter.flusher = flusher
+			break
+		}
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok {
+		// The length of a stream is unknown when its headers are sent
+		rw.Header().Del("Content-Length")
+
+		if buffer && streamWriter.flusher == nil {
+			streamWriter.buf = new(bytes.Buffer)
+		}
+	}
+
+	return streamWriter
+}
+
+// Write implements io.Writer.
+func (streamWriter *streamWriter) Write(p []byte) (int, error) {
+
+	if streamWriter.buf != nil {
+		if streamWriter.buf.Len()+len(p) <= StreamBufferBytes {
+			return streamWriter.buf.Write(p)
+		}
+
+		// Send what was held and write the rest of the stream through
+		buffered := streamWriter.buf.Bytes()
+		streamWriter.buf = nil
+		if _, err := streamWriter.w.Write(buffered); err != nil {
+			return 0, err
+		}
+	}
+
+	return streamWriter.w.Write(p)
+}
+
+// Flush implements http.Flusher; it does nothing if the destination cannot be flushed.
+func (streamWriter *streamWriter) Flush() {
+
+	if streamWriter.flusher != nil {
+		streamWriter.flusher.Flush()
+	}
+}
+
+// buffered reports whether the stream is held until Close or until it exceeds StreamBufferBytes.
+func (streamWriter *streamWriter) buffered() bool {
+	return streamWriter.buf != nil
+}
+
+// Close sends the buffered stream, if any.
+func (streamWriter *streamWriter) Close() error {
+
+	if streamWriter.buf == nil || streamWriter.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := streamWriter.w.Write(streamWriter.buf.Bytes())
+	streamWriter.buf.Reset()
+
+	return err
+}
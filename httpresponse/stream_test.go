@@ -0,0 +1,124 @@
+package httpresponse_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// plainWriter is a middleware wrapper hiding the capabilities of the writer it wraps.
+type plainWriter struct {
+	http.ResponseWriter
+	writes int
+}
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	p.writes++
+	return p.ResponseWriter.Write(b)
+}
+
+// unwrappingWriter is a middleware wrapper exposing the writer it wraps through Unwrap.
+type unwrappingWriter struct {
+	http.ResponseWriter
+}
+
+func (u *unwrappingWriter) Unwrap() http.ResponseWriter {
+	return u.ResponseWriter
+}
+
+// TestProbeStream tests the capabilities reported for bare, hiding and unwrapping writers.
+func TestProbeStream(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	tests := []struct {
+		name string
+		w    http.ResponseWriter
+		want httpresponse.StreamCapabilities
+	}{
+		{"recorder", recorder, httpresponse.StreamCapabilities{Flush: true}},
+		{"hiding wrapper", &plainWriter{ResponseWriter: recorder}, httpresponse.StreamCapabilities{Buffered: true}},
+		{"unwrapping wrapper", &unwrappingWriter{ResponseWriter: recorder}, httpresponse.StreamCapabilities{Flush: true}},
+	}
+
+	for _, tt := range tests {
+		if got := httpresponse.ProbeStream(tt.w); got != tt.want {
+			t.Errorf("%s: Expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestStreamSSE_WithoutFlusher tests that a writer lacking Flusher receives the whole stream at once.
+func TestStreamSSE_WithoutFlusher(t *testing.T) {
+	events := make(chan int)
+	go func() {
+		for i := 0; i < 3; i++ {
+			events <- i
+			time.Sleep(10 * time.Millisecond)
+		}
+		close(events)
+	}()
+
+	w := &plainWriter{ResponseWriter: httptest.NewRecorder()}
+	if err := httpresponse.StreamSSE(context.Background(), w, events, 5*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body := w.ResponseWriter.(*httptest.ResponseRecorder).Body.String()
+	if body != "data: 0\n\ndata: 1\n\ndata: 2\n\n" {
+		t.Errorf("Expected the events without heartbeats, got %q", body)
+	}
+	if w.writes != 1 {
+		t.Errorf("Expected the stream in a single write, got %d writes", w.writes)
+	}
+}
+
+// TestStreamSSE_WithoutFlusherLimit tests that a stream exceeding StreamBufferBytes on a writer lacking
+// Flusher is written through once the buffer is full.
+func TestStreamSSE_WithoutFlusherLimit(t *testing.T) {
+	event := strings.Repeat("x", httpresponse.StreamBufferBytes/2)
+	events := make(chan string, 3)
+	for i := 0; i < 3; i++ {
+		events <- event
+	}
+	close(events)
+
+	w := &plainWriter{ResponseWriter: httptest.NewRecorder()}
+	if err := httpresponse.StreamSSE(context.Background(), w, events, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body := w.ResponseWriter.(*httptest.ResponseRecorder).Body.String()
+	if body != strings.Repeat("data: \""+event+"\"\n\n", 3) {
+		t.Errorf("Expected the 3 events, got %d bytes", len(body))
+	}
+	if w.writes != 3 {
+		t.Errorf("Expected the buffered event then 2 written through, got %d writes", w.writes)
+	}
+}
+
+// TestStreamNDJSON_Unwrap tests that a Flusher exposed through Unwrap is flushed.
+func TestStreamNDJSON_Unwrap(t *testing.T) {
+	items := make(chan string, 2)
+	items <- "a"
+	items <- "b"
+	close(items)
+
+	recorder := httptest.NewRecorder()
+	err := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		StreamNDJSON(&unwrappingWriter{ResponseWriter: recorder}, items, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !recorder.Flushed {
+		t.Errorf("Expected the wrapped writer to be flushed")
+	}
+	if got := strings.Count(recorder.Body.String(), "\n"); got != 2 {
+		t.Errorf("Expected 2 lines, got %d", got)
+	}
+}
@@ -0,0 +1,32 @@
+package httpresponse
+
+import "errors"
+
+// ErrAmbiguousSuccess is returned when building a strict builder on which both SetSuccess and an error
+// setter, such as SetError, were called.
+var ErrAmbiguousSuccess = errors.New("httpresponse: both an explicit success status and an error were set")
+
+// Strict enables strict mode: building fails with ErrAmbiguousSuccess, before any option is applied, if
+// both SetSuccess and SetError (or FromError) were used, since the resulting status would depend on the
+// order of the calls. Presets and other constructors deriving the status themselves do not count as an
+// explicit SetSuccess call.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) Strict() *HTTPResponseBuilder[C, D, E, T] {
+
+	httpResponseBuilder.strict = true
+
+	return httpResponseBuilder
+}
+
+// ValidateList reports the contradictory configurations rejected in strict mode. It is called by
+// rpsutil.Build before any option is applied.
+//
+// Returns:
+//   - error: ErrAmbiguousSuccess if the builder is strict and both SetSuccess and an error were set; otherwise, nil.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) ValidateList() error {
+
+	if httpResponseBuilder.strict && httpResponseBuilder.successSet && httpResponseBuilder.errorSet {
+		return ErrAmbiguousSuccess
+	}
+
+	return nil
+}
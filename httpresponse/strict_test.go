@@ -0,0 +1,74 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestStrict tests that strict builders reject an explicit success combined with an error.
+func TestStrict(t *testing.T) {
+	failure := errors.New("connection reset")
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			Strict().
+			SetSuccess(true).
+			SetError(failure),
+	)
+	if !errors.Is(err, httpresponse.ErrAmbiguousSuccess) {
+		t.Errorf("Expected ErrAmbiguousSuccess, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.FromError[int, string, map[string]interface{}, int](failure).SetSuccess(false).Strict(),
+	)
+	if !errors.Is(err, httpresponse.ErrAmbiguousSuccess) {
+		t.Errorf("Expected ErrAmbiguousSuccess, got %v", err)
+	}
+}
+
+// TestStrict_OptIn tests that non-strict builders and strict builders without conflicts build normally.
+func TestStrict_OptIn(t *testing.T) {
+	failure := errors.New("connection reset")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetSuccess(true).
+			SetError(failure),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Success || response.Message != httpresponse.GenericErrorMessage {
+		t.Errorf("Expected SetError to flip Success, got %+v", response)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.UnprocessableEntity[int, string, map[string]interface{}, int](nil).Strict().SetError(failure),
+	)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// TestSetError_KeepsExtra tests that SetError adds its details to the Extra fields set before.
+func TestSetError_KeepsExtra(t *testing.T) {
+	httpresponse.SetDebug(true)
+	defer httpresponse.SetDebug(false)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{"request_id": "abc"}).
+			SetError(errors.New("connection reset")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Extra["request_id"] != "abc" || response.Extra["error"] != "connection reset" {
+		t.Errorf("Expected request_id and error in Extra, got %v", response.Extra)
+	}
+}
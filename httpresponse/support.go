@@ -0,0 +1,109 @@
+package httpresponse
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+const (
+	// KeySupport is the Extra key under which the support contact block is stored.
+	KeySupport = "support"
+
+	// KeyResponseID is the Extra key identifying the response, quoted as the reference of the support block.
+	KeyResponseID = "response_id"
+)
+
+// SupportInfo describes how to get help about a failed response.
+type SupportInfo struct {
+	URL       string `json:"url,omitempty"`   // The URL of the support page.
+	Email     string `json:"email,omitempty"` // The support email address.
+	Phone     string `json:"phone,omitempty"` // The support phone line, such as one localized for the client.
+	Reference string `json:"reference"`       // The reference of the response; set from its "response_id" when included.
+}
+
+// defaultSupportInfo holds the *SupportInfo included in 5xx responses; nil if none is registered.
+var defaultSupportInfo atomic.Value
+
+func init() {
+	defaultSupportInfo.Store((*SupportInfo)(nil))
+}
+
+// SetDefaultSupportInfo registers the support contact block included automatically, under the "support"
+// Extra key, in every 5xx response that has none of its own (see SetSupportInfo).
+//
+// Parameters:
+//   - info: The default support contact block; nil stops the automatic inclusion.
+func SetDefaultSupportInfo(info *SupportInfo) {
+
+	if info != nil {
+		copied := *info
+		info = &copied
+	}
+
+	defaultSupportInfo.Store(info)
+}
+
+// SetSupportInfo includes info under the "support" Extra key if the response fails, overriding the default
+// support contact block; it is never included in successful responses. The reference of the block is the
+// "response_id" Extra value of the response, which is generated with the IDGen dependency (see Deps) if
+// missing. It runs after all other options, so it sees the final outcome of the response.
+//
+// Parameters:
+//   - info: The support contact block; its Reference is overwritten.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetSupportInfo(info SupportInfo) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if !args.Success {
+			includeSupport(args, info)
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// defaultSupport includes the default support contact block in 5xx responses without one. List runs it
+// after all other options while a default is registered.
+func defaultSupport[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](args *HTTPResponseOptions[C, D, E, T]) error {
+
+	info := defaultSupportInfo.Load().(*SupportInfo)
+	if info == nil || args.Success || args.StatusCode() < http.StatusInternalServerError {
+		return nil
+	}
+
+	if _, ok := args.Extra[KeySupport]; !ok {
+		includeSupport(args, *info)
+	}
+
+	return nil
+}
+
+// includeSupport stores info under the "support" Extra key, with the response ID as its reference.
+func includeSupport[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](args *HTTPResponseOptions[C, D, E, T], info SupportInfo) {
+
+	extra := make(E, len(args.Extra)+2)
+	for k, v := range args.Extra {
+		extra[k] = v
+	}
+
+	reference, _ := extra[KeyResponseID].(string)
+	if reference == "" {
+		reference = args.deps().IDGen()
+		extra[KeyResponseID] = reference
+	}
+
+	info.Reference = reference
+	extra[KeySupport] = info
+	args.Extra = extra
+}
@@ -0,0 +1,80 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// registerSupport registers a default support block and fixed response IDs for the duration of the test.
+func registerSupport(t *testing.T) {
+	t.Helper()
+
+	httpresponse.SetDefaultSupportInfo(&httpresponse.SupportInfo{URL: "https://help.example.com", Email: "help@example.com"})
+	t.Cleanup(func() {
+		httpresponse.SetDefaultSupportInfo(nil)
+	})
+
+	httpresponse.WithDeps(t, httpresponse.Deps{IDGen: func() string { return "resp-1" }})
+}
+
+// TestSupportInfo_Default tests that the default support block is included in 5xx responses.
+func TestSupportInfo_Default(t *testing.T) {
+	registerSupport(t)
+
+	response, err := rpsutil.Build[actionResponse](httpresponse.FromError[int, string, map[string]interface{}, int](errors.New("boom")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := httpresponse.SupportInfo{URL: "https://help.example.com", Email: "help@example.com", Reference: "resp-1"}
+	if got := response.Extra[httpresponse.KeySupport]; got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := response.Extra[httpresponse.KeyResponseID]; got != "resp-1" {
+		t.Errorf("Expected the generated response ID, got %v", got)
+	}
+}
+
+// TestSupportInfo_Override tests that a support block set on the builder wins and quotes the response ID.
+func TestSupportInfo_Override(t *testing.T) {
+	registerSupport(t)
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetSupportInfo(httpresponse.SupportInfo{Phone: "+33 1 23 45 67 89"}).
+		SetSuccess(false).
+		SetCode(http.StatusServiceUnavailable).
+		SetExtra(map[string]interface{}{httpresponse.KeyResponseID: "req-42"})
+
+	response, err := rpsutil.Build[actionResponse](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := httpresponse.SupportInfo{Phone: "+33 1 23 45 67 89", Reference: "req-42"}
+	if got := response.Extra[httpresponse.KeySupport]; got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// TestSupportInfo_Success tests that no support block is included in successful or client error responses.
+func TestSupportInfo_Success(t *testing.T) {
+	registerSupport(t)
+
+	for _, builder := range []*httpresponse.HTTPResponseBuilder[int, string, map[string]interface{}, int]{
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(http.StatusOK),
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetSupportInfo(httpresponse.SupportInfo{URL: "https://help.example.com"}),
+		httpresponse.Unauthorized[int, string, map[string]interface{}, int](),
+	} {
+		response, err := rpsutil.Build[actionResponse](builder)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, ok := response.Extra[httpresponse.KeySupport]; ok {
+			t.Errorf("Expected no support block, got %+v", response.Extra)
+		}
+	}
+}
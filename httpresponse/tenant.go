@@ -0,0 +1,117 @@
+package httpresponse
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// KeyTenantID is the Extra key under which SetTenantFromContext includes the tenant ID.
+const KeyTenantID = "tenant_id"
+
+// ErrMissingTenant is returned when building a response that requires a tenant for a context carrying none.
+var ErrMissingTenant = errors.New("httpresponse: no tenant in context")
+
+// TenantExtractor extracts the tenant ID from a context, reporting whether one is present.
+type TenantExtractor func(ctx context.Context) (string, bool)
+
+// tenantContextKey is the context key used by WithTenant.
+type tenantContextKey struct{}
+
+var (
+	tenantExtractorMu sync.RWMutex
+	tenantExtractor   TenantExtractor = defaultTenantExtractor
+)
+
+// WithTenant returns a copy of ctx carrying tenantID, for the default tenant extractor.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - tenantID: The ID of the tenant.
+//
+// Returns:
+//   - context.Context: The context carrying the tenant.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// SetTenantExtractor replaces the function extracting tenant IDs from contexts, for services whose
+// middleware stores the tenant under its own context key. A nil extractor restores the default one,
+// which reads the tenant stored by WithTenant.
+//
+// Parameters:
+//   - extractor: The tenant extractor.
+func SetTenantExtractor(extractor TenantExtractor) {
+
+	tenantExtractorMu.Lock()
+	defer tenantExtractorMu.Unlock()
+
+	if extractor == nil {
+		extractor = defaultTenantExtractor
+	}
+	tenantExtractor = extractor
+}
+
+// tenantFromContext extracts the tenant ID from ctx with the current extractor.
+func tenantFromContext(ctx context.Context) (string, bool) {
+
+	tenantExtractorMu.RLock()
+	extractor := tenantExtractor
+	tenantExtractorMu.RUnlock()
+
+	tenantID, ok := extractor(ctx)
+
+	return tenantID, ok && tenantID != ""
+}
+
+// defaultTenantExtractor reads the tenant stored by WithTenant.
+func defaultTenantExtractor(ctx context.Context) (string, bool) {
+
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+
+	return tenantID, ok
+}
+
+// SetTenantFromContext includes the tenant ID carried by ctx under the "tenant_id" Extra key.
+// If ctx carries no tenant, the key is omitted; use RequireTenant to fail instead.
+//
+// Parameters:
+//   - ctx: The context of the request.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetTenantFromContext(ctx context.Context) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		tenantID, ok := tenantFromContext(ctx)
+		if !ok {
+			return nil
+		}
+
+		extra := make(E, len(args.Extra)+1)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyTenantID] = tenantID
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// RequireTenant fails the build with ErrMissingTenant if ctx carries no tenant, catching handlers that are
+// missing tenant scoping.
+//
+// Parameters:
+//   - ctx: The context of the request.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) RequireTenant(ctx context.Context) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if _, ok := tenantFromContext(ctx); !ok {
+			return ErrMissingTenant
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
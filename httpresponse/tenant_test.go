@@ -0,0 +1,75 @@
+package httpresponse_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetTenantFromContext tests that the tenant carried by the context is included in Extra.
+func TestSetTenantFromContext(t *testing.T) {
+	ctx := httpresponse.WithTenant(context.Background(), "acme")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetTenantFromContext(ctx).
+			RequireTenant(ctx),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Extra[httpresponse.KeyTenantID] != "acme" {
+		t.Errorf("Expected tenant acme, got %v", response.Extra)
+	}
+}
+
+// TestSetTenantFromContext_Missing tests that a missing tenant fails RequireTenant and omits the key otherwise.
+func TestSetTenantFromContext_Missing(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetTenantFromContext(context.Background()).
+			RequireTenant(context.Background()),
+	)
+	if !errors.Is(err, httpresponse.ErrMissingTenant) {
+		t.Errorf("Expected ErrMissingTenant, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetTenantFromContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := response.Extra[httpresponse.KeyTenantID]; ok {
+		t.Errorf("Expected no tenant, got %v", response.Extra)
+	}
+}
+
+// TestSetTenantExtractor tests a pluggable extractor reading the tenant from a custom context key.
+func TestSetTenantExtractor(t *testing.T) {
+	type orgKey struct{}
+
+	httpresponse.SetTenantExtractor(func(ctx context.Context) (string, bool) {
+		org, ok := ctx.Value(orgKey{}).(string)
+		return org, ok
+	})
+	defer httpresponse.SetTenantExtractor(nil)
+
+	ctx := context.WithValue(context.Background(), orgKey{}, "globex")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetTenantFromContext(ctx).
+			RequireTenant(ctx),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Extra[httpresponse.KeyTenantID] != "globex" {
+		t.Errorf("Expected tenant globex, got %v", response.Extra)
+	}
+}
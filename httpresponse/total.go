@@ -0,0 +1,177 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var (
+	// ErrNegativeTotal is returned when building a response with a negative total that was not allowed.
+	ErrNegativeTotal = errors.New("httpresponse: negative total")
+
+	// ErrTotalOutOfRange is returned when building a response whose total exceeds the maximum total, or
+	// whose total set with SetTotalFromInt cannot be represented by T.
+	ErrTotalOutOfRange = errors.New("httpresponse: total out of range")
+
+	// ErrTotalWithoutList is returned by TotalRequiresListData when a total is set on a response whose data
+	// is not a list.
+	ErrTotalWithoutList = errors.New("httpresponse: total set on non-list data")
+)
+
+// AllowNegativeTotal accepts negative totals set with SetTotal, such as -1 standing for an unknown count.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) AllowNegativeTotal() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.AllowNegativeTotal = true
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// TotalRequiresListData rejects totals on responses whose Data is not a list, where a total is meaningless
+// and most likely set by mistake: building fails with ErrTotalWithoutList if Total is non-zero and Data is
+// not a slice, an array or a map. It runs after all other options, so it checks the final Data and Total.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) TotalRequiresListData() *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		if args.Total == 0 {
+			return nil
+		}
+
+		data := reflect.ValueOf(&args.Data).Elem()
+		for (data.Kind() == reflect.Interface || data.Kind() == reflect.Pointer) && !data.IsNil() {
+			data = data.Elem()
+		}
+
+		switch data.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return nil
+		default:
+			return fmt.Errorf("%w: %d with data of type %T", ErrTotalWithoutList, args.Total, args.Data)
+		}
+	})
+
+	return httpResponseBuilder
+}
+
+// SetMaxTotal sets the largest total accepted by SetTotal. Without it, unsigned 32- and 64-bit totals above
+// the largest signed value of the same width are rejected, as they almost certainly wrapped around.
+//
+// Parameters:
+//   - max: The largest accepted total.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetMaxTotal(max uint64) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.MaxTotal = max
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// SetTotalFromInt sets the total from a signed count, such as the result of a subtraction, converting it to T
+// safely: building fails with ErrTotalOutOfRange if total cannot be represented by T (for example, a negative
+// count with an unsigned T), instead of silently wrapping around.
+//
+// Parameters:
+//   - total: The total value.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetTotalFromInt(total int64) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		converted, err := TotalFromInt64[T](total)
+		if err != nil {
+			return err
+		}
+
+		args.Total = converted
+
+		return nil
+	})
+
+	httpResponseBuilder.addFinalizer(validateTotal[C, D, E, T])
+
+	return httpResponseBuilder
+}
+
+// TotalFromInt64 converts n to the total type T, failing with ErrTotalOutOfRange instead of wrapping around
+// if T cannot represent n.
+//
+// Parameters:
+//   - n: The value to convert.
+//
+// Returns:
+//   - T: The converted total.
+//   - error: An error if n is out of the range of T.
+func TotalFromInt64[T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64](n int64) (T, error) {
+
+	total := T(n)
+	if int64(total) != n || (n < 0) != (total < 0) {
+		return 0, fmt.Errorf("%w: %d does not fit in %T", ErrTotalOutOfRange, n, total)
+	}
+
+	return total, nil
+}
+
+// TotalFromUint64 converts n to the total type T, failing with ErrTotalOutOfRange instead of wrapping around
+// if T cannot represent n.
+//
+// Parameters:
+//   - n: The value to convert.
+//
+// Returns:
+//   - T: The converted total.
+//   - error: An error if n is out of the range of T.
+func TotalFromUint64[T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64](n uint64) (T, error) {
+
+	total := T(n)
+	if uint64(total) != n || total < 0 {
+		return 0, fmt.Errorf("%w: %d does not fit in %T", ErrTotalOutOfRange, n, total)
+	}
+
+	return total, nil
+}
+
+// validateTotal rejects negative totals unless allowed, and totals above the maximum total.
+func validateTotal[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](args *HTTPResponseOptions[C, D, E, T]) error {
+
+	v := reflect.ValueOf(args.Total)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		total := v.Int()
+		if total < 0 {
+			if args.AllowNegativeTotal {
+				return nil
+			}
+			return fmt.Errorf("%w: %d", ErrNegativeTotal, total)
+		}
+		if args.MaxTotal > 0 && uint64(total) > args.MaxTotal {
+			return fmt.Errorf("%w: %d exceeds %d", ErrTotalOutOfRange, total, args.MaxTotal)
+		}
+
+	default:
+		total := v.Uint()
+
+		max := args.MaxTotal
+		if max == 0 && v.Type().Size() >= 4 {
+			// Values with the sign bit of their width set are most likely wrapped negatives
+			max = math.MaxUint64 >> (65 - 8*v.Type().Size())
+		}
+
+		if max > 0 && total > max {
+			return fmt.Errorf("%w: %d exceeds %d, possibly a wrapped negative value", ErrTotalOutOfRange, total, max)
+		}
+	}
+
+	return nil
+}
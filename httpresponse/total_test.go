@@ -0,0 +1,139 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestSetTotal_WrapDetection tests that unsigned totals that look like wrapped negatives fail the build.
+func TestSetTotal_WrapDetection(t *testing.T) {
+	count, offset := 3, 5
+	wrapped := uint64(count - offset)
+
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint64]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint64]().SetTotal(wrapped),
+	)
+	if !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected ErrTotalOutOfRange for %d, got %v", wrapped, err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint32]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint32]().SetTotal(math.MaxUint32),
+	)
+	if !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected ErrTotalOutOfRange for a wrapped uint32, got %v", err)
+	}
+
+	// Narrow unsigned types have no default limit
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint8]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint8]().SetTotal(200),
+	)
+	if err != nil || response.Total != 200 {
+		t.Errorf("Expected total 200, got %v (%v)", response, err)
+	}
+}
+
+// TestSetTotal_MaxTotal tests the configurable maximum total.
+func TestSetTotal_MaxTotal(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetTotal(1001).
+		SetMaxTotal(1000)
+
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder); !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected ErrTotalOutOfRange, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint64]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint64]().SetTotal(1 << 63).SetMaxTotal(math.MaxUint64),
+	)
+	if err != nil || response.Total != 1<<63 {
+		t.Errorf("Expected a raised maximum to accept 2^63, got %v", err)
+	}
+}
+
+// TestSetTotal_Negative tests that negative totals fail the build unless allowed.
+func TestSetTotal_Negative(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int64]().SetTotal(-1)
+
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int64]](builder); !errors.Is(err, httpresponse.ErrNegativeTotal) {
+		t.Errorf("Expected ErrNegativeTotal, got %v", err)
+	}
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int64]](builder.AllowNegativeTotal())
+	if err != nil {
+		t.Fatalf("Expected no error with AllowNegativeTotal, got %v", err)
+	}
+	if response.Total != -1 {
+		t.Errorf("Expected total -1, got %d", response.Total)
+	}
+}
+
+// TestSetTotalFromInt tests the safe conversion of signed counts to the total type.
+func TestSetTotalFromInt(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint]().SetTotalFromInt(42),
+	)
+	if err != nil || response.Total != 42 {
+		t.Errorf("Expected total 42, got %v (%v)", response, err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, uint]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, uint]().SetTotalFromInt(-2),
+	)
+	if !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected ErrTotalOutOfRange, got %v", err)
+	}
+}
+
+// TestTotalFromInt64 tests the conversion helpers at the bounds of several total types.
+func TestTotalFromInt64(t *testing.T) {
+	if _, err := httpresponse.TotalFromInt64[int8](128); !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected 128 not to fit in int8, got %v", err)
+	}
+	if v, err := httpresponse.TotalFromInt64[int8](-128); err != nil || v != -128 {
+		t.Errorf("Expected -128 to fit in int8, got %v (%v)", v, err)
+	}
+	if _, err := httpresponse.TotalFromInt64[uint64](-1); !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected -1 not to fit in uint64, got %v", err)
+	}
+	if _, err := httpresponse.TotalFromUint64[int64](math.MaxUint64); !errors.Is(err, httpresponse.ErrTotalOutOfRange) {
+		t.Errorf("Expected MaxUint64 not to fit in int64, got %v", err)
+	}
+	if v, err := httpresponse.TotalFromUint64[uint16](65535); err != nil || v != 65535 {
+		t.Errorf("Expected 65535 to fit in uint16, got %v (%v)", v, err)
+	}
+}
+
+// TestTotalRequiresListData tests that a total is accepted with list data and rejected with scalar data.
+func TestTotalRequiresListData(t *testing.T) {
+	_, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, []string, map[string]interface{}, int]().
+			TotalRequiresListData().
+			SetData([]string{"a", "b"}).
+			SetTotal(2),
+	)
+	if err != nil {
+		t.Errorf("Expected no error for list data, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			TotalRequiresListData().
+			SetData("a").
+			SetTotal(2),
+	)
+	if !errors.Is(err, httpresponse.ErrTotalWithoutList) {
+		t.Errorf("Expected ErrTotalWithoutList for scalar data, got %v", err)
+	}
+
+	_, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().TotalRequiresListData().SetData("a"),
+	)
+	if err != nil {
+		t.Errorf("Expected no error without total, got %v", err)
+	}
+}
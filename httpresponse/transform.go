@@ -0,0 +1,27 @@
+package httpresponse
+
+// AddDataTransformE queues a transform of the data set by the preceding options, such as a conversion to a
+// public representation. If the transform fails, the data is left unchanged, Success is set to false and
+// the message to the public message of the error, as translated by the registered error translations
+// (see RegisterErrorTranslation); the build itself does not fail. Since options are applied in order, a
+// SetSuccess or SetMessage called after AddDataTransformE overrides the outcome of the transform.
+//
+// Parameters:
+//   - transform: The function deriving the new data from the current data.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) AddDataTransformE(transform func(D) (D, error)) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		data, err := transform(args.Data)
+		if err != nil {
+			args.Success = false
+			args.Message = translateError(err).message
+			return nil
+		}
+
+		args.Data = data
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
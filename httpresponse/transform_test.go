@@ -0,0 +1,57 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestAddDataTransformE tests that a successful transform replaces the data.
+func TestAddDataTransformE(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetData("widget").
+			AddDataTransformE(func(data string) (string, error) { return strings.ToUpper(data), nil }),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success || response.Data != "WIDGET" {
+		t.Errorf("Expected successful data WIDGET, got %+v", response)
+	}
+}
+
+// TestAddDataTransformE_Error tests that a failing transform flips Success to false unless SetSuccess follows.
+func TestAddDataTransformE_Error(t *testing.T) {
+	fail := func(string) (string, error) { return "", errors.New("connection reset") }
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetData("widget").
+			AddDataTransformE(fail),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Success || response.Message != httpresponse.GenericErrorMessage || response.Data != "widget" {
+		t.Errorf("Expected a failure with the generic message and unchanged data, got %+v", response)
+	}
+
+	response, err = rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			AddDataTransformE(fail).
+			SetSuccess(true),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.Success {
+		t.Errorf("Expected the later SetSuccess to win, got %+v", response)
+	}
+}
@@ -0,0 +1,189 @@
+package httpresponse
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// KeyWarnings is the Extra key under which the placeholder policy reports the Extra values it replaced.
+const KeyWarnings = "warnings"
+
+// ErrUnserializable is returned by MarshalJSON when Extra holds a value that JSON cannot represent under
+// the UnserializableFail policy.
+var ErrUnserializable = errors.New("httpresponse: unserializable Extra value")
+
+// UnserializablePolicy selects how MarshalJSON handles Extra values that JSON cannot represent: functions,
+// channels, complex numbers and unsafe pointers, at the top level of Extra or nested in maps and slices.
+type UnserializablePolicy int
+
+const (
+	// UnserializableFail fails the encoding with ErrUnserializable, naming the first offending value. It is the default.
+	UnserializableFail UnserializablePolicy = iota
+
+	// UnserializablePlaceholder replaces each offending value with a placeholder string, such as
+	// "<unserializable func>", and lists the replacements under the "warnings" Extra key.
+	UnserializablePlaceholder
+)
+
+// SetUnserializablePolicy selects how Extra values that JSON cannot represent are handled when encoding.
+//
+// Parameters:
+//   - policy: The policy, UnserializableFail (the default) or UnserializablePlaceholder.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) SetUnserializablePolicy(policy UnserializablePolicy) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addOpt(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		args.Unserializable = policy
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// serializableExtra sweeps Extra for values that JSON cannot represent. It returns nil if there are none,
+// an error under UnserializableFail, and otherwise a copy of Extra with the values replaced and the
+// replacements appended to the "warnings" key.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) serializableExtra() (E, error) {
+
+	keys := make([]string, 0, len(httpResponseOptions.Extra))
+	for k := range httpResponseOptions.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var extra E
+	var warnings []string
+
+	for _, k := range keys {
+
+		var found []string
+		v, changed := sweepUnserializable(httpResponseOptions.Extra[k], strconv.Quote(k), &found)
+		if !changed {
+			continue
+		}
+
+		if httpResponseOptions.Unserializable != UnserializablePlaceholder {
+			return nil, fmt.Errorf("%w: %s", ErrUnserializable, found[0])
+		}
+
+		if extra == nil {
+			extra = make(E, len(httpResponseOptions.Extra)+1)
+			for k, v := range httpResponseOptions.Extra {
+				extra[k] = v
+			}
+		}
+		extra[k] = v
+		warnings = append(warnings, found...)
+	}
+
+	if extra == nil {
+		return nil, nil
+	}
+
+	previous, _ := extra[KeyWarnings].([]string)
+	extra[KeyWarnings] = append(append([]string{}, previous...), warnings...)
+
+	return extra, nil
+}
+
+// sweepUnserializable returns v with the values that JSON cannot represent replaced by placeholders, and
+// whether any was, describing each replaced value, located by path, in found. Common JSON types are handled
+// with a type switch; reflection is only used for other types.
+func sweepUnserializable(v any, path string, found *[]string) (any, bool) {
+
+	switch v := v.(type) {
+	case nil, string, bool, float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8,
+		json.Number, json.RawMessage, []string, map[string]string:
+		return v, false
+	case json.Marshaler, encoding.TextMarshaler:
+		return v, false
+	case map[string]any:
+		return sweepMap(reflect.ValueOf(v), path, found)
+	case []any:
+		return sweepSlice(reflect.ValueOf(v), path, found)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Func, reflect.Chan, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		*found = append(*found, fmt.Sprintf("%s is an unserializable %s", path, rv.Kind()))
+		return fmt.Sprintf("<unserializable %s>", rv.Kind()), true
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return sweepMap(rv, path, found)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return sweepSlice(rv, path, found)
+		}
+	}
+
+	return v, false
+}
+
+// sweepMap sweeps the values of a map with string keys, returning a map[string]any copy if any is replaced.
+func sweepMap(rv reflect.Value, path string, found *[]string) (any, bool) {
+
+	keys := make([]string, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+
+	var swept map[string]any
+	for _, k := range keys {
+
+		key := reflect.ValueOf(k).Convert(rv.Type().Key())
+		v, changed := sweepUnserializable(rv.MapIndex(key).Interface(), path+"."+strconv.Quote(k), found)
+		if !changed {
+			continue
+		}
+
+		if swept == nil {
+			swept = make(map[string]any, rv.Len())
+			for _, k := range keys {
+				swept[k] = rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface()
+			}
+		}
+		swept[k] = v
+	}
+
+	if swept == nil {
+		return rv.Interface(), false
+	}
+
+	return swept, true
+}
+
+// sweepSlice sweeps the elements of a slice or array, returning a []any copy if any is replaced.
+func sweepSlice(rv reflect.Value, path string, found *[]string) (any, bool) {
+
+	var swept []any
+	for i := 0; i < rv.Len(); i++ {
+
+		v, changed := sweepUnserializable(rv.Index(i).Interface(), path+"["+strconv.Itoa(i)+"]", found)
+		if !changed {
+			continue
+		}
+
+		if swept == nil {
+			swept = make([]any, rv.Len())
+			for j := range swept {
+				swept[j] = rv.Index(j).Interface()
+			}
+		}
+		swept[i] = v
+	}
+
+	if swept == nil {
+		return rv.Interface(), false
+	}
+
+	return swept, true
+}
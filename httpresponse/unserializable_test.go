@@ -0,0 +1,110 @@
+package httpresponse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// unserializableExtra holds a func, a chan and a complex128, at the top level and nested in maps and slices.
+func unserializableExtra() map[string]interface{} {
+	return map[string]interface{}{
+		"callback": func() {},
+		"nested": map[string]interface{}{
+			"events": make(chan int),
+			"name":   "job",
+		},
+		"points": []complex128{1 + 2i},
+		"valid":  []interface{}{1, "two"},
+	}
+}
+
+// TestUnserializable_Fail tests that the default policy fails with an error naming the first offending value.
+func TestUnserializable_Fail(t *testing.T) {
+	response := httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Extra:   unserializableExtra(),
+	}
+
+	_, err := response.MarshalJSON()
+	if !errors.Is(err, httpresponse.ErrUnserializable) {
+		t.Fatalf("Expected ErrUnserializable, got %v", err)
+	}
+	if want := `httpresponse: unserializable Extra value: "callback" is an unserializable func`; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	for key, v := range map[string]interface{}{"events": make(chan int), "amount": complex(1, 1)} {
+		response.Extra = map[string]interface{}{key: []interface{}{v}}
+		if _, err := response.MarshalJSON(); !errors.Is(err, httpresponse.ErrUnserializable) {
+			t.Errorf("Expected ErrUnserializable for a nested %T, got %v", v, err)
+		}
+	}
+}
+
+// TestUnserializable_Placeholder tests that the placeholder policy replaces offending values and warns about them.
+func TestUnserializable_Placeholder(t *testing.T) {
+	extra := unserializableExtra()
+	extra[httpresponse.KeyWarnings] = []string{"stale cache"}
+
+	response := httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success:        true,
+		Extra:          extra,
+		Unserializable: httpresponse.UnserializablePlaceholder,
+	}
+
+	jsonData, err := response.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(jsonData, &got); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	want := map[string]interface{}{
+		"success":  true,
+		"message":  "",
+		"callback": "<unserializable func>",
+		"nested":   map[string]interface{}{"events": "<unserializable chan>", "name": "job"},
+		"points":   []interface{}{"<unserializable complex128>"},
+		"valid":    []interface{}{float64(1), "two"},
+		"warnings": []interface{}{
+			"stale cache",
+			`"callback" is an unserializable func`,
+			`"nested"."events" is an unserializable chan`,
+			`"points"[0] is an unserializable complex128`,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, ok := response.Extra["callback"].(func()); !ok {
+		t.Error("Expected the Extra of the response to be left unchanged")
+	}
+}
+
+// TestHTTPResponseBuilder_SetUnserializablePolicy tests that the builder sets the policy.
+func TestHTTPResponseBuilder_SetUnserializablePolicy(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+			SetExtra(map[string]interface{}{"callback": func() {}}).
+			SetUnserializablePolicy(httpresponse.UnserializablePlaceholder),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Unserializable != httpresponse.UnserializablePlaceholder {
+		t.Errorf("Expected the placeholder policy, got %v", response.Unserializable)
+	}
+	if _, err := response.MarshalJSON(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
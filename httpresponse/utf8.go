@@ -0,0 +1,83 @@
+package httpresponse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned by Build when a string field is not valid UTF-8 under the UTF8Reject policy.
+var ErrInvalidUTF8 = errors.New("httpresponse: invalid UTF-8")
+
+// UTF8Policy determines how ValidateUTF8 handles string fields that are not valid UTF-8.
+type UTF8Policy int
+
+const (
+	UTF8Reject  UTF8Policy = iota // Fail the build with ErrInvalidUTF8.
+	UTF8Replace                   // Replace each invalid byte sequence with the Unicode replacement character.
+)
+
+// ValidateUTF8 checks at build time that Message and all string Extra values are valid UTF-8.
+// The check runs after all other options of the builder, so it observes their final values.
+//
+// Parameters:
+//   - policy: UTF8Reject to fail the build, or UTF8Replace to substitute invalid byte sequences with U+FFFD.
+func (httpResponseBuilder *HTTPResponseBuilder[C, D, E, T]) ValidateUTF8(policy UTF8Policy) *HTTPResponseBuilder[C, D, E, T] {
+	httpResponseBuilder.addFinalizer(func(args *HTTPResponseOptions[C, D, E, T]) error {
+
+		message, err := checkUTF8("message", args.Message, policy)
+		if err != nil {
+			return err
+		}
+		args.Message = message
+
+		// Replacements go to a copy of Extra, since the map may be shared with the caller
+		var extra E
+		for k, v := range args.Extra {
+
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+
+			valid, err := checkUTF8("extra "+k, s, policy)
+			if err != nil {
+				return err
+			}
+			if valid == s {
+				continue
+			}
+
+			if extra == nil {
+				extra = make(E, len(args.Extra))
+				for ek, ev := range args.Extra {
+					extra[ek] = ev
+				}
+			}
+			extra[k] = valid
+		}
+
+		if extra != nil {
+			args.Extra = extra
+		}
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// checkUTF8 applies policy to s, naming field in the returned error.
+func checkUTF8(field, s string, policy UTF8Policy) (string, error) {
+
+	if utf8.ValidString(s) {
+		return s, nil
+	}
+
+	if policy == UTF8Replace {
+		return strings.ToValidUTF8(s, string(utf8.RuneError)), nil
+	}
+
+	return "", fmt.Errorf("%w in %s", ErrInvalidUTF8, field)
+}
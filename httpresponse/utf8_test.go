@@ -0,0 +1,66 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestHTTPResponseBuilder_ValidateUTF8_Valid tests that valid UTF-8 strings pass validation unchanged.
+func TestHTTPResponseBuilder_ValidateUTF8_Valid(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		ValidateUTF8(httpresponse.UTF8Reject).
+		SetMessage("Größe: 日本").
+		SetExtra(map[string]interface{}{"name": "Zoë", "count": 3})
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Message != "Größe: 日本" {
+		t.Errorf("Expected Message to be unchanged, got %v", response.Message)
+	}
+	if response.Extra["name"] != "Zoë" {
+		t.Errorf("Expected Extra name to be unchanged, got %v", response.Extra["name"])
+	}
+}
+
+// TestHTTPResponseBuilder_ValidateUTF8_Reject tests that invalid UTF-8 fails the build, even if set after the validation.
+func TestHTTPResponseBuilder_ValidateUTF8_Reject(t *testing.T) {
+	messageBuilder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		ValidateUTF8(httpresponse.UTF8Reject).
+		SetMessage("bad \xff byte")
+
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](messageBuilder); !errors.Is(err, httpresponse.ErrInvalidUTF8) {
+		t.Errorf("Expected ErrInvalidUTF8 for Message, got %v", err)
+	}
+
+	extraBuilder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetExtra(map[string]interface{}{"name": "bad \xc3\x28"}).
+		ValidateUTF8(httpresponse.UTF8Reject)
+
+	if _, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](extraBuilder); !errors.Is(err, httpresponse.ErrInvalidUTF8) {
+		t.Errorf("Expected ErrInvalidUTF8 for Extra, got %v", err)
+	}
+}
+
+// TestHTTPResponseBuilder_ValidateUTF8_Replace tests that invalid byte sequences are replaced under the UTF8Replace policy.
+func TestHTTPResponseBuilder_ValidateUTF8_Replace(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetMessage("bad \xff byte").
+		SetExtra(map[string]interface{}{"name": "bad \xc3\x28"}).
+		ValidateUTF8(httpresponse.UTF8Replace)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Message != "bad � byte" {
+		t.Errorf("Expected invalid byte to be replaced in Message, got %q", response.Message)
+	}
+	if response.Extra["name"] != "bad �(" {
+		t.Errorf("Expected invalid sequence to be replaced in Extra, got %q", response.Extra["name"])
+	}
+}
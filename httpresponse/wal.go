@@ -0,0 +1,450 @@
+package httpresponse
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WALHeadBytes is the number of leading body bytes kept in each write-ahead record.
+const WALHeadBytes = 256
+
+// WALFlushInterval is the interval at which a FileWAL writes its buffered entries to the file.
+const WALFlushInterval = 100 * time.Millisecond
+
+// walBufferBytes is the size of the buffer of a FileWAL; entries are written to the file when it fills.
+const walBufferBytes = 64 << 10
+
+// WALRecord is the compact description of a response written to a WALSink before it is sent.
+type WALRecord struct {
+	Seq    uint64    `json:"seq"`            // The sequence number assigned by the sink.
+	Time   time.Time `json:"time"`           // When the response was about to be sent.
+	Status int       `json:"status"`         // The HTTP status code.
+	Code   string    `json:"code,omitempty"` // The envelope code, for responses built with this package.
+	Size   int       `json:"size"`           // The size of the body in bytes.
+	Head   string    `json:"head"`           // The first WALHeadBytes bytes of the body.
+}
+
+// WALSink persists write-ahead records. Implementations must be safe for concurrent use.
+type WALSink interface {
+	// Record persists record before the response is sent and returns the sequence number it assigned.
+	Record(record WALRecord) (uint64, error)
+
+	// Commit marks the record with sequence number seq as sent.
+	Commit(seq uint64) error
+}
+
+// walSink holds the WALSink of WriteJSON, wrapped so that the atomic value always stores the same type.
+var walSink atomic.Value // walSinkHolder
+
+// walSinkHolder wraps the registered WALSink; sink is nil while write-ahead logging is disabled.
+type walSinkHolder struct {
+	sink WALSink
+}
+
+func init() {
+	walSink.Store(walSinkHolder{})
+}
+
+// SetWALSink enables write-ahead logging of the responses sent by WriteJSON to sink, or disables it if sink
+// is nil, which is the default. WriteJSON records each response after encoding it and before writing anything,
+// and commits the record once the body is written, so that after a crash the responses that were about to
+// be sent can be told apart from those that were. Sink errors are logged and never fail the write.
+// ServeJSON records its responses as well, including 304 Not Modified responses, with an empty head. The
+// streaming writers (SeqResponse.WriteJSON, StreamNDJSON and StreamSSE) are not recorded: their bodies are
+// produced while they are written, so no record could describe them beforehand.
+//
+// The durability of records depends on the sink: a *FileWAL buffers them, unless its Sync mode is enabled.
+//
+// Parameters:
+//   - sink: The sink, such as a *FileWAL; nil disables write-ahead logging.
+func SetWALSink(sink WALSink) {
+	walSink.Store(walSinkHolder{sink: sink})
+}
+
+// walCoder is implemented by responses exposing their envelope code to write-ahead records.
+type walCoder interface {
+	walCode() string
+}
+
+// walCode returns the code of the response, formatted for write-ahead records.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) walCode() string {
+	return fmt.Sprint(httpResponseOptions.Code)
+}
+
+// walRecord records the response about to be written to the registered sink, if any. It returns the
+// function committing the record, which does nothing if there is no sink or recording failed.
+func walRecord(responder Responder, status int, body []byte) func() {
+
+	sink := walSink.Load().(walSinkHolder).sink
+	if sink == nil {
+		return func() {}
+	}
+
+	deps := depsOf(responder)
+
+	record := WALRecord{Time: deps.Clock(), Status: status, Size: len(body)}
+	if coder, ok := responder.(walCoder); ok {
+		record.Code = coder.walCode()
+	}
+	if len(body) > WALHeadBytes {
+		record.Head = string(body[:WALHeadBytes])
+	} else {
+		record.Head = string(body)
+	}
+
+	seq, err := sink.Record(record)
+	if err != nil {
+		deps.logf("httpresponse: write-ahead record: %v", err)
+		return func() {}
+	}
+
+	return func() {
+		if err := sink.Commit(seq); err != nil {
+			deps.logf("httpresponse: write-ahead commit: %v", err)
+		}
+	}
+}
+
+// walEntry is a line of a FileWAL: a record, or the commit of the record with sequence number Commit.
+type walEntry struct {
+	*WALRecord
+	Commit uint64 `json:"commit,omitempty"`
+}
+
+// FileWAL is a WALSink appending JSON lines to a file, a record per line followed later by a line committing
+// it, rotated to a single backup once it exceeds a size. It is safe for concurrent use.
+//
+// Entries are appended to a buffer, written to the file once it fills, every WALFlushInterval, and by Flush
+// and Close, so that recording costs a single buffered append. Entries of the last interval before a crash may
+// thus be lost: a lost commit makes its record appear uncommitted, while a lost record leaves no trace of its
+// response. Sync mode (see SetSync) writes each record to the file and syncs it before Record returns, so
+// that it outlives a crash of the process or the machine, at the cost of a disk write per response.
+type FileWAL struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	sync     bool
+	file     *os.File
+	buf      *bufio.Writer
+	size     int64
+	seq      uint64
+	stop     chan struct{}
+}
+
+// NewFileWAL opens the write-ahead log at path, creating it if needed, and continues the sequence numbers of
+// the entries it and its backup already hold.
+//
+// Parameters:
+//   - path: The path of the log; the backup is path + ".1".
+//   - maxBytes: The size beyond which the log is rotated to the backup, replacing the previous backup;
+//     values less than or equal to zero disable rotation.
+//
+// Returns:
+//   - *FileWAL: The log.
+//   - error: An error if the log cannot be read or opened.
+func NewFileWAL(path string, maxBytes int64) (*FileWAL, error) {
+
+	fileWAL := &FileWAL{path: path, maxBytes: maxBytes}
+
+	var complete int64
+	for _, name := range []string{path + ".1", path} {
+		var err error
+		complete, err = readWAL(name, func(entry walEntry) {
+			if entry.WALRecord != nil && entry.Seq > fileWAL.seq {
+				fileWAL.seq = entry.Seq
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Drop the torn last line left by a crash mid-append, so that the next entry starts on a line of its own
+	if info, err := os.Stat(path); err == nil && info.Size() > complete {
+		if err := os.Truncate(path, complete); err != nil {
+			return nil, fmt.Errorf("httpresponse: repair write-ahead log: %w", err)
+		}
+	}
+
+	if err := fileWAL.open(); err != nil {
+		return nil, err
+	}
+
+	fileWAL.stop = make(chan struct{})
+	go fileWAL.flushEvery(WALFlushInterval, fileWAL.stop)
+
+	return fileWAL, nil
+}
+
+// SetSync enables or disables Sync mode, in which each record is written to the file and synced to the disk
+// before Record returns. It is disabled by default.
+//
+// Parameters:
+//   - sync: A boolean enabling (true) or disabling (false) Sync mode.
+//
+// Returns:
+//   - *FileWAL: The same log, for chaining.
+func (fileWAL *FileWAL) SetSync(sync bool) *FileWAL {
+
+	fileWAL.mu.Lock()
+	defer fileWAL.mu.Unlock()
+
+	fileWAL.sync = sync
+
+	return fileWAL
+}
+
+// flushEvery flushes the log every interval until stop is closed.
+func (fileWAL *FileWAL) flushEvery(interval time.Duration, stop <-chan struct{}) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := fileWAL.Flush(); err != nil && !errors.Is(err, os.ErrClosed) {
+				logf("httpresponse: flush write-ahead log: %v", err)
+			}
+		}
+	}
+}
+
+// open opens the log file for appending.
+func (fileWAL *FileWAL) open() error {
+
+	file, err := os.OpenFile(fileWAL.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("httpresponse: open write-ahead log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("httpresponse: open write-ahead log: %w", err)
+	}
+
+	fileWAL.file = file
+	fileWAL.buf = bufio.NewWriterSize(file, walBufferBytes)
+	fileWAL.size = info.Size()
+
+	return nil
+}
+
+// Record appends record to the log buffer with the next sequence number, and in Sync mode writes and syncs
+// it to the file.
+//
+// Parameters:
+//   - record: The record; its Seq is ignored.
+//
+// Returns:
+//   - uint64: The sequence number of the record.
+//   - error: An error if the record cannot be written.
+func (fileWAL *FileWAL) Record(record WALRecord) (uint64, error) {
+
+	fileWAL.mu.Lock()
+	defer fileWAL.mu.Unlock()
+
+	fileWAL.seq++
+	record.Seq = fileWAL.seq
+
+	if err := fileWAL.append(walEntry{WALRecord: &record}); err != nil {
+		return 0, err
+	}
+
+	if fileWAL.sync {
+		if err := fileWAL.buf.Flush(); err != nil {
+			return 0, err
+		}
+		if err := fileWAL.file.Sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	return record.Seq, nil
+}
+
+// Commit appends the commit of the record with sequence number seq to the log buffer.
+//
+// Parameters:
+//   - seq: The sequence number returned by Record.
+//
+// Returns:
+//   - error: An error if the commit cannot be written.
+func (fileWAL *FileWAL) Commit(seq uint64) error {
+
+	fileWAL.mu.Lock()
+	defer fileWAL.mu.Unlock()
+
+	return fileWAL.append(walEntry{Commit: seq})
+}
+
+// append appends entry as a line, rotating the log first if it would exceed maxBytes.
+func (fileWAL *FileWAL) append(entry walEntry) error {
+
+	if fileWAL.file == nil {
+		return os.ErrClosed
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if fileWAL.maxBytes > 0 && fileWAL.size > 0 && fileWAL.size+int64(len(line)) > fileWAL.maxBytes {
+		if err := fileWAL.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fileWAL.buf.Write(line)
+	fileWAL.size += int64(n)
+
+	return err
+}
+
+// rotate moves the log to its backup and opens a new log.
+func (fileWAL *FileWAL) rotate() error {
+
+	if err := fileWAL.close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(fileWAL.path, fileWAL.path+".1"); err != nil {
+		return fmt.Errorf("httpresponse: rotate write-ahead log: %w", err)
+	}
+
+	return fileWAL.open()
+}
+
+// Flush writes the buffered entries to the file.
+//
+// Returns:
+//   - error: An error if the entries cannot be written.
+func (fileWAL *FileWAL) Flush() error {
+
+	fileWAL.mu.Lock()
+	defer fileWAL.mu.Unlock()
+
+	if fileWAL.file == nil {
+		return os.ErrClosed
+	}
+
+	return fileWAL.buf.Flush()
+}
+
+// Close flushes and closes the log, and stops its periodic flushes.
+//
+// Returns:
+//   - error: An error if the log cannot be flushed or closed.
+func (fileWAL *FileWAL) Close() error {
+
+	fileWAL.mu.Lock()
+	defer fileWAL.mu.Unlock()
+
+	if fileWAL.stop != nil {
+		close(fileWAL.stop)
+		fileWAL.stop = nil
+	}
+
+	if fileWAL.file == nil {
+		return nil
+	}
+
+	return fileWAL.close()
+}
+
+// close flushes and closes the log file.
+func (fileWAL *FileWAL) close() error {
+
+	err := fileWAL.buf.Flush()
+	if closeErr := fileWAL.file.Close(); err == nil {
+		err = closeErr
+	}
+	fileWAL.file = nil
+
+	return err
+}
+
+// ReadUncommittedWAL returns the records of the write-ahead log at path, and of its backup, that were never
+// committed, in sequence order: the responses that were about to be sent when the process stopped, or whose
+// write failed. It is meant to run at start-up, before the log is opened again.
+//
+// Parameters:
+//   - path: The path of the log, as passed to NewFileWAL.
+//
+// Returns:
+//   - []WALRecord: The uncommitted records.
+//   - error: An error if the log cannot be read.
+func ReadUncommittedWAL(path string) ([]WALRecord, error) {
+
+	var records []WALRecord
+	committed := make(map[uint64]bool)
+
+	for _, name := range []string{path + ".1", path} {
+		_, err := readWAL(name, func(entry walEntry) {
+			if entry.WALRecord != nil {
+				records = append(records, *entry.WALRecord)
+			} else {
+				committed[entry.Commit] = true
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	uncommitted := records[:0]
+	for _, record := range records {
+		if !committed[record.Seq] {
+			uncommitted = append(uncommitted, record)
+		}
+	}
+
+	return uncommitted, nil
+}
+
+// readWAL calls fn with each entry of the log file at name, which may not exist, and returns the size of its
+// complete lines. Lines that cannot be decoded, such as the torn last line left by a crash mid-append, are
+// skipped.
+func readWAL(name string, fn func(walEntry)) (int64, error) {
+
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("httpresponse: read write-ahead log: %w", err)
+	}
+	defer file.Close()
+
+	var complete int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return complete, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("httpresponse: read write-ahead log: %w", err)
+		}
+		complete += int64(len(line))
+
+		var entry walEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			logf("httpresponse: skipping undecodable write-ahead log line in %s: %v", name, err)
+			continue
+		}
+		fn(entry)
+	}
+}
@@ -0,0 +1,361 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// eventLog records the order of write-ahead and network events.
+type eventLog []string
+
+// loggingSink is a WALSink appending its calls to an eventLog.
+type loggingSink struct {
+	events  *eventLog
+	records []httpresponse.WALRecord
+}
+
+func (l *loggingSink) Record(record httpresponse.WALRecord) (uint64, error) {
+	*l.events = append(*l.events, "record")
+	l.records = append(l.records, record)
+	return uint64(len(l.records)), nil
+}
+
+func (l *loggingSink) Commit(seq uint64) error {
+	*l.events = append(*l.events, "commit")
+	return nil
+}
+
+// loggingWriter is an http.ResponseWriter appending its calls to an eventLog, failing writes if err is set.
+type loggingWriter struct {
+	*httptest.ResponseRecorder
+	events *eventLog
+	err    error
+}
+
+func (l *loggingWriter) WriteHeader(status int) {
+	*l.events = append(*l.events, "header")
+	l.ResponseRecorder.WriteHeader(status)
+}
+
+func (l *loggingWriter) Write(p []byte) (int, error) {
+	*l.events = append(*l.events, "write")
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.ResponseRecorder.Write(p)
+}
+
+// TestWriteJSON_WALOrdering tests that the response is recorded before it is written and committed after.
+func TestWriteJSON_WALOrdering(t *testing.T) {
+	var events eventLog
+	sink := &loggingSink{events: &events}
+	httpresponse.SetWALSink(sink)
+	defer httpresponse.SetWALSink(nil)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(201).SetData(strings.Repeat("x", 1000)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	w := &loggingWriter{ResponseRecorder: httptest.NewRecorder(), events: &events}
+	if err := httpresponse.WriteJSON(w, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(events, ",") != "record,header,write,commit" {
+		t.Errorf("Expected record,header,write,commit, got %v", events)
+	}
+
+	record := sink.records[0]
+	if record.Status != http.StatusCreated || record.Code != "201" || record.Size != w.Body.Len() {
+		t.Errorf("Expected status 201, code 201 and size %d, got %+v", w.Body.Len(), record)
+	}
+	if len(record.Head) != httpresponse.WALHeadBytes || !strings.HasPrefix(w.Body.String(), record.Head) {
+		t.Errorf("Expected the first %d bytes of the body, got %q", httpresponse.WALHeadBytes, record.Head)
+	}
+}
+
+// TestWriteJSON_WALWriteFailure tests that a response whose write fails is recorded but not committed.
+func TestWriteJSON_WALWriteFailure(t *testing.T) {
+	var events eventLog
+	httpresponse.SetWALSink(&loggingSink{events: &events})
+	defer httpresponse.SetWALSink(nil)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(200).SetData("ok"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	broken := errors.New("connection reset")
+	w := &loggingWriter{ResponseRecorder: httptest.NewRecorder(), events: &events, err: broken}
+	if err := httpresponse.WriteJSON(w, response); !errors.Is(err, broken) {
+		t.Fatalf("Expected the write error, got %v", err)
+	}
+
+	if strings.Join(events, ",") != "record,header,write" {
+		t.Errorf("Expected record,header,write, got %v", events)
+	}
+}
+
+// TestServeJSON_WALNotModified tests that ServeJSON records and commits the 304 responses it writes itself.
+func TestServeJSON_WALNotModified(t *testing.T) {
+	var events eventLog
+	sink := &loggingSink{events: &events}
+	httpresponse.SetWALSink(sink)
+	defer httpresponse.SetWALSink(nil)
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetData("resource").Conditional("v1", time.Time{}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	r.Header.Set("If-None-Match", `"v1"`)
+
+	w := &loggingWriter{ResponseRecorder: httptest.NewRecorder(), events: &events}
+	if err := httpresponse.ServeJSON(w, r, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(events, ",") != "record,header,commit" {
+		t.Errorf("Expected record,header,commit, got %v", events)
+	}
+	if record := sink.records[0]; record.Status != http.StatusNotModified || record.Size != 0 {
+		t.Errorf("Expected an empty 304 record, got %+v", record)
+	}
+}
+
+// TestWriteJSON_WALDisabled tests that nothing is recorded by default.
+func TestWriteJSON_WALDisabled(t *testing.T) {
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(200).SetData("ok"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var events eventLog
+	w := &loggingWriter{ResponseRecorder: httptest.NewRecorder(), events: &events}
+	if err := httpresponse.WriteJSON(w, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(events, ",") != "header,write" {
+		t.Errorf("Expected header,write, got %v", events)
+	}
+}
+
+// TestFileWAL_Recovery tests that the records left uncommitted, including across a restart, are recovered.
+func TestFileWAL_Recovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.wal")
+
+	wal, err := httpresponse.NewFileWAL(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	httpresponse.SetWALSink(wal)
+	defer httpresponse.SetWALSink(nil)
+
+	sent, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(200).SetData("sent"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lost, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](
+		httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(500).SetData("lost"),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := httpresponse.WriteJSON(httptest.NewRecorder(), sent); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	w := &loggingWriter{ResponseRecorder: httptest.NewRecorder(), events: new(eventLog), err: errors.New("broken pipe")}
+	_ = httpresponse.WriteJSON(w, lost)
+
+	// A crash between recording and committing: the record is written on Close, the commit never.
+	if _, err := wal.Record(httpresponse.WALRecord{Status: 404, Code: "404", Head: "in flight"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	uncommitted, err := httpresponse.ReadUncommittedWAL(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(uncommitted) != 2 || uncommitted[0].Code != "500" || uncommitted[1].Head != "in flight" {
+		t.Fatalf("Expected the failed and in-flight records, got %+v", uncommitted)
+	}
+	if uncommitted[0].Seq != 2 || uncommitted[1].Seq != 3 {
+		t.Errorf("Expected sequence numbers 2 and 3, got %d and %d", uncommitted[0].Seq, uncommitted[1].Seq)
+	}
+
+	reopened, err := httpresponse.NewFileWAL(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer reopened.Close()
+
+	if seq, err := reopened.Record(httpresponse.WALRecord{Status: 200}); err != nil || seq != 4 {
+		t.Errorf("Expected the sequence to continue at 4, got %d, %v", seq, err)
+	}
+}
+
+// TestFileWAL_Buffering tests that records are buffered and written periodically, and that Sync mode writes
+// each record before Record returns.
+func TestFileWAL_Buffering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.wal")
+
+	wal, err := httpresponse.NewFileWAL(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer wal.Close()
+
+	size := func() int64 {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return info.Size()
+	}
+
+	if _, err := wal.Record(httpresponse.WALRecord{Status: 200}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := size(); n != 0 {
+		t.Errorf("Expected the record to be buffered, got %d bytes in the file", n)
+	}
+
+	deadline := time.Now().Add(10 * httpresponse.WALFlushInterval)
+	for size() == 0 && time.Now().Before(deadline) {
+		time.Sleep(httpresponse.WALFlushInterval / 4)
+	}
+	flushed := size()
+	if flushed == 0 {
+		t.Fatal("Expected the record to be flushed periodically")
+	}
+
+	if _, err := wal.SetSync(true).Record(httpresponse.WALRecord{Status: 500}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := size(); n <= flushed {
+		t.Errorf("Expected the record to be written in Sync mode, got %d bytes in the file", n)
+	}
+}
+
+// TestFileWAL_Rotation tests that the log is rotated to its backup once it exceeds its size and that
+// recovery reads both files.
+func TestFileWAL_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.wal")
+
+	wal, err := httpresponse.NewFileWAL(path, 512)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer wal.Close()
+
+	for i := 0; i < 10; i++ {
+		seq, err := wal.Record(httpresponse.WALRecord{Status: 200, Head: strings.Repeat("x", 64)})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if seq%2 == 0 {
+			if err := wal.Commit(seq); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+	}
+	if err := wal.Flush(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{path, path + ".1"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Expected %s to exist, got %v", name, err)
+		}
+		if info.Size() > 512 {
+			t.Errorf("Expected %s to hold at most 512 bytes, got %d", name, info.Size())
+		}
+	}
+
+	uncommitted, err := httpresponse.ReadUncommittedWAL(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(uncommitted) == 0 {
+		t.Fatal("Expected uncommitted records")
+	}
+	for _, record := range uncommitted {
+		if record.Seq%2 == 0 {
+			t.Errorf("Expected only odd sequence numbers, got %d", record.Seq)
+		}
+	}
+}
+
+// TestFileWAL_TornTail tests that the torn last line left by a crash mid-append is dropped when the log is
+// reopened, so that later entries stay readable, and that undecodable lines are skipped.
+func TestFileWAL_TornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.wal")
+
+	wal, err := httpresponse.NewFileWAL(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := wal.Record(httpresponse.WALRecord{Status: 200, Head: "first"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := file.WriteString("not json\n" + `{"seq":2,"time":"2026-10-`); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	file.Close()
+
+	reopened, err := httpresponse.NewFileWAL(path, 0)
+	if err != nil {
+		t.Fatalf("Expected the log to reopen, got %v", err)
+	}
+	if seq, err := reopened.Record(httpresponse.WALRecord{Status: 500, Head: "second"}); err != nil || seq != 2 {
+		t.Errorf("Expected sequence number 2, got %d, %v", seq, err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	uncommitted, err := httpresponse.ReadUncommittedWAL(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(uncommitted) != 2 || uncommitted[0].Head != "first" || uncommitted[1].Head != "second" {
+		t.Errorf("Expected both records, got %+v", uncommitted)
+	}
+}
@@ -0,0 +1,275 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contentTypeJSON is the media type of the JSON encodings produced by this package.
+const contentTypeJSON = "application/json"
+
+// Responder is the minimal interface a response must implement to be written by WriteJSON.
+// HTTPResponseOptions implements it, and custom response structs can implement it directly
+// or be wrapped with WrapResponder.
+type Responder interface {
+	// StatusCode returns the HTTP status code of the response.
+	StatusCode() int
+
+	// Body returns the content type and the encoded body of the response.
+	Body() (contentType string, body []byte, err error)
+}
+
+// StatusCode returns the HTTP status code of the response. An int Code within the HTTP status range
+// (100-599) is used as is; otherwise the status is 200 for successful responses and 500 for failures.
+//
+// Returns:
+//   - int: The HTTP status code to write.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) StatusCode() int {
+
+	if code, ok := any(httpResponseOptions.Code).(int); ok && code >= 100 && code <= 599 {
+		return code
+	}
+
+	if httpResponseOptions.Success {
+		return http.StatusOK
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Body returns the JSON encoding of the response produced by MarshalJSON.
+//
+// Returns:
+//   - string: The content type of the body, "application/json".
+//   - []byte: The encoded body.
+//   - error: An error if marshaling fails.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) Body() (string, []byte, error) {
+
+	body, err := httpResponseOptions.MarshalJSON()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return contentTypeJSON, body, nil
+}
+
+// Header returns the HTTP headers sent along with the response by the writers, including the
+// Cache-Control (and, if requested, Expires) headers rendered from the caching intent and the
+// Vary contributions of the response.
+//
+// Returns:
+//   - http.Header: The response headers; nil if none were set.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) Header() http.Header {
+
+	if httpResponseOptions.Cache == nil && len(httpResponseOptions.Vary) == 0 {
+		return httpResponseOptions.Headers
+	}
+
+	// Render into a copy, so that Expires reflects the time of each write
+	header := httpResponseOptions.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	if httpResponseOptions.Cache != nil {
+		header.Set("Cache-Control", httpResponseOptions.Cache.CacheControl())
+		if httpResponseOptions.CacheExpires {
+			header.Set("Expires", httpResponseOptions.Cache.Expires(httpResponseOptions.deps().Clock()))
+		}
+	}
+
+	for _, field := range httpResponseOptions.Vary {
+		header.Add("Vary", field)
+	}
+	normalizeVary(header)
+
+	return header
+}
+
+// setHeader sets a response header, allocating the header map on first use.
+func (httpResponseOptions *HTTPResponseOptions[C, D, E, T]) setHeader(key, value string) {
+
+	if httpResponseOptions.Headers == nil {
+		httpResponseOptions.Headers = make(http.Header)
+	}
+
+	httpResponseOptions.Headers.Set(key, value)
+}
+
+// valueResponder adapts an arbitrary value and a status code to the Responder interface.
+type valueResponder struct {
+	status int
+	value  any
+}
+
+// WrapResponder adapts an arbitrary value, such as an existing response struct, to the Responder interface.
+// The value is encoded with encoding/json.
+//
+// Parameters:
+//   - status: The HTTP status code of the response.
+//   - value: The value to encode as the response body.
+//
+// Returns:
+//   - Responder: A Responder writing value as JSON with the given status.
+func WrapResponder(status int, value any) Responder {
+	return &valueResponder{status: status, value: value}
+}
+
+// StatusCode returns the wrapped status code.
+func (valueResponder *valueResponder) StatusCode() int {
+	return valueResponder.status
+}
+
+// Body returns the JSON encoding of the wrapped value.
+func (valueResponder *valueResponder) Body() (string, []byte, error) {
+
+	body, err := json.Marshal(valueResponder.value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return contentTypeJSON, body, nil
+}
+
+// WriteJSON encodes the responder and writes it to w with its content type and status code.
+// If the responder also provides headers through a Header() http.Header method, as HTTPResponseOptions does,
+// they are added to w before the status is written. Vary values contributed by the responder and by
+// earlier handlers or middleware are merged into a single deduplicated, sorted Vary header. The body is encoded before anything is written,
+// so an encoding error leaves w untouched and the caller free to write a different response, and its length is sent as Content-Length.
+// Encoding and write failures are logged with a preview of the response, as produced by Preview.
+// With a sink set by SetWALSink, the response is recorded to it before anything is written and committed once the body is written.
+//
+// Parameters:
+//   - w: The destination http.ResponseWriter.
+//   - responder: The response to write, such as *HTTPResponseOptions or a value wrapped with WrapResponder.
+//
+// Returns:
+//   - error: An error if encoding the body or writing it fails.
+func WriteJSON(w http.ResponseWriter, responder Responder) error {
+
+	responder, err := snapshotOf(responder)
+	if err != nil {
+		logResponseError("encode", responder, err)
+		return err
+	}
+
+	contentType, body, err := responder.Body()
+	if err != nil {
+		logResponseError("encode", responder, err)
+		return err
+	}
+
+	copyHeaders(w, responder)
+	normalizeVary(w.Header())
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+	markEnvelope(w, responder)
+	commit := walRecord(responder, responder.StatusCode(), body)
+
+	w.WriteHeader(responder.StatusCode())
+
+	if _, err = w.Write(body); err != nil {
+		logResponseError("write", responder, err)
+		return err
+	}
+
+	commit()
+	recordWrite()
+
+	return nil
+}
+
+// snapshotter is implemented by responders whose content may change between calls, such as Memoized.
+// The writers read the status, headers and body from the single snapshot it returns.
+type snapshotter interface {
+	snapshot() (Responder, error)
+}
+
+// snapshotOf returns the snapshot of responder if it is a snapshotter, or else responder itself.
+func snapshotOf(responder Responder) (Responder, error) {
+
+	if snapshotter, ok := responder.(snapshotter); ok {
+		return snapshotter.snapshot()
+	}
+
+	return responder, nil
+}
+
+// logPreviewBytes caps the preview of a response logged when writing it fails.
+const logPreviewBytes = 512
+
+// previewer is implemented by responses that summarize themselves for logs, such as HTTPResponseOptions.
+type previewer interface {
+	Preview(maxBytes int) string
+}
+
+// logResponseError logs the failure of action on responder, with a size-capped preview of responders that
+// provide one, so that logs show which envelope failed without printing its whole body.
+func logResponseError(action string, responder Responder, err error) {
+
+	deps := depsOf(responder)
+
+	if previewer, ok := responder.(previewer); ok {
+		deps.logf("httpresponse: %s response [%s]: %v", action, previewer.Preview(logPreviewBytes), err)
+		return
+	}
+
+	deps.logf("httpresponse: %s response: %v", action, err)
+}
+
+// copyHeaders adds the headers provided by responder, if any, to w.
+func copyHeaders(w http.ResponseWriter, responder Responder) {
+
+	headerResponder, ok := responder.(interface{ Header() http.Header })
+	if !ok {
+		return
+	}
+
+	for k, values := range headerResponder.Header() {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+}
+
+// normalizeVary merges all Vary values of header into a single deduplicated, sorted value of canonical
+// header names. A "*" value subsumes all others.
+func normalizeVary(header http.Header) {
+
+	values := header.Values("Vary")
+	if len(values) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	fields := make([]string, 0, len(values))
+
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			if field == "*" {
+				header.Set("Vary", "*")
+				return
+			}
+
+			field = http.CanonicalHeaderKey(field)
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	sort.Strings(fields)
+
+	header.Set("Vary", strings.Join(fields, ", "))
+}
@@ -0,0 +1,212 @@
+package httpresponse_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// customResponder is a response type owned by another team, implementing Responder directly.
+type customResponder struct {
+	status int
+	body   string
+	err    error
+}
+
+func (c *customResponder) StatusCode() int {
+	return c.status
+}
+
+func (c *customResponder) Body() (string, []byte, error) {
+	return "text/plain", []byte(c.body), c.err
+}
+
+// TestWriteJSON_HTTPResponseOptions tests that WriteJSON writes a built response with its status code.
+func TestWriteJSON_HTTPResponseOptions(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetCode(201).
+		SetData("created")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %v", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %v", rec.Header().Get("Content-Type"))
+	}
+	if !contains(rec.Body.String(), `"data":"created"`) {
+		t.Errorf("Expected body to contain the data, got %v", rec.Body.String())
+	}
+}
+
+// TestHTTPResponseOptions_StatusCode tests the status code derived from Code and Success.
+func TestHTTPResponseOptions_StatusCode(t *testing.T) {
+	cases := []struct {
+		response *httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+		expected int
+	}{
+		{&httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{Success: true, Code: 404}, 404},
+		{&httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{Success: true, Code: 10001}, 200},
+		{&httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{Success: false}, 500},
+	}
+
+	for _, c := range cases {
+		if status := c.response.StatusCode(); status != c.expected {
+			t.Errorf("Expected status %d, got %d", c.expected, status)
+		}
+	}
+
+	stringCoded := &httpresponse.HTTPResponseOptions[string, string, map[string]interface{}, int]{Success: true, Code: "404"}
+	if status := stringCoded.StatusCode(); status != 200 {
+		t.Errorf("Expected status 200 for a string code, got %d", status)
+	}
+}
+
+// TestWriteJSON_CustomResponder tests that a custom Responder flows through WriteJSON.
+func TestWriteJSON_CustomResponder(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, &customResponder{status: 418, body: "teapot"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("Expected status 418, got %v", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("Expected Content-Type text/plain, got %v", rec.Header().Get("Content-Type"))
+	}
+	if rec.Body.String() != "teapot" {
+		t.Errorf("Expected body 'teapot', got %v", rec.Body.String())
+	}
+}
+
+// TestWriteJSON_BodyError tests that WriteJSON writes nothing when the body cannot be encoded.
+func TestWriteJSON_BodyError(t *testing.T) {
+	bodyErr := errors.New("encode failed")
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, &customResponder{status: 200, err: bodyErr}); !errors.Is(err, bodyErr) {
+		t.Fatalf("Expected encode error, got %v", err)
+	}
+
+	if rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+		t.Errorf("Expected nothing to be written, got headers %v and body %v", rec.Header(), rec.Body.String())
+	}
+}
+
+// TestWriteJSON_EncodeErrorPreview tests that an encoding failure is logged with a capped preview of the
+// response rather than its body.
+func TestWriteJSON_EncodeErrorPreview(t *testing.T) {
+	var logged []string
+	httpresponse.SetLogger(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	defer httpresponse.SetLogger(nil)
+
+	response := &httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]{
+		Message: "listing",
+		Data:    make([]string, 100000),
+		Extra:   map[string]interface{}{"callback": func() {}},
+	}
+
+	if err := httpresponse.WriteJSON(httptest.NewRecorder(), response); !errors.Is(err, httpresponse.ErrUnserializable) {
+		t.Fatalf("Expected ErrUnserializable, got %v", err)
+	}
+
+	if len(logged) != 1 || !strings.Contains(logged[0], response.Preview(512)) {
+		t.Fatalf("Expected one log line with the preview, got %v", logged)
+	}
+	if !strings.Contains(logged[0], "data=[]string(len=100000)") || len(logged[0]) > 1024 {
+		t.Errorf("Expected a short log line summarizing the data, got %q", logged[0])
+	}
+}
+
+// TestWrapResponder tests that WrapResponder adapts an arbitrary struct with a status.
+func TestWrapResponder(t *testing.T) {
+	type legacyResponse struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, httpresponse.WrapResponder(400, legacyResponse{Error: "bad input"})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %v", rec.Code)
+	}
+	if rec.Body.String() != `{"ok":false,"error":"bad input"}` {
+		t.Errorf(`Expected body {"ok":false,"error":"bad input"}, got %v`, rec.Body.String())
+	}
+}
+
+// TestWriteJSON_Vary tests that Vary contributions are merged into a deduplicated, sorted header.
+func TestWriteJSON_Vary(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		AddVary("accept-language", "Accept").
+		AddVary("Authorization, Accept")
+
+	response, err := rpsutil.Build[httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]](builder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A compression middleware contributed Accept-Encoding before the handler wrote
+	rec := httptest.NewRecorder()
+	rec.Header().Add("Vary", "Accept-Encoding")
+
+	if err := httpresponse.WriteJSON(rec, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values := rec.Header().Values("Vary")
+	if len(values) != 1 || values[0] != "Accept, Accept-Encoding, Accept-Language, Authorization" {
+		t.Errorf("Expected a single merged Vary header, got %v", values)
+	}
+}
+
+// TestWriteJSON_VaryWildcard tests that a wildcard Vary subsumes all other entries.
+func TestWriteJSON_VaryWildcard(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]{
+		Success: true,
+		Vary:    []string{"Accept", "*"},
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if vary := rec.Header().Values("Vary"); len(vary) != 1 || vary[0] != "*" {
+		t.Errorf("Expected Vary to be *, got %v", vary)
+	}
+}
+
+// TestWriteJSON_ContentLength tests that buffered writes send the length of the body.
+func TestWriteJSON_ContentLength(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(recorder, httpresponse.WrapResponder(http.StatusOK, map[string]string{"a": "b"})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got, want := recorder.Header().Get("Content-Length"), strconv.Itoa(recorder.Body.Len()); got != want {
+		t.Errorf("Expected Content-Length %s, got %q", want, got)
+	}
+}
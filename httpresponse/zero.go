@@ -0,0 +1,21 @@
+package httpresponse
+
+import "reflect"
+
+// isZero reports whether v is the zero value of its type, looking through interfaces, as SetNullAs
+// and Err require. Nil slices, maps, pointers, channels, functions and interfaces are zero, while non-nil
+// empty slices and maps are not. Arrays and structs are zero when all of their elements or fields are zero.
+func isZero[V any](v V) bool {
+
+	rv := reflect.ValueOf(&v).Elem()
+
+	// Look through interfaces, so that an interface holding a zero value is zero
+	for rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+
+	return rv.IsZero()
+}
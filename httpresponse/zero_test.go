@@ -0,0 +1,74 @@
+package httpresponse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// TestSetNullAs_ZeroKinds tests which Data values of many kinds are substituted as zero by SetNullAs.
+func TestSetNullAs_ZeroKinds(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	type tagged struct {
+		Name string
+		Tags []string
+	}
+
+	var nilPointer *point
+	var nilSlice []int
+	var nilMap map[string]int
+	var nilError error
+
+	tests := []struct {
+		name string
+		v    any
+		want bool
+	}{
+		{"nil", nil, true},
+		{"zero int", 0, true},
+		{"int", -1, false},
+		{"zero uint64", uint64(0), true},
+		{"zero float", 0.0, true},
+		{"float", 0.5, false},
+		{"false", false, true},
+		{"true", true, false},
+		{"empty string", "", true},
+		{"string", "x", false},
+		{"nil slice", nilSlice, true},
+		{"empty slice", []int{}, false},
+		{"slice", []int{0}, false},
+		{"nil map", nilMap, true},
+		{"empty map", map[string]int{}, false},
+		{"nil pointer", nilPointer, true},
+		{"pointer to zero", &point{}, false},
+		{"nil error", nilError, true},
+		{"zero struct", point{}, true},
+		{"struct", point{Y: 1}, false},
+		{"struct with empty slice", tagged{Tags: []string{}}, false},
+		{"zero array", [2]int{}, true},
+		{"array", [2]int{0, 1}, false},
+		{"zero time", time.Time{}, true},
+		{"time", time.Unix(0, 0), false},
+		{"interface holding zero", any(any(0)), true},
+	}
+
+	for _, tt := range tests {
+		response := &httpresponse.HTTPResponseOptions[int, any, map[string]interface{}, int]{
+			Success: true,
+			Data:    tt.v,
+			NullAs:  "zero",
+		}
+
+		body, err := response.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", tt.name, err)
+		}
+		if got := strings.Contains(string(body), `"data":"zero"`); got != tt.want {
+			t.Errorf("Expected %s substituted %v, got %s", tt.name, tt.want, body)
+		}
+	}
+}
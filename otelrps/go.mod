@@ -0,0 +1,13 @@
+module github.com/zeroxsolutions/go-rps/otelrps
+
+go 1.25.0
+
+require (
+	github.com/zeroxsolutions/go-rps v0.0.0
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require github.com/cespare/xxhash/v2 v2.3.0 // indirect
+
+replace github.com/zeroxsolutions/go-rps => ../
@@ -0,0 +1,98 @@
+// Package otelrps ties httpresponse envelopes to OpenTelemetry traces. It lives in its own module so that
+// the OpenTelemetry dependency stays optional for users of the core packages.
+package otelrps
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+const (
+	// KeyTraceID is the Extra key under which the trace ID of the active span is stored.
+	KeyTraceID = "trace_id"
+
+	// KeySpanID is the Extra key under which the span ID of the active span is stored.
+	KeySpanID = "span_id"
+
+	// EventName is the name of the span event recorded by RecordEvent.
+	EventName = "httpresponse"
+)
+
+// SetSpanContext adds the trace and span IDs of the span active in ctx to the Extra of the response,
+// under the "trace_id" and "span_id" keys, so that responses can be correlated with traces end-to-end.
+// Existing Extra entries are kept. If ctx carries no valid span context, the response is left unchanged.
+//
+// Parameters:
+//   - httpResponseBuilder: The builder to add the IDs to.
+//   - ctx: The context carrying the active span.
+//
+// Returns:
+//   - *httpresponse.HTTPResponseBuilder: The same builder, for chaining.
+func SetSpanContext[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](httpResponseBuilder *httpresponse.HTTPResponseBuilder[C, D, E, T], ctx context.Context) *httpresponse.HTTPResponseBuilder[C, D, E, T] {
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return httpResponseBuilder
+	}
+
+	traceID := spanContext.TraceID().String()
+	spanID := spanContext.SpanID().String()
+
+	httpResponseBuilder.AddOpt(func(args *httpresponse.HTTPResponseOptions[C, D, E, T]) error {
+
+		extra := make(E, len(args.Extra)+2)
+		for k, v := range args.Extra {
+			extra[k] = v
+		}
+		extra[KeyTraceID] = traceID
+		extra[KeySpanID] = spanID
+		args.Extra = extra
+
+		return nil
+	})
+
+	return httpResponseBuilder
+}
+
+// RecordEvent records an "httpresponse" event on the span active in ctx with the response's success flag,
+// code and HTTP status. It is meant to be called once the response is built, so the final code is recorded.
+// Spans that are not recording are left untouched.
+//
+// Parameters:
+//   - ctx: The context carrying the active span.
+//   - response: The built response; a nil response records nothing.
+func RecordEvent[
+	C int | string,
+	D any,
+	E map[string]any,
+	T int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64,
+](ctx context.Context, response *httpresponse.HTTPResponseOptions[C, D, E, T]) {
+
+	span := trace.SpanFromContext(ctx)
+	if response == nil || !span.IsRecording() {
+		return
+	}
+
+	code := attribute.String("response.code", "")
+	switch c := any(response.Code).(type) {
+	case int:
+		code = attribute.Int("response.code", c)
+	case string:
+		code = attribute.String("response.code", c)
+	}
+
+	span.AddEvent(EventName, trace.WithAttributes(
+		attribute.Bool("response.success", response.Success),
+		code,
+		attribute.Int("http.response.status_code", response.StatusCode()),
+	))
+}
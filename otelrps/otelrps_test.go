@@ -0,0 +1,126 @@
+package otelrps_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/otelrps"
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+type response = httpresponse.HTTPResponseOptions[int, string, map[string]interface{}, int]
+
+// mockSpan is a recording span that captures the events added to it.
+type mockSpan struct {
+	noop.Span
+	spanContext trace.SpanContext
+	events      map[string][]attribute.KeyValue
+}
+
+func (s *mockSpan) SpanContext() trace.SpanContext { return s.spanContext }
+
+func (s *mockSpan) IsRecording() bool { return true }
+
+func (s *mockSpan) AddEvent(name string, options ...trace.EventOption) {
+	config := trace.NewEventConfig(options...)
+	s.events[name] = config.Attributes()
+}
+
+// mockSpanContext returns a valid span context with fixed IDs.
+func mockSpanContext() trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+// TestSetSpanContext tests that the trace and span IDs of the active span are added to Extra.
+func TestSetSpanContext(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), mockSpanContext())
+
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().
+		SetExtra(map[string]interface{}{"request_id": "r-1"})
+
+	built, err := rpsutil.Build[response](otelrps.SetSpanContext(builder, ctx))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if built.Extra[otelrps.KeyTraceID] != "0102030405060708090a0b0c0d0e0f10" {
+		t.Errorf("Expected trace ID, got %v", built.Extra[otelrps.KeyTraceID])
+	}
+	if built.Extra[otelrps.KeySpanID] != "0102030405060708" {
+		t.Errorf("Expected span ID, got %v", built.Extra[otelrps.KeySpanID])
+	}
+	if built.Extra["request_id"] != "r-1" {
+		t.Errorf("Expected existing Extra to be kept, got %v", built.Extra)
+	}
+}
+
+// TestSetSpanContext_ValueCopies tests that the option added to a copy of a builder made by value is not
+// overwritten by the setters of the original.
+func TestSetSpanContext_ValueCopies(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), mockSpanContext())
+
+	// The builder leaves spare capacity in its options, which the copy shares
+	base := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]().SetCode(200).SetMessage("base")
+	copied := *base
+
+	otelrps.SetSpanContext(&copied, ctx)
+	base.SetMessage("changed")
+
+	built, err := rpsutil.Build[response](&copied)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if built.Extra[otelrps.KeyTraceID] == nil || built.Message != "base" {
+		t.Errorf("Expected the trace ID and the base message, got %v and %q", built.Extra, built.Message)
+	}
+}
+
+// TestSetSpanContext_NoSpan tests that a context without a span leaves the response unchanged.
+func TestSetSpanContext_NoSpan(t *testing.T) {
+	builder := httpresponse.HTTPResponse[int, string, map[string]interface{}, int]()
+
+	built, err := rpsutil.Build[response](otelrps.SetSpanContext(builder, context.Background()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if built.Extra != nil {
+		t.Errorf("Expected no Extra, got %v", built.Extra)
+	}
+}
+
+// TestRecordEvent tests that the response code is recorded as an event on the active span.
+func TestRecordEvent(t *testing.T) {
+	span := &mockSpan{spanContext: mockSpanContext(), events: map[string][]attribute.KeyValue{}}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	otelrps.RecordEvent(ctx, &response{Success: false, Code: 404})
+
+	attributes, ok := span.events[otelrps.EventName]
+	if !ok {
+		t.Fatalf("Expected %q event, got %v", otelrps.EventName, span.events)
+	}
+
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range attributes {
+		got[kv.Key] = kv.Value
+	}
+
+	if got["response.code"].AsInt64() != 404 {
+		t.Errorf("Expected response.code 404, got %v", got["response.code"].Emit())
+	}
+	if got["response.success"].AsBool() {
+		t.Error("Expected response.success false")
+	}
+	if got["http.response.status_code"].AsInt64() != 404 {
+		t.Errorf("Expected status 404, got %v", got["http.response.status_code"].Emit())
+	}
+}
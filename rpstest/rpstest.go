@@ -0,0 +1,331 @@
+// Package rpstest provides a fluent expectation builder for asserting the shape of response envelopes
+// in contract tests. Expectations operate on decoded envelopes as well as raw HTTP recorders, and report
+// every failing check with the offending field path and its expected and actual values.
+//
+// Example usage:
+//
+//	rpstest.Expect().Success().Code(200).DataLen(3).ExtraHas("trace_id").Field("data.0.name", "alice").Match(t, rec)
+package rpstest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+)
+
+// coreKeys are the envelope keys that are not considered Extra fields.
+var coreKeys = map[string]bool{
+	httpresponse.KeySuccess: true,
+	httpresponse.KeyMessage: true,
+	httpresponse.KeyCode:    true,
+	httpresponse.KeySubCode: true,
+	httpresponse.KeyData:    true,
+	httpresponse.KeyTotal:   true,
+}
+
+// TB is the subset of testing.TB used to report failures, so that expectations can be verified
+// against fakes as well as *testing.T.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Matcher is a custom check applied to the value found at a field path.
+// It returns a non-nil error describing the mismatch when the value is not acceptable.
+type Matcher func(got any) error
+
+// check is a single expectation evaluated against a decoded envelope.
+type check struct {
+	path    string
+	matcher Matcher
+}
+
+// Expectation is a fluent builder of checks on a response envelope.
+type Expectation struct {
+	status *int
+	checks []check
+}
+
+// Expect creates an empty Expectation.
+//
+// Returns:
+//   - *Expectation: An expectation to which checks can be chained.
+func Expect() *Expectation {
+	return new(Expectation)
+}
+
+// Status expects the HTTP status code of a recorder source. It fails for sources without a status.
+//
+// Parameters:
+//   - status: The expected HTTP status code.
+func (expectation *Expectation) Status(status int) *Expectation {
+
+	expectation.status = &status
+
+	return expectation
+}
+
+// Success expects the envelope's success flag to be true.
+func (expectation *Expectation) Success() *Expectation {
+	return expectation.Field(httpresponse.KeySuccess, true)
+}
+
+// Failure expects the envelope's success flag to be false.
+func (expectation *Expectation) Failure() *Expectation {
+	return expectation.Field(httpresponse.KeySuccess, false)
+}
+
+// Message expects the envelope's message.
+//
+// Parameters:
+//   - message: The expected message.
+func (expectation *Expectation) Message(message string) *Expectation {
+	return expectation.Field(httpresponse.KeyMessage, message)
+}
+
+// Code expects the envelope's code.
+//
+// Parameters:
+//   - code: The expected code, such as 200 or "NOT_FOUND".
+func (expectation *Expectation) Code(code any) *Expectation {
+	return expectation.Field(httpresponse.KeyCode, code)
+}
+
+// Total expects the envelope's total.
+//
+// Parameters:
+//   - total: The expected total.
+func (expectation *Expectation) Total(total any) *Expectation {
+	return expectation.Field(httpresponse.KeyTotal, total)
+}
+
+// DataLen expects the envelope's data to be an array or object with n elements.
+//
+// Parameters:
+//   - n: The expected number of elements.
+func (expectation *Expectation) DataLen(n int) *Expectation {
+	return expectation.Satisfies(httpresponse.KeyData, func(got any) error {
+
+		var length int
+		switch v := got.(type) {
+		case []any:
+			length = len(v)
+		case map[string]any:
+			length = len(v)
+		default:
+			return fmt.Errorf("got %s, want an array or object of length %d", describe(got), n)
+		}
+
+		if length != n {
+			return fmt.Errorf("got length %d, want %d", length, n)
+		}
+
+		return nil
+	})
+}
+
+// ExtraHas expects the envelope to carry the given Extra key at its top level.
+//
+// Parameters:
+//   - key: The expected Extra key; core keys such as "data" are not Extra keys.
+func (expectation *Expectation) ExtraHas(key string) *Expectation {
+	expectation.checks = append(expectation.checks, check{path: key, matcher: func(got any) error {
+
+		if coreKeys[key] {
+			return fmt.Errorf("%q is a core field, not an Extra key", key)
+		}
+
+		return nil
+	}})
+
+	return expectation
+}
+
+// Field expects the value at a dot-separated path to equal want. Path segments address object keys
+// or, when the value is an array, zero-based indexes (e.g. "data.0.name"). Numbers are compared by value,
+// so want may be any Go numeric type.
+//
+// Parameters:
+//   - path: The dot-separated path of the field.
+//   - want: The expected value, compared after a JSON round trip.
+func (expectation *Expectation) Field(path string, want any) *Expectation {
+	return expectation.Satisfies(path, func(got any) error {
+
+		normalized, err := normalize(want)
+		if err != nil {
+			return fmt.Errorf("cannot compare with %#v: %v", want, err)
+		}
+
+		if !reflect.DeepEqual(got, normalized) {
+			return fmt.Errorf("got %s, want %s", describe(got), describe(normalized))
+		}
+
+		return nil
+	})
+}
+
+// Satisfies expects the value at a dot-separated path to satisfy a custom matcher.
+//
+// Parameters:
+//   - path: The dot-separated path of the field, as accepted by Field.
+//   - matcher: The check applied to the decoded value.
+func (expectation *Expectation) Satisfies(path string, matcher Matcher) *Expectation {
+
+	expectation.checks = append(expectation.checks, check{path: path, matcher: matcher})
+
+	return expectation
+}
+
+// Match evaluates every check against source and reports each failure through t.
+//
+// Parameters:
+//   - t: The test to report failures to.
+//   - source: An *httptest.ResponseRecorder, the encoded envelope as []byte or string,
+//     or any value encodable to a JSON object, such as a decoded map or *HTTPResponseOptions.
+//
+// Returns:
+//   - bool: True if all checks passed.
+func (expectation *Expectation) Match(t TB, source any) bool {
+
+	t.Helper()
+
+	envelope, status, err := decode(source)
+	if err != nil {
+		t.Errorf("rpstest: %v", err)
+		return false
+	}
+
+	ok := true
+
+	if expectation.status != nil {
+		switch {
+		case status == nil:
+			t.Errorf("rpstest: status: source %T has no HTTP status", source)
+			ok = false
+		case *status != *expectation.status:
+			t.Errorf("rpstest: status: got %d, want %d", *status, *expectation.status)
+			ok = false
+		}
+	}
+
+	for _, c := range expectation.checks {
+
+		got, err := Lookup(envelope, c.path)
+		if err == nil {
+			err = c.matcher(got)
+		}
+
+		if err != nil {
+			t.Errorf("rpstest: field %q: %v", c.path, err)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Lookup resolves a dot-separated path in a decoded JSON value. Each segment addresses an object key or,
+// for arrays, a zero-based index. An empty path returns v itself.
+//
+// Parameters:
+//   - v: A decoded JSON value made of map[string]any, []any and scalars.
+//   - path: The dot-separated path, such as "data.0.name".
+//
+// Returns:
+//   - any: The value found at path.
+//   - error: An error naming the first segment that could not be resolved.
+func Lookup(v any, path string) (any, error) {
+
+	if path == "" {
+		return v, nil
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("missing key %q", segment)
+			}
+			v = child
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("index %q out of range for array of length %d", segment, len(node))
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("cannot resolve %q in %s", segment, describe(v))
+		}
+	}
+
+	return v, nil
+}
+
+// decode turns a match source into a decoded JSON object and, for recorders, the HTTP status.
+func decode(source any) (map[string]any, *int, error) {
+
+	var (
+		body   []byte
+		status *int
+	)
+
+	switch s := source.(type) {
+	case *httptest.ResponseRecorder:
+		code := s.Code
+		status = &code
+		body = s.Body.Bytes()
+	case []byte:
+		body = s
+	case string:
+		body = []byte(s)
+	case nil:
+		return nil, nil, errors.New("nil source")
+	default:
+		b, err := json.Marshal(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode source: %w", err)
+		}
+		body = b
+	}
+
+	var envelope map[string]any
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("decode envelope: %w", err)
+	}
+
+	return envelope, status, nil
+}
+
+// normalize converts v to its decoded JSON form so it compares equal to values decoded from envelopes.
+func normalize(v any) (any, error) {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// describe renders a decoded value compactly for failure messages.
+func describe(v any) string {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,163 @@
+package rpstest_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/httpresponse"
+	"github.com/zeroxsolutions/go-rps/rpstest"
+)
+
+// fakeTB records failures instead of failing the test, so that matcher failures can be asserted.
+type fakeTB struct {
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// envelope is a decoded response used across tests.
+const envelope = `{"success":true,"message":"ok","code":200,"total":3,"trace_id":"abc",
+	"data":[{"name":"alice","tags":{"role":"admin"}},{"name":"bob"},{"name":"carol"}]}`
+
+// TestExpectation_Match_Pass tests that a fully matching envelope passes all checks.
+func TestExpectation_Match_Pass(t *testing.T) {
+	ok := rpstest.Expect().
+		Success().
+		Message("ok").
+		Code(200).
+		Total(3).
+		DataLen(3).
+		ExtraHas("trace_id").
+		Field("data.0.name", "alice").
+		Field("data.0.tags.role", "admin").
+		Match(t, envelope)
+
+	if !ok {
+		t.Error("Expected Match to return true")
+	}
+}
+
+// TestExpectation_Match_Failures tests that each failing check is reported with its field and values.
+func TestExpectation_Match_Failures(t *testing.T) {
+	fake := new(fakeTB)
+
+	ok := rpstest.Expect().
+		Failure().
+		Code(404).
+		DataLen(2).
+		ExtraHas("request_id").
+		ExtraHas("data").
+		Field("data.1.name", "alice").
+		Match(fake, []byte(envelope))
+
+	if ok {
+		t.Error("Expected Match to return false")
+	}
+
+	expected := []string{
+		`rpstest: field "success": got true, want false`,
+		`rpstest: field "code": got 200, want 404`,
+		`rpstest: field "data": got length 3, want 2`,
+		`rpstest: field "request_id": missing key "request_id"`,
+		`rpstest: field "data": "data" is a core field, not an Extra key`,
+		`rpstest: field "data.1.name": got "bob", want "alice"`,
+	}
+	if len(fake.errors) != len(expected) {
+		t.Fatalf("Expected %d failures, got %d: %v", len(expected), len(fake.errors), fake.errors)
+	}
+	for i := range expected {
+		if fake.errors[i] != expected[i] {
+			t.Errorf("Expected failure %d to be %v, got %v", i, expected[i], fake.errors[i])
+		}
+	}
+}
+
+// TestExpectation_Match_CustomMatcher tests that custom matchers are applied to the resolved value.
+func TestExpectation_Match_CustomMatcher(t *testing.T) {
+	fake := new(fakeTB)
+
+	hasPrefix := func(prefix string) rpstest.Matcher {
+		return func(got any) error {
+			if s, ok := got.(string); !ok || !strings.HasPrefix(s, prefix) {
+				return fmt.Errorf("got %v, want prefix %q", got, prefix)
+			}
+			return nil
+		}
+	}
+
+	rpstest.Expect().Satisfies("data.2.name", hasPrefix("car")).Match(t, envelope)
+	rpstest.Expect().Satisfies("data.2.name", hasPrefix("dav")).Match(fake, envelope)
+
+	if len(fake.errors) != 1 || fake.errors[0] != `rpstest: field "data.2.name": got carol, want prefix "dav"` {
+		t.Errorf("Expected custom matcher failure, got %v", fake.errors)
+	}
+}
+
+// TestExpectation_Match_Recorder tests matching against a recorder, including its status.
+func TestExpectation_Match_Recorder(t *testing.T) {
+	response := &httpresponse.HTTPResponseOptions[int, []string, map[string]interface{}, int]{
+		Success: true,
+		Code:    201,
+		Data:    []string{"a", "b"},
+		Extra:   map[string]interface{}{"trace_id": "abc"},
+	}
+
+	rec := httptest.NewRecorder()
+	if err := httpresponse.WriteJSON(rec, response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rpstest.Expect().Status(201).Success().Code(201).DataLen(2).ExtraHas("trace_id").Match(t, rec)
+
+	// Decoded envelopes are accepted too, but have no HTTP status
+	fake := new(fakeTB)
+	rpstest.Expect().Status(201).Match(fake, response)
+
+	if len(fake.errors) != 1 || !strings.Contains(fake.errors[0], "has no HTTP status") {
+		t.Errorf("Expected missing status failure, got %v", fake.errors)
+	}
+}
+
+// TestLookup tests dot-path resolution across arrays and maps.
+func TestLookup(t *testing.T) {
+	value := map[string]any{
+		"data": []any{
+			map[string]any{"name": "alice", "roles": []any{"admin", "dev"}},
+		},
+	}
+
+	cases := map[string]any{
+		"":               value,
+		"data.0.name":    "alice",
+		"data.0.roles.1": "dev",
+		"data.0.roles":   []any{"admin", "dev"},
+	}
+	for path, expected := range cases {
+		got, err := rpstest.Lookup(value, path)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", path, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected %q to resolve to %v, got %v", path, expected, got)
+		}
+	}
+
+	failures := map[string]string{
+		"data.1.name":      `index "1" out of range for array of length 1`,
+		"data.x":           `index "x" out of range for array of length 1`,
+		"data.0.missing":   `missing key "missing"`,
+		"data.0.name.more": `cannot resolve "more" in "alice"`,
+	}
+	for path, expected := range failures {
+		if _, err := rpstest.Lookup(value, path); err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q for %q, got %v", expected, path, err)
+		}
+	}
+}
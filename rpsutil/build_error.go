@@ -0,0 +1,151 @@
+package rpsutil
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BuildErrorHook is notified of every failed build with the error returned by Build and the name of the
+// built type, such as "httpresponse.HTTPResponseOptions[int,string,...]". Errors raised by configuration
+// functions are *OptionError values locating the failing function, reachable with errors.As.
+type BuildErrorHook func(err error, typeName string)
+
+// BuildErrorCounter is a BuildErrorHook implementation counting build failures per built type.
+// It is safe for concurrent use, and its zero value is ready to use.
+type BuildErrorCounter struct {
+	total  uint64
+	mu     sync.Mutex
+	byType map[string]uint64
+}
+
+// DefaultBuildErrorCounter is the hook notified of build failures until OnBuildError is called.
+var DefaultBuildErrorCounter = &BuildErrorCounter{}
+
+// BuildErrorQueueSize is the number of build failures queued for the build error hook. Failures occurring
+// while the queue is full are dropped and counted by DroppedBuildErrors.
+const BuildErrorQueueSize = 256
+
+var buildErrorHook atomic.Value // BuildErrorHook
+
+// buildErrorEvent is a build failure queued for the hook registered when it occurred.
+type buildErrorEvent struct {
+	hook     BuildErrorHook
+	err      error
+	typeName string
+}
+
+var (
+	buildErrors        = make(chan buildErrorEvent, BuildErrorQueueSize)
+	buildErrorWorker   sync.Once
+	droppedBuildErrors uint64
+)
+
+func init() {
+	buildErrorHook.Store(BuildErrorHook(DefaultBuildErrorCounter.Record))
+}
+
+// OnBuildError replaces the hook notified whenever Build, BuildContext or BuildTimeout returns an error.
+// The hook runs on a single background goroutine, in the order of the failures, so it never delays the build;
+// failures are queued for it up to BuildErrorQueueSize and dropped beyond, so a slow hook loses notifications
+// rather than stalling builds. A panic in the hook is recovered and discarded. A nil hook disables the
+// notifications.
+//
+// Parameters:
+//   - hook: The function notified of each build failure.
+//
+// Example usage:
+//
+//	rpsutil.OnBuildError(func(err error, typeName string) {
+//		buildFailures.WithLabelValues(typeName).Inc()
+//	})
+func OnBuildError(hook BuildErrorHook) {
+
+	if hook == nil {
+		hook = func(error, string) {}
+	}
+
+	buildErrorHook.Store(hook)
+}
+
+// DroppedBuildErrors returns the number of build failures dropped because the queue of the build error hook
+// was full.
+//
+// Returns:
+//   - uint64: The number of failures the hook was not notified of.
+func DroppedBuildErrors() uint64 {
+	return atomic.LoadUint64(&droppedBuildErrors)
+}
+
+// notifyBuildError queues the failed build of T for the build error hook, without blocking.
+func notifyBuildError[T any](err error) {
+
+	buildErrorWorker.Do(func() {
+		go runBuildErrorHooks()
+	})
+
+	select {
+	case buildErrors <- buildErrorEvent{hook: buildErrorHook.Load().(BuildErrorHook), err: err, typeName: typeName[T]()}:
+	default:
+		atomic.AddUint64(&droppedBuildErrors, 1)
+	}
+}
+
+// runBuildErrorHooks notifies the hooks of the queued build failures.
+func runBuildErrorHooks() {
+
+	for event := range buildErrors {
+		callBuildErrorHook(event)
+	}
+}
+
+// callBuildErrorHook notifies the hook of event, recovering from its panics.
+func callBuildErrorHook(event buildErrorEvent) {
+
+	defer func() {
+		_ = recover()
+	}()
+
+	event.hook(event.err, event.typeName)
+}
+
+// Record counts a build failure of the named type. Its signature matches BuildErrorHook, so that
+// counter.Record can be passed to OnBuildError.
+//
+// Parameters:
+//   - err: The build error; it is not retained.
+//   - typeName: The name of the built type.
+func (buildErrorCounter *BuildErrorCounter) Record(err error, typeName string) {
+
+	atomic.AddUint64(&buildErrorCounter.total, 1)
+
+	buildErrorCounter.mu.Lock()
+	defer buildErrorCounter.mu.Unlock()
+
+	if buildErrorCounter.byType == nil {
+		buildErrorCounter.byType = make(map[string]uint64)
+	}
+	buildErrorCounter.byType[typeName]++
+}
+
+// Total returns the number of build failures recorded.
+//
+// Returns:
+//   - uint64: The number of failures of all types.
+func (buildErrorCounter *BuildErrorCounter) Total() uint64 {
+	return atomic.LoadUint64(&buildErrorCounter.total)
+}
+
+// Count returns the number of build failures recorded for the named type.
+//
+// Parameters:
+//   - typeName: The name of the built type.
+//
+// Returns:
+//   - uint64: The number of failures of the type.
+func (buildErrorCounter *BuildErrorCounter) Count(typeName string) uint64 {
+
+	buildErrorCounter.mu.Lock()
+	defer buildErrorCounter.mu.Unlock()
+
+	return buildErrorCounter.byType[typeName]
+}
@@ -0,0 +1,126 @@
+package rpsutil_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// failingConfig is only built by the build error hook tests.
+type failingConfig struct{}
+
+// buildFailing builds a failingConfig whose second configuration function fails with err.
+func buildFailing(err error) {
+	_, _ = rpsutil.Build[failingConfig](&MockLister[failingConfig]{Funcs: []func(*failingConfig) error{
+		func(*failingConfig) error { return nil },
+		func(*failingConfig) error { return err },
+	}})
+}
+
+// TestOnBuildError tests that the hook is notified with the type name and the failing option's indices.
+func TestOnBuildError(t *testing.T) {
+	type failure struct {
+		err      error
+		typeName string
+	}
+	failures := make(chan failure, 1)
+
+	rpsutil.OnBuildError(func(err error, typeName string) {
+		failures <- failure{err: err, typeName: typeName}
+	})
+	defer rpsutil.OnBuildError(rpsutil.DefaultBuildErrorCounter.Record)
+
+	failErr := errors.New("error in function")
+	buildFailing(failErr)
+
+	select {
+	case f := <-failures:
+		if f.typeName != "rpsutil_test.failingConfig" {
+			t.Errorf("Expected type name rpsutil_test.failingConfig, got %s", f.typeName)
+		}
+
+		var optionError *rpsutil.OptionError
+		if !errors.As(f.err, &optionError) || optionError.Index != 1 || !errors.Is(f.err, failErr) {
+			t.Errorf("Expected an OptionError at index 1, got %v", f.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the hook to be notified")
+	}
+}
+
+// TestOnBuildError_Panic tests that a panicking hook does not break Build.
+func TestOnBuildError_Panic(t *testing.T) {
+	notified := make(chan struct{})
+
+	rpsutil.OnBuildError(func(error, string) {
+		close(notified)
+		panic("hook failure")
+	})
+	defer rpsutil.OnBuildError(rpsutil.DefaultBuildErrorCounter.Record)
+
+	failErr := errors.New("error in function")
+	_, err := rpsutil.Build[failingConfig](&MockLister[failingConfig]{Funcs: []func(*failingConfig) error{
+		func(*failingConfig) error { return failErr },
+	}})
+	if !errors.Is(err, failErr) {
+		t.Errorf("Expected the build error, got %v", err)
+	}
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the hook to be notified")
+	}
+}
+
+// TestOnBuildError_BlockingHook tests that a blocking hook delays no build, the failures beyond the queue
+// being dropped and counted.
+func TestOnBuildError_BlockingHook(t *testing.T) {
+	release := make(chan struct{})
+	notified := make(chan struct{}, rpsutil.BuildErrorQueueSize+1)
+
+	rpsutil.OnBuildError(func(error, string) {
+		notified <- struct{}{}
+		<-release
+	})
+	defer rpsutil.OnBuildError(rpsutil.DefaultBuildErrorCounter.Record)
+
+	dropped := rpsutil.DroppedBuildErrors()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < rpsutil.BuildErrorQueueSize+10; i++ {
+			buildFailing(errors.New("error in function"))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the builds not to wait for the hook")
+	}
+	close(release)
+
+	if rpsutil.DroppedBuildErrors() <= dropped {
+		t.Errorf("Expected the failures beyond the queue to be dropped, got %d dropped", rpsutil.DroppedBuildErrors()-dropped)
+	}
+}
+
+// TestBuildErrorCounter tests that the counter counts failures per type.
+func TestBuildErrorCounter(t *testing.T) {
+	counter := &rpsutil.BuildErrorCounter{}
+
+	counter.Record(errors.New("a"), "a.Config")
+	counter.Record(errors.New("b"), "a.Config")
+	counter.Record(errors.New("c"), "b.Config")
+
+	if counter.Total() != 3 {
+		t.Errorf("Expected total 3, got %d", counter.Total())
+	}
+	if counter.Count("a.Config") != 2 || counter.Count("b.Config") != 1 || counter.Count("c.Config") != 0 {
+		t.Errorf("Expected counts 2, 1 and 0, got %d, %d and %d", counter.Count("a.Config"), counter.Count("b.Config"), counter.Count("c.Config"))
+	}
+}
@@ -0,0 +1,49 @@
+package rpsutil
+
+import (
+	"context"
+	"time"
+)
+
+// BuildContext behaves like Build, but checks ctx before applying each configuration function.
+// If ctx is done, BuildContext stops and returns nil and ctx's error, wrapped in an *OptionError
+// locating the first function that was not applied. A function already running is not interrupted.
+//
+// Parameters:
+//   - ctx: The context bounding the build.
+//   - opts: Variadic list of Lister implementations for type T.
+//
+// Returns:
+//   - *T: A pointer to the configured instance of type T.
+//   - error: An error if ctx is done, or any validation or configuration function fails; otherwise, nil.
+func BuildContext[T any](ctx context.Context, opts ...Lister[T]) (*T, error) {
+
+	t, applied, err := build(ctx, opts)
+
+	intercept[T](t, applied, err)
+
+	return t, err
+}
+
+// BuildTimeout behaves like BuildContext with a context that times out after d.
+// If the timeout expires between configuration functions, the returned error wraps context.DeadlineExceeded.
+//
+// Parameters:
+//   - d: The maximum duration of the build.
+//   - opts: Variadic list of Lister implementations for type T.
+//
+// Returns:
+//   - *T: A pointer to the configured instance of type T.
+//   - error: An error if the timeout expires, or any validation or configuration function fails; otherwise, nil.
+//
+// Example usage:
+//
+//	config, err := rpsutil.BuildTimeout(50*time.Millisecond, configOption)
+//	if errors.Is(err, context.DeadlineExceeded) { /* handle timeout */ }
+func BuildTimeout[T any](d time.Duration, opts ...Lister[T]) (*T, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return BuildContext(ctx, opts...)
+}
@@ -0,0 +1,79 @@
+package rpsutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// TestBuildTimeout_Exceeded tests that BuildTimeout stops when an option sleeps past the timeout.
+func TestBuildTimeout_Exceeded(t *testing.T) {
+	type Config struct {
+		Steps int
+	}
+
+	lister := &MockLister[Config]{
+		Funcs: []func(*Config) error{
+			func(c *Config) error { c.Steps++; return nil },
+			func(c *Config) error { time.Sleep(30 * time.Millisecond); c.Steps++; return nil },
+			func(c *Config) error { t.Error("Expected the option after the timeout not to run"); return nil },
+		},
+	}
+
+	result, err := rpsutil.BuildTimeout[Config](10*time.Millisecond, lister)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result, got %+v", result)
+	}
+
+	var optionErr *rpsutil.OptionError
+	if !errors.As(err, &optionErr) || optionErr.Index != 2 {
+		t.Errorf("Expected an OptionError locating option #2, got %v", err)
+	}
+}
+
+// TestBuildTimeout_Success tests that BuildTimeout builds normally within the timeout.
+func TestBuildTimeout_Success(t *testing.T) {
+	type Config struct {
+		Name string
+	}
+
+	lister := &MockLister[Config]{
+		Funcs: []func(*Config) error{
+			func(c *Config) error { c.Name = "ok"; return nil },
+		},
+	}
+
+	result, err := rpsutil.BuildTimeout[Config](time.Second, lister)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Name != "ok" {
+		t.Errorf("Expected Name ok, got %q", result.Name)
+	}
+}
+
+// TestBuildContext_Canceled tests that BuildContext applies no option with an already canceled context.
+func TestBuildContext_Canceled(t *testing.T) {
+	type Config struct {
+		Name string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lister := &MockLister[Config]{
+		Funcs: []func(*Config) error{
+			func(c *Config) error { t.Error("Expected no option to run"); return nil },
+		},
+	}
+
+	if _, err := rpsutil.BuildContext[Config](ctx, lister); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
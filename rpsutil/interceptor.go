@@ -0,0 +1,70 @@
+package rpsutil
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// BuildInfo describes the outcome of a Build call.
+type BuildInfo struct {
+	Type    reflect.Type // The built type T.
+	Value   any          // The built *T; nil if the build failed.
+	Options int          // The number of configuration functions applied, including a failing one.
+	Err     error        // The error returned by Build, if any.
+}
+
+// Interceptor is notified of the outcome of every Build call. Interceptors run synchronously on the
+// building goroutine, so they must be cheap and safe for concurrent use.
+type Interceptor func(info BuildInfo)
+
+var (
+	interceptorsMu sync.Mutex
+	interceptors   atomic.Value // []Interceptor, replaced on every registration
+)
+
+// RegisterInterceptor adds an interceptor notified after every Build call, typically from an init function.
+// When no interceptor is registered, Build incurs no interception cost beyond an atomic load.
+//
+// Parameters:
+//   - interceptor: The function notified of each build outcome.
+func RegisterInterceptor(interceptor Interceptor) {
+
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+
+	current, _ := interceptors.Load().([]Interceptor)
+
+	// Copy on write, so that concurrent builds keep iterating over the previous slice
+	updated := make([]Interceptor, 0, len(current)+1)
+	updated = append(updated, current...)
+	updated = append(updated, interceptor)
+
+	interceptors.Store(updated)
+}
+
+// intercept notifies the registered interceptors, and the build error hook of failures, of a build outcome.
+func intercept[T any](t *T, applied int, err error) {
+
+	if err != nil {
+		notifyBuildError[T](err)
+	}
+
+	registered, _ := interceptors.Load().([]Interceptor)
+	if len(registered) == 0 {
+		return
+	}
+
+	info := BuildInfo{
+		Type:    reflect.TypeOf((*T)(nil)).Elem(),
+		Options: applied,
+		Err:     err,
+	}
+	if t != nil {
+		info.Value = t
+	}
+
+	for _, interceptor := range registered {
+		interceptor(info)
+	}
+}
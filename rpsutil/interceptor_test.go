@@ -0,0 +1,55 @@
+package rpsutil_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// interceptedConfig is only built by the interceptor test, so other tests do not disturb its observations.
+type interceptedConfig struct {
+	Value int
+}
+
+var interceptedBuilds []rpsutil.BuildInfo
+
+func init() {
+	rpsutil.RegisterInterceptor(func(info rpsutil.BuildInfo) {
+		if info.Type == reflect.TypeOf(interceptedConfig{}) {
+			interceptedBuilds = append(interceptedBuilds, info)
+		}
+	})
+}
+
+// TestRegisterInterceptor tests if interceptors observe successful and failed builds.
+func TestRegisterInterceptor(t *testing.T) {
+	interceptedBuilds = nil
+
+	setValue := func(c *interceptedConfig) error {
+		c.Value = 42
+		return nil
+	}
+	failErr := errors.New("error in function")
+	fail := func(*interceptedConfig) error {
+		return failErr
+	}
+
+	config, _ := rpsutil.Build[interceptedConfig](&MockLister[interceptedConfig]{Funcs: []func(*interceptedConfig) error{setValue, nil, setValue}})
+	_, _ = rpsutil.Build[interceptedConfig](&MockLister[interceptedConfig]{Funcs: []func(*interceptedConfig) error{setValue, fail, setValue}})
+
+	if len(interceptedBuilds) != 2 {
+		t.Fatalf("Expected 2 intercepted builds, got %d", len(interceptedBuilds))
+	}
+
+	success := interceptedBuilds[0]
+	if success.Value != config || success.Options != 2 || success.Err != nil {
+		t.Errorf("Expected successful build with 2 options, got %+v", success)
+	}
+
+	failure := interceptedBuilds[1]
+	if failure.Value != nil || failure.Options != 2 || !errors.Is(failure.Err, failErr) {
+		t.Errorf("Expected failed build after 2 options, got %+v", failure)
+	}
+}
@@ -0,0 +1,128 @@
+package rpsutil
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// OptionError is returned by Build when a configuration function fails. It wraps the function's error
+// with the built type, the position of the function and, if labels are enabled, the name of the setter that
+// queued it. Failing functions are always wrapped, labels or not; errors.Is and errors.As match the wrapped
+// error.
+type OptionError struct {
+	Type   string // Name of the built type, as written in Go source and qualified by package name only.
+	Lister int    // Position of the option provider among the options passed to Build.
+	Index  int    // Position of the failing function in the provider's List.
+	Label  string // Name of the setter that queued the function; empty unless labels are enabled.
+	Err    error  // The error returned by the function.
+}
+
+// Error formats the error as "building pkg.Config: option #3 (SetData): ...", with the provider position
+// before the option position when the failing function does not belong to the first provider.
+func (optionError *OptionError) Error() string {
+
+	var sb strings.Builder
+
+	if optionError.Type != "" {
+		fmt.Fprintf(&sb, "building %s: ", optionError.Type)
+	}
+
+	if optionError.Lister > 0 {
+		fmt.Fprintf(&sb, "lister #%d ", optionError.Lister)
+	}
+
+	fmt.Fprintf(&sb, "option #%d", optionError.Index)
+
+	if optionError.Label != "" {
+		fmt.Fprintf(&sb, " (%s)", optionError.Label)
+	}
+
+	fmt.Fprintf(&sb, ": %v", optionError.Err)
+
+	return sb.String()
+}
+
+// Unwrap returns the error of the failing function, so that errors.Is and errors.As see through OptionError.
+func (optionError *OptionError) Unwrap() error {
+	return optionError.Err
+}
+
+var optionLabels int32
+
+// EnableOptionLabels enables or disables setter labels in OptionError, typically in development.
+// Labels are derived from the runtime name of the failing function, so they cost nothing until a build fails;
+// they are disabled by default because the function name lookup is comparatively slow.
+//
+// Parameters:
+//   - enabled: A boolean enabling (true) or disabling (false) labels.
+func EnableOptionLabels(enabled bool) {
+
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&optionLabels, v)
+}
+
+// newOptionError wraps err, returned by the function fn at the given position of a build of T.
+func newOptionError[T any](lister, index int, fn any, err error) *OptionError {
+
+	optionError := &OptionError{Type: typeName[T](), Lister: lister, Index: index, Err: err}
+
+	if atomic.LoadInt32(&optionLabels) == 1 {
+		optionError.Label = functionLabel(fn)
+	}
+
+	return optionError
+}
+
+// functionLabel derives a readable label from the runtime name of a function. Closures are named after
+// the function that created them, so an option queued by a setter is labeled with the setter's name,
+// e.g. "pkg.(*Builder[...]).SetData.func1" yields "SetData".
+func functionLabel(fn any) string {
+
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	segments := strings.Split(name, ".")
+
+	// Drop the closure suffixes ("func1", "1", ...) to reach the enclosing function
+	for len(segments) > 1 && isClosureSegment(segments[len(segments)-1]) {
+		segments = segments[:len(segments)-1]
+	}
+
+	label := segments[len(segments)-1]
+	if i := strings.Index(label, "["); i >= 0 {
+		label = label[:i]
+	}
+
+	return label
+}
+
+// isClosureSegment reports whether a segment of a function name denotes a closure, such as "func1" or "2".
+func isClosureSegment(segment string) bool {
+
+	digits := strings.TrimPrefix(segment, "func")
+	if digits == "" {
+		return false
+	}
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,7 +2,11 @@
 // It defines a `Lister` interface and a generic `Build` function for assembling a type with customizable options.
 package rpsutil
 
-import "reflect"
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
 
 // Lister is a generic interface that represents a type that provides a list of configuration functions for type T.
 // Each configuration function takes a pointer to T and applies specific settings to it.
@@ -12,16 +16,90 @@ type Lister[T any] interface {
 	List() []func(*T) error
 }
 
+// ValidatingLister is a Lister that can check its own queued configuration functions before they are applied.
+// Build calls ValidateList on every option provider implementing this interface before applying any function,
+// which lets builders detect problems such as mutually exclusive setters up front.
+type ValidatingLister[T any] interface {
+	Lister[T]
+
+	// ValidateList reports whether the queued configuration functions can be applied.
+	// A non-nil error aborts Build before any function runs.
+	ValidateList() error
+}
+
+// mappedLister is a Lister whose configuration functions are transformed versions of another Lister's functions.
+type mappedLister[T any] struct {
+	lister Lister[T]
+	f      func(func(*T) error) func(*T) error
+}
+
+// Map returns a Lister that wraps each configuration function of l with f, enabling cross-cutting
+// instrumentation such as logging or timing around the application of every option.
+// The functions of l are retrieved and wrapped each time List is called, so options added to l
+// after Map is called are included. Nil functions are passed through unwrapped, and validation of a
+// ValidatingLister is forwarded.
+//
+// Parameters:
+//   - l: The Lister whose configuration functions are wrapped; a nil Lister yields no functions.
+//   - f: The transformation applied to each configuration function.
+//
+// Returns:
+//   - Lister[T]: A Lister providing the wrapped configuration functions.
+//
+// Example usage:
+//
+//	counted := rpsutil.Map(builder, func(next func(*Config) error) func(*Config) error {
+//		return func(c *Config) error { calls++; return next(c) }
+//	})
+func Map[T any](l Lister[T], f func(func(*T) error) func(*T) error) Lister[T] {
+	return &mappedLister[T]{lister: l, f: f}
+}
+
+// List returns the wrapped configuration functions of the underlying Lister.
+func (mappedLister *mappedLister[T]) List() []func(*T) error {
+
+	if mappedLister.lister == nil || reflect.ValueOf(mappedLister.lister).IsNil() {
+		return nil
+	}
+
+	funcs := mappedLister.lister.List()
+
+	mapped := make([]func(*T) error, len(funcs))
+	for i, fn := range funcs {
+		if fn == nil {
+			continue
+		}
+		mapped[i] = mappedLister.f(fn)
+	}
+
+	return mapped
+}
+
+// ValidateList forwards validation to the underlying Lister if it implements ValidatingLister.
+func (mappedLister *mappedLister[T]) ValidateList() error {
+
+	if validatingLister, ok := mappedLister.lister.(ValidatingLister[T]); ok && !reflect.ValueOf(validatingLister).IsNil() {
+		return validatingLister.ValidateList()
+	}
+
+	return nil
+}
+
 // Build creates a new instance of type T and applies all configuration functions provided by Lister options.
 // It iterates over each option in opts and applies the contained functions to the new instance of T.
-// If any configuration function returns an error, Build immediately returns nil and the encountered error.
+// If any configuration function returns an error, Build immediately returns nil and the encountered error,
+// wrapped in an *OptionError locating the failing function. The error is wrapped whether or not option labels
+// are enabled (see EnableOptionLabels), so it no longer compares equal to the function's error: use errors.Is
+// or errors.As, which see through the wrapper.
+// Option providers implementing ValidatingLister are validated first; if any validation fails, no function is applied.
+// Registered interceptors are notified of the outcome before Build returns, and the build error hook of a failure (see OnBuildError).
 //
 // Parameters:
 //   - opts: Variadic list of Lister implementations for type T, each containing a list of functions that modify T.
 //
 // Returns:
 //   - *T: A pointer to the configured instance of type T.
-//   - error: An error if any configuration function fails; otherwise, nil.
+//   - error: An error if any validation or configuration function fails; otherwise, nil.
 //
 // Example usage:
 //
@@ -30,26 +108,79 @@ type Lister[T any] interface {
 //	if err != nil { /* handle error */ }
 func Build[T any](opts ...Lister[T]) (*T, error) {
 
-	t := new(T)
+	t, applied, err := build(context.Background(), opts)
+
+	intercept[T](t, applied, err)
+
+	return t, err
+}
+
+// MustBuild is like Build but panics if the build fails, typically to build fixed responses at program
+// initialization. The panic value is an error wrapping the build error and naming T.
+//
+// Parameters:
+//   - opts: Variadic list of Lister implementations for type T.
+//
+// Returns:
+//   - *T: A pointer to the configured instance of type T.
+func MustBuild[T any](opts ...Lister[T]) *T {
+
+	t, err := Build[T](opts...)
+	if err != nil {
+		// An *OptionError already names the built type
+		if _, ok := err.(*OptionError); ok {
+			panic(fmt.Errorf("rpsutil: %w", err))
+		}
+		panic(fmt.Errorf("rpsutil: building %s: %w", typeName[T](), err))
+	}
+
+	return t
+}
+
+// build validates and applies opts to a new instance of T, also returning the number of configuration functions applied.
+// It stops with ctx's error, wrapped in an *OptionError, if ctx is done before a configuration function is applied.
+func build[T any](ctx context.Context, opts []Lister[T]) (*T, int, error) {
 
 	for _, opt := range opts {
 		if opt == nil || reflect.ValueOf(opt).IsNil() {
 			continue
 		}
 
-		for _, setArgs := range opt.List() {
+		if validatingOpt, ok := opt.(ValidatingLister[T]); ok {
+			if err := validatingOpt.ValidateList(); err != nil {
+				return nil, 0, err
+			}
+		}
+
+	}
+
+	t := new(T)
+	applied := 0
+
+	for listerIndex, opt := range opts {
+		if opt == nil || reflect.ValueOf(opt).IsNil() {
+			continue
+		}
+
+		for optionIndex, setArgs := range opt.List() {
 
 			if setArgs == nil {
 				continue
 			}
 
+			if err := ctx.Err(); err != nil {
+				return nil, applied, newOptionError[T](listerIndex, optionIndex, setArgs, err)
+			}
+
+			applied++
+
 			if err := setArgs(t); err != nil {
-				return nil, err
+				return nil, applied, newOptionError[T](listerIndex, optionIndex, setArgs, err)
 			}
 
 		}
 
 	}
 
-	return t, nil
+	return t, applied, nil
 }
@@ -170,3 +170,185 @@ func TestBuild_EmptyOptions(t *testing.T) {
 		t.Errorf("Expected config.Value to be 0, got %d", config.Value)
 	}
 }
+
+// MockValidatingLister is a mock implementation of the ValidatingLister interface for testing purposes.
+type MockValidatingLister[T any] struct {
+	MockLister[T]
+	Err error
+}
+
+// ValidateList returns the configured validation error.
+func (m *MockValidatingLister[T]) ValidateList() error {
+	return m.Err
+}
+
+// TestBuild_ValidationFailure tests if Build aborts without applying any option when a provider fails its own validation.
+func TestBuild_ValidationFailure(t *testing.T) {
+	type Config struct {
+		Value int
+	}
+
+	applied := 0
+	setValue := func(value int) func(*Config) error {
+		return func(c *Config) error {
+			applied++
+			c.Value = value
+			return nil
+		}
+	}
+
+	validationErr := errors.New("conflicting options")
+
+	// The first provider is valid, the second fails its own validation
+	mockLister := &MockLister[Config]{Funcs: []func(*Config) error{setValue(1)}}
+	mockValidatingLister := &MockValidatingLister[Config]{
+		MockLister: MockLister[Config]{Funcs: []func(*Config) error{setValue(42)}},
+		Err:        validationErr,
+	}
+
+	config, err := rpsutil.Build[Config](mockLister, mockValidatingLister)
+	if !errors.Is(err, validationErr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if config != nil {
+		t.Errorf("Expected config to be nil, got %v", config)
+	}
+
+	// Verify that validation happened before any option was applied
+	if applied != 0 {
+		t.Errorf("Expected no option to be applied, got %d", applied)
+	}
+}
+
+// TestBuild_ValidationSuccess tests if Build applies options of a provider that passes its own validation.
+func TestBuild_ValidationSuccess(t *testing.T) {
+	type Config struct {
+		Value int
+	}
+
+	mockValidatingLister := &MockValidatingLister[Config]{
+		MockLister: MockLister[Config]{Funcs: []func(*Config) error{func(c *Config) error {
+			c.Value = 42
+			return nil
+		}}},
+	}
+
+	config, err := rpsutil.Build[Config](mockValidatingLister)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.Value != 42 {
+		t.Errorf("Expected config.Value to be 42, got %d", config.Value)
+	}
+}
+
+// TestMap_CountsInvocations tests if Map wraps every option so that invocations can be counted.
+func TestMap_CountsInvocations(t *testing.T) {
+	type Config struct {
+		Value int
+	}
+
+	setValue := func(value int) func(*Config) error {
+		return func(c *Config) error {
+			c.Value += value
+			return nil
+		}
+	}
+
+	mockLister := &MockLister[Config]{Funcs: []func(*Config) error{setValue(10), nil, setValue(15)}}
+
+	calls := 0
+	counted := rpsutil.Map[Config](mockLister, func(next func(*Config) error) func(*Config) error {
+		return func(c *Config) error {
+			calls++
+			return next(c)
+		}
+	})
+
+	// Options added after wrapping are wrapped as well
+	mockLister.Funcs = append(mockLister.Funcs, setValue(5))
+
+	config, err := rpsutil.Build[Config](counted)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.Value != 30 {
+		t.Errorf("Expected config.Value to be 30, got %d", config.Value)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 wrapped invocations, got %d", calls)
+	}
+}
+
+// TestMap_NilLister tests if Map tolerates a nil Lister.
+func TestMap_NilLister(t *testing.T) {
+	type Config struct {
+		Value int
+	}
+
+	var mockLister *MockLister[Config]
+	identity := func(next func(*Config) error) func(*Config) error { return next }
+
+	config, err := rpsutil.Build[Config](rpsutil.Map[Config](mockLister, identity))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.Value != 0 {
+		t.Errorf("Expected config.Value to be 0, got %d", config.Value)
+	}
+}
+
+// TestBuild_OptionError tests if Build wraps a failing function's error with its position and, when enabled, its label.
+func TestBuild_OptionError(t *testing.T) {
+	type Config struct {
+		Value int
+	}
+
+	errFunction := errors.New("error in function")
+	errFunc := func(*Config) error {
+		return errFunction
+	}
+	noop := func(*Config) error {
+		return nil
+	}
+
+	mockLister1 := &MockLister[Config]{Funcs: []func(*Config) error{noop}}
+	mockLister2 := &MockLister[Config]{Funcs: []func(*Config) error{noop, nil, noop, errFunc}}
+
+	_, err := rpsutil.Build[Config](mockLister1, mockLister2)
+
+	var optionErr *rpsutil.OptionError
+	if !errors.As(err, &optionErr) {
+		t.Fatalf("Expected *OptionError, got %T", err)
+	}
+	if optionErr.Lister != 1 || optionErr.Index != 3 || optionErr.Label != "" {
+		t.Errorf("Expected lister 1, option 3 and no label, got %+v", optionErr)
+	}
+	if err.Error() != "building rpsutil_test.Config: lister #1 option #3: error in function" {
+		t.Errorf("Expected error message to include the type and the position, got %v", err)
+	}
+	if !errors.Is(err, errFunction) {
+		t.Errorf("Expected the wrapped error to match the function's error, got %v", err)
+	}
+
+	rpsutil.EnableOptionLabels(true)
+	defer rpsutil.EnableOptionLabels(false)
+
+	_, err = rpsutil.Build[labeledConfig](&MockLister[labeledConfig]{Funcs: []func(*labeledConfig) error{failingSetter()}})
+	if err == nil || err.Error() != "building rpsutil_test.labeledConfig: option #0 (failingSetter): setter failed" {
+		t.Errorf("Expected labeled error message, got %v", err)
+	}
+}
+
+// labeledConfig is configured by failingSetter.
+type labeledConfig struct {
+	Value int
+}
+
+// failingSetter returns a configuration function that fails, like a builder setter would queue.
+func failingSetter() func(*labeledConfig) error {
+	return func(*labeledConfig) error {
+		return errors.New("setter failed")
+	}
+}
@@ -0,0 +1,17 @@
+package rpsutil
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// importPathPattern matches the import path qualifying a package name, such as "github.com/org/repo/" in
+// "github.com/org/repo/pkg.Type".
+var importPathPattern = regexp.MustCompile(`(?:[\w.\-~]+/)+`)
+
+// typeName returns the name of T as written in Go source, qualified by package name only, such as
+// "httpresponse.HTTPResponseOptions[int,string,map[string]interface {},int]". The name is deterministic,
+// so it can be used as a metric label.
+func typeName[T any]() string {
+	return importPathPattern.ReplaceAllString(reflect.TypeOf((*T)(nil)).Elem().String(), "")
+}
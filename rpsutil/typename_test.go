@@ -0,0 +1,69 @@
+package rpsutil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zeroxsolutions/go-rps/rpsutil"
+)
+
+// genericConfig is a generic type configured by MustBuild.
+type genericConfig[K comparable, V any] struct {
+	Values map[K]V
+}
+
+// TestMustBuild tests that MustBuild returns the built instance.
+func TestMustBuild(t *testing.T) {
+	config := rpsutil.MustBuild[labeledConfig](&MockLister[labeledConfig]{Funcs: []func(*labeledConfig) error{
+		func(c *labeledConfig) error { c.Value = 7; return nil },
+	}})
+
+	if config.Value != 7 {
+		t.Errorf("Expected value 7, got %d", config.Value)
+	}
+}
+
+// TestMustBuild_Panic tests that MustBuild panics with the build error and the readable name of a generic type.
+func TestMustBuild_Panic(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok {
+			t.Fatalf("Expected an error panic, got %v", err)
+		}
+
+		if !errors.Is(err, errBroken) {
+			t.Errorf("Expected the build error, got %v", err)
+		}
+
+		want := "rpsutil: building rpsutil_test.genericConfig[string,map[string]interface {}]: option #0: broken"
+		if err.Error() != want {
+			t.Errorf("Expected %s, got %s", want, err.Error())
+		}
+	}()
+
+	rpsutil.MustBuild[genericConfig[string, map[string]interface{}]](&MockLister[genericConfig[string, map[string]interface{}]]{
+		Funcs: []func(*genericConfig[string, map[string]interface{}]) error{
+			func(*genericConfig[string, map[string]interface{}]) error { return errBroken },
+		},
+	})
+}
+
+// TestMustBuild_QualifiedTypeArguments tests that type arguments are qualified by package name only.
+func TestMustBuild_QualifiedTypeArguments(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+
+		want := "rpsutil: building rpsutil_test.genericConfig[int,*rpsutil_test.labeledConfig]: option #0: broken"
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected %s, got %v", want, err)
+		}
+	}()
+
+	rpsutil.MustBuild[genericConfig[int, *labeledConfig]](&MockLister[genericConfig[int, *labeledConfig]]{
+		Funcs: []func(*genericConfig[int, *labeledConfig]) error{
+			func(*genericConfig[int, *labeledConfig]) error { return errors.New("broken") },
+		},
+	})
+}